@@ -6,11 +6,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 	"user-service/internal/database"
+	"user-service/internal/encryption"
 	"user-service/internal/handlers"
+	"user-service/internal/jobs"
 	"user-service/internal/middleware"
+	"user-service/internal/models"
+	"user-service/internal/servicecall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -34,6 +39,16 @@ func main() {
 	}
 	defer database.CloseRedis()
 
+	// Fail fast if PII encryption keys are missing or invalid
+	if _, err := encryption.ActiveKeyID(); err != nil {
+		log.Fatal("Failed to load encryption keys:", err)
+	}
+
+	// Initialize client for service-to-service calls
+	if err := servicecall.Init(); err != nil {
+		log.Fatal("Failed to initialize service client:", err)
+	}
+
 	// Setup Gin router
 	if os.Getenv("GO_ENV") == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -53,6 +68,9 @@ func main() {
 		})
 	})
 
+	// Readiness endpoint, polled by orchestration and the admin health aggregator
+	r.GET("/readyz", handlers.Readyz)
+
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 	{
@@ -62,7 +80,8 @@ func main() {
 			auth.POST("/register", handlers.Register)
 			auth.POST("/login", handlers.Login)
 			auth.POST("/refresh", handlers.RefreshToken)
-			auth.POST("/logout", middleware.AuthMiddleware(), handlers.Logout)
+			auth.POST("/session/refresh", handlers.SessionRefresh)
+			auth.POST("/logout", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.Logout)
 			auth.POST("/verify-email", handlers.VerifyEmail)
 			auth.POST("/forgot-password", handlers.ForgotPassword)
 			auth.POST("/reset-password", handlers.ResetPassword)
@@ -72,27 +91,337 @@ func main() {
 		users := v1.Group("/users")
 		users.Use(middleware.AuthMiddleware())
 		{
-			users.GET("/profile", handlers.GetProfile)
-			users.PUT("/profile", handlers.UpdateProfile)
-			users.DELETE("/account", handlers.DeleteAccount)
-			users.PUT("/password", handlers.ChangePassword)
-			users.GET("/subscription", handlers.GetSubscription)
-			users.POST("/subscription/upgrade", handlers.UpgradeSubscription)
+			users.GET("/profile", middleware.RequireScope(models.ScopeProfileRead), handlers.GetProfile)
+			users.PUT("/profile", middleware.RequireScope(models.ScopeProfileWrite), handlers.UpdateProfile)
+			users.DELETE("/account", middleware.RequireFirstParty(), handlers.DeleteAccount)
+			users.PUT("/password", middleware.RequireFirstParty(), handlers.ChangePassword)
+			users.PUT("/email", middleware.RequireFirstParty(), handlers.ChangeEmail)
+			users.GET("/identity-history", middleware.RequireScope(models.ScopeProfileRead), handlers.GetIdentityHistory)
+			users.POST("/verification", middleware.RequireFirstParty(), handlers.SubmitVerificationRequest)
+			users.GET("/verification", middleware.RequireScope(models.ScopeProfileRead), handlers.GetVerificationStatus)
+			users.GET("/reminders", middleware.RequireScope(models.ScopePracticeRead), handlers.ListReminders)
+			users.POST("/reminders", middleware.RequireFirstParty(), handlers.CreateReminder)
+			users.PUT("/reminders/:id", middleware.RequireFirstParty(), handlers.UpdateReminder)
+			users.DELETE("/reminders/:id", middleware.RequireFirstParty(), handlers.DeleteReminder)
+			users.POST("/reminders/:id/snooze", middleware.RequireFirstParty(), handlers.SnoozeReminder)
+			users.POST("/reminders/:id/skip-week", middleware.RequireFirstParty(), handlers.SkipReminderWeek)
+			users.GET("/calendar-feed", middleware.RequireFirstParty(), handlers.GetCalendarFeed)
+			users.POST("/calendar-feed/reset", middleware.RequireFirstParty(), handlers.ResetCalendarFeed)
+			users.GET("/privacy", middleware.RequireScope(models.ScopeProfileRead), handlers.GetPrivacySettings)
+			users.PUT("/privacy", middleware.RequireFirstParty(), handlers.UpdatePrivacySettings)
+			users.GET("/subscription", middleware.RequireScope(models.ScopeBillingRead), handlers.GetSubscription)
+			users.GET("/notifications", middleware.RequireFirstParty(), handlers.GetJobNotificationSettings)
+			users.PUT("/notifications", middleware.RequireFirstParty(), handlers.UpdateJobNotificationSettings)
+			users.GET("/activity", middleware.RequireScope(models.ScopeProfileRead), handlers.GetActivity)
+			users.POST("/api-keys", middleware.RequireFirstParty(), handlers.CreateAPIKey)
+			users.GET("/api-keys", middleware.RequireFirstParty(), handlers.ListAPIKeys)
+			users.DELETE("/api-keys/:id", middleware.RequireFirstParty(), handlers.RevokeAPIKey)
+			users.POST("/api-keys/:id/signing-secret", middleware.RequireFirstParty(), handlers.RotateAPIKeySigningSecret)
+			users.GET("/api-keys/:id/usage", middleware.RequireFirstParty(), handlers.GetAPIKeyUsage)
+			users.POST("/subscription/upgrade", middleware.RequireFirstParty(), handlers.UpgradeSubscription)
+			users.GET("/assessment/quiz", middleware.RequireFirstParty(), handlers.GetPlacementQuiz)
+			users.POST("/assessment/quiz", middleware.RequireFirstParty(), handlers.SubmitPlacementQuiz)
+			users.GET("/assessment", middleware.RequireScope(models.ScopeProfileRead), handlers.GetAssessmentHistory)
+			users.GET("/recommended-scores", middleware.RequireScope(models.ScopeScoresRead), handlers.GetRecommendedScores)
+			users.GET("/analytics", middleware.RequireScope(models.ScopeScoresRead), handlers.GetCreatorAnalytics)
+			users.GET("/analytics/scores", middleware.RequireScope(models.ScopeScoresRead), handlers.GetCreatorScoreAnalytics)
+			users.GET("/experiments", middleware.RequireFirstParty(), handlers.GetExperimentAssignments)
+			users.POST("/experiments/:key/exposure", middleware.RequireFirstParty(), handlers.LogExperimentExposure)
+			users.GET("/support/tickets", middleware.RequireFirstParty(), handlers.ListSupportTickets)
+			users.POST("/support/tickets", middleware.RequireFirstParty(), handlers.CreateSupportTicket)
+			users.GET("/support/tickets/:id", middleware.RequireFirstParty(), handlers.GetSupportTicket)
+			users.POST("/support/tickets/:id/messages", middleware.RequireFirstParty(), handlers.ReplySupportTicket)
+			users.POST("/support/tickets/:id/close", middleware.RequireFirstParty(), handlers.CloseSupportTicket)
+			users.POST("/uploads/credentials", middleware.RequireScope(models.ScopeMediaWrite), handlers.IssueUploadCredential)
+		}
+
+		// The current user's score library
+		scores := v1.Group("/scores")
+		scores.Use(middleware.AuthMiddleware())
+		{
+			scores.GET("", middleware.RequireScope(models.ScopeScoresRead), handlers.ListLibraryScores)
+			scores.GET("/views", middleware.RequireScope(models.ScopeScoresRead), handlers.ListLibraryViews)
+			scores.POST("/views", middleware.RequireFirstParty(), handlers.CreateLibraryView)
+			scores.PUT("/views/:id", middleware.RequireFirstParty(), handlers.UpdateLibraryView)
+			scores.DELETE("/views/:id", middleware.RequireFirstParty(), handlers.DeleteLibraryView)
+			scores.GET("/playlists", middleware.RequireScope(models.ScopeScoresRead), handlers.ListSmartPlaylists)
+			scores.GET("/playlists/:id", middleware.RequireScope(models.ScopeScoresRead), handlers.GetSmartPlaylist)
+			scores.POST("/playlists", middleware.RequireFirstParty(), handlers.CreateSmartPlaylist)
+			scores.PUT("/playlists/:id", middleware.RequireFirstParty(), handlers.UpdateSmartPlaylist)
+			scores.DELETE("/playlists/:id", middleware.RequireFirstParty(), handlers.DeleteSmartPlaylist)
+			scores.GET("/duplicates", middleware.RequireScope(models.ScopeScoresRead), handlers.ListDuplicateScores)
+			scores.GET("/trash", middleware.RequireScope(models.ScopeScoresRead), handlers.ListScoreTrash)
+			scores.POST("/import/abc", middleware.RequireScope(models.ScopeScoresWrite), handlers.ImportABCScore)
+			scores.POST("/export", middleware.RequireScope(models.ScopeScoresRead), handlers.CreateScoreExport)
+			scores.GET("/exports/:id", middleware.RequireScope(models.ScopeScoresRead), handlers.GetScoreExport)
+			scores.GET("/:id/download", middleware.RequireScope(models.ScopeScoresRead), handlers.DownloadScoreAudio)
+			scores.GET("/:id/abc", middleware.RequireScope(models.ScopeScoresRead), handlers.GetScoreABC)
+			scores.DELETE("/:id", middleware.RequireScope(models.ScopeScoresWrite), handlers.DeleteScore)
+			scores.POST("/:id/restore", middleware.RequireScope(models.ScopeScoresWrite), handlers.RestoreScore)
+			scores.POST("/:id/merge", middleware.RequireFirstParty(), handlers.MergeScores)
+			scores.GET("/:id/fingerings", middleware.RequireScope(models.ScopeScoresRead), handlers.GetScoreFingerings)
+			scores.PUT("/:id/fingerings", middleware.RequireScope(models.ScopeScoresWrite), handlers.UpdateScoreFingerings)
+			scores.POST("/:id/fingerings/suggest", middleware.RequireScope(models.ScopeScoresRead), handlers.SuggestScoreFingerings)
+			scores.GET("/:id/difficulty", middleware.RequireScope(models.ScopeScoresRead), handlers.GetScoreDifficulty)
+			scores.PUT("/:id/difficulty", middleware.RequireScope(models.ScopeScoresWrite), handlers.UpdateScoreDifficulty)
+			scores.GET("/:id/license", middleware.RequireScope(models.ScopeScoresRead), handlers.GetScoreLicense)
+			scores.PUT("/:id/license", middleware.RequireScope(models.ScopeScoresWrite), handlers.UpdateScoreLicense)
+			scores.PUT("/:id/visibility", middleware.RequireScope(models.ScopeScoresWrite), handlers.UpdateScoreVisibility)
+			scores.GET("/:id/translations", middleware.RequireScope(models.ScopeScoresRead), handlers.ListScoreTranslations)
+			scores.PUT("/:id/translations/:locale", middleware.RequireScope(models.ScopeScoresWrite), handlers.PutScoreTranslation)
+			scores.DELETE("/:id/translations/:locale", middleware.RequireScope(models.ScopeScoresWrite), handlers.DeleteScoreTranslation)
+		}
+
+		// Albums grouping the user's scores into releases
+		albums := v1.Group("/albums")
+		albums.Use(middleware.AuthMiddleware())
+		{
+			albums.GET("", middleware.RequireScope(models.ScopeScoresRead), handlers.ListAlbums)
+			albums.POST("", middleware.RequireScope(models.ScopeScoresWrite), handlers.CreateAlbum)
+			albums.GET("/:id", middleware.RequireScope(models.ScopeScoresRead), handlers.GetAlbum)
+			albums.PUT("/:id", middleware.RequireScope(models.ScopeScoresWrite), handlers.UpdateAlbum)
+			albums.DELETE("/:id", middleware.RequireScope(models.ScopeScoresWrite), handlers.DeleteAlbum)
+			albums.PUT("/:id/tracks", middleware.RequireScope(models.ScopeScoresWrite), handlers.SetAlbumTracks)
+			albums.PUT("/:id/visibility", middleware.RequireScope(models.ScopeScoresWrite), handlers.UpdateAlbumVisibility)
+		}
+
+		// Artists the user follows
+		artists := v1.Group("/artists")
+		artists.Use(middleware.AuthMiddleware())
+		artists.Use(middleware.RequireFirstParty())
+		{
+			artists.GET("/following", handlers.ListFollowedArtists)
+			artists.POST("/:id/follow", handlers.FollowArtist)
+			artists.DELETE("/:id/follow", handlers.UnfollowArtist)
+		}
+
+		// Score export archives, authenticated by URL signature
+		v1.GET("/exports/:id/download", handlers.DownloadScoreExport)
+
+		// Public profiles
+		v1.GET("/profiles/:username", middleware.OptionalAuthMiddleware(), handlers.GetPublicProfile)
+
+		// What's-new announcements with per-user seen tracking
+		announcements := v1.Group("/announcements")
+		announcements.Use(middleware.AuthMiddleware())
+		announcements.Use(middleware.RequireFirstParty())
+		{
+			announcements.GET("", handlers.ListAnnouncements)
+			announcements.GET("/unseen-count", handlers.GetUnseenAnnouncementCount)
+			announcements.POST("/seen", handlers.MarkAnnouncementsSeen)
+		}
+
+		// Batched client telemetry, forwarded into the analytics pipeline
+		v1.POST("/telemetry", middleware.AuthMiddleware(), middleware.RequireFirstParty(), middleware.TelemetryRateLimitMiddleware(), handlers.IngestTelemetry)
+
+		// Anonymous frontend error reports, rate limited per IP
+		v1.POST("/errors", middleware.AnonymousRateLimitMiddleware(), handlers.SubmitClientError)
+
+		// Public service status for the status page and degradation banners
+		v1.GET("/status", handlers.GetServiceStatus)
+		v1.GET("/status/history", handlers.GetIncidentHistory)
+
+		// In-app feedback
+		v1.POST("/feedback", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.SubmitFeedback)
+
+		// Practice leaderboards
+		v1.GET("/leaderboards", middleware.AuthMiddleware(), middleware.RequireScope(models.ScopePracticeRead), handlers.GetLeaderboard)
+
+		// Daily and weekly practice challenges
+		challenges := v1.Group("/challenges")
+		challenges.Use(middleware.AuthMiddleware())
+		{
+			challenges.GET("", middleware.RequireScope(models.ScopePracticeRead), handlers.ListChallenges)
+			challenges.GET("/:id", middleware.RequireScope(models.ScopePracticeRead), handlers.GetChallenge)
+			challenges.POST("/:id/enroll", middleware.RequireFirstParty(), handlers.EnrollChallenge)
+			challenges.GET("/:id/submissions", middleware.RequireScope(models.ScopePracticeRead), handlers.ListMyChallengeSubmissions)
+			challenges.POST("/:id/submissions", middleware.RequireFirstParty(), handlers.SubmitChallenge)
+		}
+
+		// Community song requests board
+		songRequests := v1.Group("/song-requests")
+		songRequests.Use(middleware.AuthMiddleware())
+		songRequests.Use(middleware.RequireFirstParty())
+		{
+			songRequests.GET("", handlers.ListSongRequests)
+			songRequests.POST("", handlers.CreateSongRequest)
+			songRequests.GET("/:id", handlers.GetSongRequest)
+			songRequests.POST("/:id/vote", handlers.VoteSongRequest)
+			songRequests.DELETE("/:id/vote", handlers.UnvoteSongRequest)
+		}
+
+		// Read-only content for anonymous visitors, cached in Redis and at the CDN
+		anon := v1.Group("/public")
+		anon.Use(middleware.AnonymousRateLimitMiddleware())
+		anon.Use(middleware.PublicCacheMiddleware())
+		{
+			anon.GET("/scores", handlers.ListPublicScores)
+			anon.GET("/scores/:id", handlers.GetPublicScore)
+			anon.GET("/profiles/:username", handlers.GetPublicProfile)
+			anon.GET("/artists/:id", handlers.GetArtistPage)
+			anon.GET("/albums/:id", handlers.GetPublicAlbum)
+			anon.GET("/search", handlers.SearchPublicContent)
+		}
+
+		// Atom and RSS feeds of public scores (feed.atom or feed.rss)
+		feeds := v1.Group("/feeds")
+		{
+			feeds.GET("/scores/:file", handlers.ServeSiteFeed)
+			feeds.GET("/users/:username/:file", handlers.ServeUserFeed)
+			feeds.GET("/artists/:artist/:file", handlers.ServeArtistFeed)
+		}
+
+		// ICS calendar subscriptions, authenticated by URL signature
+		v1.GET("/calendar/:id/feed.ics", handlers.ServeCalendarFeed)
+
+		// Developer routes for third-party application registration
+		developer := v1.Group("/developer")
+		developer.Use(middleware.AuthMiddleware())
+		developer.Use(middleware.RequireFirstParty())
+		{
+			developer.POST("/apps", handlers.RegisterApp)
+			developer.GET("/apps", handlers.ListApps)
+			developer.GET("/apps/:id", handlers.GetApp)
+			developer.PUT("/apps/:id", handlers.UpdateApp)
+			developer.DELETE("/apps/:id", handlers.DeleteApp)
+			developer.POST("/apps/:id/rotate-secret", handlers.RotateAppSecret)
+		}
+
+		// Jam rooms: WebRTC signaling only, audio stays peer to peer
+		jam := v1.Group("/jam")
+		{
+			jam.GET("/ws", handlers.JamWebSocket) // authenticated by single-use ticket
+			jam.POST("/rooms", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.CreateJamRoom)
+			jam.GET("/rooms/:id", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.GetJamRoom)
+			jam.POST("/rooms/:id/ticket", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.IssueJamTicket)
+			jam.GET("/turn-credentials", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.GetTURNCredentials)
+		}
+
+		// OAuth2 authorization server (authorization code + PKCE)
+		oauth := v1.Group("/oauth")
+		{
+			oauth.GET("/authorize", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.AuthorizeConsent)
+			oauth.POST("/authorize", middleware.AuthMiddleware(), middleware.RequireFirstParty(), handlers.AuthorizeDecision)
+			oauth.POST("/token", handlers.Token)
+			oauth.POST("/introspect", handlers.Introspect)
+			oauth.POST("/revoke", handlers.Revoke)
 		}
 
 		// Admin routes
 		admin := v1.Group("/admin")
 		admin.Use(middleware.AuthMiddleware())
 		admin.Use(middleware.AdminMiddleware())
+		admin.Use(middleware.RequireFirstParty())
 		{
 			admin.GET("/users", handlers.ListUsers)
 			admin.GET("/users/:id", handlers.GetUserByID)
 			admin.PUT("/users/:id", handlers.UpdateUserByID)
 			admin.DELETE("/users/:id", handlers.DeleteUserByID)
 			admin.GET("/stats", handlers.GetSystemStats)
+			admin.GET("/locks", handlers.GetLockStats)
+			admin.GET("/system/health", handlers.GetSystemHealth)
+			admin.GET("/transcription-jobs/dead-letters", handlers.ListDeadLetteredTranscriptions)
+			admin.POST("/transcription-jobs/:id/requeue", handlers.RequeueTranscriptionJob)
+			admin.GET("/storage/reconciliation", handlers.GetStorageReconciliation)
+			admin.GET("/reserved-usernames", handlers.ListReservedUsernames)
+			admin.POST("/reserved-usernames", handlers.ReserveUsername)
+			admin.DELETE("/reserved-usernames/:username", handlers.ReleaseUsername)
+			admin.POST("/reserved-usernames/:username/grant", handlers.GrantReservedUsername)
+			admin.GET("/verification-requests", handlers.ListVerificationRequests)
+			admin.POST("/verification-requests/:id/review", handlers.ReviewVerificationRequest)
+			admin.DELETE("/users/:id/verification", handlers.RevokeVerification)
+			admin.GET("/artists", handlers.ListArtists)
+			admin.PUT("/artists/:id", handlers.UpdateArtist)
+			admin.POST("/artists/:id/merge", handlers.MergeArtists)
+			admin.GET("/scores/licensing", handlers.GetLicensingReport)
+			admin.GET("/shadow-bans", handlers.ListShadowBannedUsers)
+			admin.POST("/users/:id/shadow-ban", handlers.ShadowBanUser)
+			admin.DELETE("/users/:id/shadow-ban", handlers.LiftShadowBan)
+			admin.GET("/content/search", handlers.SearchAllContent)
+			admin.GET("/analytics/metrics", handlers.GetAnalyticsMetrics)
+			admin.GET("/experiments", handlers.ListExperiments)
+			admin.POST("/experiments", handlers.CreateExperiment)
+			admin.PUT("/experiments/:id", handlers.UpdateExperiment)
+			admin.DELETE("/experiments/:id", handlers.DeleteExperiment)
+			admin.GET("/assessment-questions", handlers.ListAssessmentQuestions)
+			admin.POST("/assessment-questions", handlers.CreateAssessmentQuestion)
+			admin.DELETE("/assessment-questions/:id", handlers.RetireAssessmentQuestion)
+			admin.PUT("/song-requests/:id/status", handlers.UpdateSongRequestStatus)
+			admin.POST("/song-requests/:id/merge", handlers.MergeSongRequest)
+			admin.GET("/support/tickets", handlers.ListSupportQueue)
+			admin.GET("/support/tickets/:id", handlers.GetSupportTicketAdmin)
+			admin.POST("/support/tickets/:id/messages", handlers.ReplySupportTicketAdmin)
+			admin.PUT("/support/tickets/:id", handlers.UpdateSupportTicket)
+			admin.GET("/feedback", handlers.ListFeedback)
+			admin.PUT("/feedback/:id", handlers.TriageFeedback)
+			admin.GET("/errors", handlers.ListErrorGroups)
+			admin.PUT("/errors/:id", handlers.UpdateErrorGroup)
+			admin.POST("/announcements", handlers.CreateAnnouncement)
+			admin.PUT("/announcements/:id", handlers.UpdateAnnouncement)
+			admin.DELETE("/announcements/:id", handlers.DeleteAnnouncement)
+			admin.POST("/status/incidents", handlers.CreateIncident)
+			admin.POST("/status/incidents/:id/updates", handlers.PostIncidentUpdate)
+			admin.POST("/challenges", handlers.PublishChallenge)
+			admin.DELETE("/challenges/:id", handlers.DeleteChallenge)
+			admin.GET("/challenges/:id/submissions", handlers.ListChallengeSubmissions)
+			admin.POST("/challenge-submissions/:id/review", handlers.ReviewChallengeSubmission)
+
+			// Legal holds and impersonation are restricted to super admins
+			legal := admin.Group("")
+			legal.Use(middleware.SuperAdminMiddleware())
+			{
+				legal.GET("/legal-holds", handlers.ListLegalHolds)
+				legal.POST("/users/:id/legal-hold", handlers.PlaceLegalHold)
+				legal.DELETE("/users/:id/legal-hold", handlers.ReleaseLegalHold)
+				legal.POST("/users/:id/impersonate", handlers.ImpersonateUser)
+			}
 		}
 	}
 
+	// Public developer API, authenticated by API key
+	public := r.Group("/public/v1")
+	public.Use(middleware.APIKeyMiddleware())
+	{
+		public.GET("/scores", middleware.RequireScope(models.ScopeScoresRead), handlers.ListPublicScores)
+		public.GET("/scores/:id", middleware.RequireScope(models.ScopeScoresRead), handlers.GetPublicScore)
+		public.POST("/transcriptions", middleware.RequireScope(models.ScopeTranscriptionsWrite), middleware.ReplayProtectionMiddleware(5*time.Minute), handlers.SubmitTranscription)
+		public.GET("/transcriptions/:id", middleware.RequireScope(models.ScopeTranscriptionsRead), handlers.GetTranscription)
+		public.GET("/transcriptions/:id/events", middleware.RequireScope(models.ScopeTranscriptionsRead), handlers.StreamTranscriptionEvents)
+		public.POST("/transcription-batches", middleware.RequireScope(models.ScopeTranscriptionsWrite), middleware.ReplayProtectionMiddleware(5*time.Minute), handlers.SubmitTranscriptionBatch)
+		public.GET("/transcription-batches/:id", middleware.RequireScope(models.ScopeTranscriptionsRead), handlers.GetTranscriptionBatch)
+	}
+
+	// Development-only routes, never registered in production
+	if os.Getenv("GO_ENV") != "production" && os.Getenv("ENABLE_DEV_ENDPOINTS") == "true" {
+		dev := v1.Group("/dev")
+		dev.Use(middleware.AuthMiddleware())
+		dev.Use(middleware.AdminMiddleware())
+		{
+			dev.POST("/seed", handlers.SeedDatabase)
+		}
+	}
+
+	// Internal routes for other backend services (service identity tokens only)
+	allowedServices := os.Getenv("INTERNAL_ALLOWED_SERVICES")
+	if allowedServices == "" {
+		allowedServices = "media-service,transcription-service"
+	}
+	internal := r.Group("/internal/v1")
+	internal.Use(middleware.ServiceAuthMiddleware(strings.Split(allowedServices, ",")...))
+	internal.Use(middleware.ReplayProtectionMiddleware(5 * time.Minute))
+	{
+		internal.GET("/users/:id", handlers.GetInternalUser)
+		internal.POST("/users/:id/quota-check", handlers.CheckUploadQuota)
+		internal.GET("/tiers", handlers.GetTiers)
+		internal.POST("/transcription-jobs/claim", handlers.ClaimTranscriptionJob)
+		internal.POST("/transcription-jobs/:id/complete", handlers.CompleteTranscriptionJob)
+		internal.POST("/transcription-jobs/:id/heartbeat", handlers.HeartbeatTranscriptionJob)
+		internal.POST("/transcription-jobs/:id/progress", handlers.ReportTranscriptionProgress)
+		internal.POST("/transcription-jobs/:id/fail", handlers.FailTranscriptionJob)
+		internal.POST("/uploads/redeem", handlers.RedeemUploadCredential)
+		internal.POST("/challenges", handlers.PublishChallenge)
+	}
+
 	// Get port from environment or use default
 	port := os.Getenv("USER_SERVICE_PORT")
 	if port == "" {
@@ -108,14 +437,31 @@ func main() {
 		MaxHeaderBytes: 1 << 20, // 1 MB
 	}
 
+	// Optional TLS, with client certificate verification for mTLS
+	tlsConfig, err := servicecall.ServerTLSConfig()
+	if err != nil {
+		log.Fatal("Failed to configure TLS:", err)
+	}
+	srv.TLSConfig = tlsConfig
+
 	// Start server in goroutine
 	go func() {
 		log.Printf("User Service starting on port %s", port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server:", err)
 		}
 	}()
 
+	// Start scheduled jobs; Redis locks keep each to one run per interval across instances
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobs.Start(jobsCtx)
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -123,6 +469,9 @@ func main() {
 
 	log.Println("Shutting down server...")
 
+	stopJobs()
+	jobs.Wait()
+
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"user-service/internal/database"
+	"user-service/internal/seed"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	users := flag.Int("users", 50, "number of users to create")
+	scores := flag.Int("scores", 8, "scores per user")
+	randSeed := flag.Int64("seed", 1, "random seed, for reproducible data")
+	flag.Parse()
+
+	// Load environment variables
+	if err := godotenv.Load("../../../.env"); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	if os.Getenv("GO_ENV") == "production" {
+		log.Fatal("Refusing to seed a production database")
+	}
+
+	if err := database.InitDB(); err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer database.CloseDB()
+
+	result, err := seed.Run(context.Background(), database.GetDB(), seed.Options{
+		Users:         *users,
+		ScoresPerUser: *scores,
+		Seed:          *randSeed,
+	})
+	if err != nil {
+		log.Fatal("Seeding failed:", err)
+	}
+
+	log.Printf("Seeded %d users, %d scores, %d practice records (password: %s)",
+		result.Users, result.Scores, result.Progress, seed.DefaultPassword)
+}
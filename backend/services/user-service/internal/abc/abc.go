@@ -0,0 +1,940 @@
+// Package abc reads and writes ABC notation (standard 2.1) for single-voice
+// melodies, converting to and from the notes the transcription service
+// stores in a score's transcription_data.
+package abc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultTempo is the tempo, in quarter notes per minute, of tunes without Q:
+const DefaultTempo = 120
+
+// defaultVelocity is given to imported notes, which ABC has no dynamics for
+const defaultVelocity = 80
+
+// ErrNoTune is returned for text without a K: field, which starts a tune body
+var ErrNoTune = errors.New("no tune found: an ABC tune needs a K: field")
+
+// Note is a transcribed note as stored in transcription_data.notes. Pitch is
+// a MIDI note number and times are in seconds.
+type Note struct {
+	Pitch     int     `json:"pitch"`
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
+	Duration  float64 `json:"duration"`
+	Velocity  int     `json:"velocity"`
+}
+
+// Tune is a single-voice melody. Meter is "" for free meter and Key is
+// normalized to a tonic and mode, e.g. "G", "Em" or "Dmix".
+type Tune struct {
+	Title    string
+	Composer string
+	Meter    string
+	Key      string
+	Tempo    int
+	Notes    []Note
+}
+
+// ParseNotes reads the notes array of a score's transcription_data. Notes
+// without an end time last for their duration.
+func ParseNotes(data []byte) ([]Note, error) {
+	var raw []struct {
+		Pitch     float64 `json:"pitch"`
+		StartTime float64 `json:"start_time"`
+		EndTime   float64 `json:"end_time"`
+		Duration  float64 `json:"duration"`
+		Velocity  float64 `json:"velocity"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	notes := make([]Note, len(raw))
+	for i, n := range raw {
+		notes[i] = Note{
+			Pitch:     int(math.Round(n.Pitch)),
+			StartTime: n.StartTime,
+			EndTime:   n.EndTime,
+			Duration:  n.Duration,
+			Velocity:  int(n.Velocity),
+		}
+		if n.EndTime == 0 && n.Duration > 0 {
+			notes[i].EndTime = n.StartTime + n.Duration
+		}
+	}
+	return notes, nil
+}
+
+// Semitones above C of the natural notes
+var letterPitch = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+// Position of each natural note on the circle of fifths, relative to C
+var letterFifths = map[byte]int{'F': -1, 'C': 0, 'G': 1, 'D': 2, 'A': 3, 'E': 4, 'B': 5}
+
+// Modes by their first three letters, with how many fifths their key
+// signature sits from the major key on the same tonic
+var modeFifths = map[string]int{
+	"": 0, "maj": 0, "ion": 0, "mix": -1, "dor": -2, "m": -3, "min": -3, "aeo": -3,
+	"phr": -4, "loc": -5, "lyd": 1,
+}
+
+// modeSuffix writes a mode back in its usual short form
+var modeSuffix = map[string]string{
+	"": "", "maj": "", "ion": "", "mix": "mix", "dor": "dor", "m": "m", "min": "m", "aeo": "m",
+	"phr": "phr", "loc": "loc", "lyd": "lyd",
+}
+
+// key is a parsed key signature: the alteration of each letter, in
+// semitones, and the normalized name
+type key struct {
+	name   string
+	fifths int
+	alter  map[byte]int
+}
+
+// parseKey reads the value of a K: field: a tonic and optional mode ("G",
+// "Em", "D mix", "a minor"), "none", or either followed by explicit
+// accidentals ("D ^g"). Clefs and other K: options are ignored.
+func parseKey(value string) (key, error) {
+	k := key{alter: map[byte]int{}}
+	fields := strings.Fields(value)
+	if len(fields) == 0 || strings.EqualFold(fields[0], "none") {
+		if len(fields) > 0 {
+			fields = fields[1:]
+		}
+	} else {
+		tonic := fields[0]
+		fields = fields[1:]
+		letter := strings.ToUpper(tonic[:1])[0]
+		fifths, ok := letterFifths[letter]
+		if !ok {
+			return k, fmt.Errorf("invalid key %q", value)
+		}
+		rest := tonic[1:]
+		accidental := ""
+		if strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "b") {
+			accidental, rest = rest[:1], rest[1:]
+		}
+		if accidental == "#" {
+			fifths += 7
+		} else if accidental == "b" {
+			fifths -= 7
+		}
+		if rest == "" && len(fields) > 0 && isMode(fields[0]) {
+			rest, fields = fields[0], fields[1:]
+		}
+		mode := strings.ToLower(rest)
+		if len(mode) > 3 {
+			mode = mode[:3]
+		}
+		offset, ok := modeFifths[mode]
+		if !ok {
+			return k, fmt.Errorf("invalid key %q", value)
+		}
+		k.fifths = fifths + offset
+		if k.fifths < -7 || k.fifths > 7 {
+			return k, fmt.Errorf("key %q has more than seven sharps or flats", value)
+		}
+		k.name = string(letter) + accidental + modeSuffix[mode]
+		for i := 0; i < k.fifths; i++ {
+			k.alter["FCGDAEB"[i]] = 1
+		}
+		for i := 0; i < -k.fifths; i++ {
+			k.alter["BEADGCF"[i]] = -1
+		}
+	}
+
+	for _, field := range fields {
+		alter, rest := parseAccidental(field)
+		if alter == nil || len(rest) != 1 {
+			continue // clef=, middle= and the like
+		}
+		letter := strings.ToUpper(rest)[0]
+		if _, ok := letterPitch[letter]; ok {
+			k.alter[letter] = *alter
+		}
+	}
+	return k, nil
+}
+
+func isMode(field string) bool {
+	mode := strings.ToLower(field)
+	if len(mode) > 3 {
+		mode = mode[:3]
+	}
+	_, ok := modeFifths[mode]
+	return ok && mode != ""
+}
+
+// parseAccidental splits a leading ^^, ^, __, _ or = from s
+func parseAccidental(s string) (*int, string) {
+	for _, a := range []struct {
+		mark  string
+		alter int
+	}{{"^^", 2}, {"__", -2}, {"^", 1}, {"_", -1}, {"=", 0}} {
+		if strings.HasPrefix(s, a.mark) {
+			alter := a.alter
+			return &alter, s[len(a.mark):]
+		}
+	}
+	return nil, s
+}
+
+// parseFraction reads "3/4" or "1/8"
+func parseFraction(value string) (float64, bool) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	if len(parts) != 2 {
+		return 0, false
+	}
+	num, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	den, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || num <= 0 || den <= 0 {
+		return 0, false
+	}
+	return float64(num) / float64(den), true
+}
+
+// parseMeter reads an M: field into its normalized form and length in whole
+// notes; free meter has length 0
+func parseMeter(value string) (string, float64, error) {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "", "none":
+		return "", 0, nil
+	case "C":
+		return "4/4", 1, nil
+	case "C|":
+		return "2/2", 1, nil
+	}
+	length, ok := parseFraction(value)
+	if !ok {
+		return "", 0, fmt.Errorf("invalid meter %q", value)
+	}
+	return strings.ReplaceAll(value, " ", ""), length, nil
+}
+
+var tempoPattern = regexp.MustCompile(`(\d+)\s*/\s*(\d+)\s*=\s*(\d+)`)
+
+// parseTempo reads a Q: field into quarter notes per minute. A bare number
+// counts unit note lengths per minute.
+func parseTempo(value string, unit float64) (float64, error) {
+	if m := tempoPattern.FindStringSubmatch(value); m != nil {
+		beat, _ := parseFraction(m[1] + "/" + m[2])
+		bpm, _ := strconv.Atoi(m[3])
+		if beat > 0 && bpm > 0 {
+			return float64(bpm) * beat * 4, nil
+		}
+	}
+	if bpm, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && bpm > 0 {
+		return float64(bpm) * unit * 4, nil
+	}
+	return 0, fmt.Errorf("invalid tempo %q", value)
+}
+
+// event is a note, chord or rest, with its length in whole notes and the
+// tempo it is played at. tied marks pitches continuing from the last event.
+type event struct {
+	pitches []int
+	tied    []bool
+	length  float64
+	bpm     float64
+}
+
+// parser holds the state of reading a tune body
+type parser struct {
+	tune     *Tune
+	key      key
+	meter    float64
+	unit     float64
+	bpm      float64
+	events   []event
+	barAlter map[int]int // alteration by natural pitch, until the next bar line
+	tieNext  bool
+	broken   float64 // length factor the next event owes a broken rhythm
+	tuplet   struct {
+		left   int
+		factor float64
+	}
+}
+
+// Parse reads the first tune of an ABC file. Only single-voice tunes are
+// supported; repeats and endings are read as plain bar lines, and grace
+// notes, decorations and chord symbols are skipped.
+func Parse(text string) (*Tune, error) {
+	p := &parser{
+		tune:     &Tune{},
+		unit:     -1,
+		bpm:      DefaultTempo,
+		barAlter: map[int]int{},
+		broken:   1,
+	}
+	tempo := ""
+	inBody := false
+	seenTune := false
+
+	for n, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		lineNo := n + 1
+		if i := strings.Index(line, "%"); i >= 0 && (i == 0 || line[i-1] != '\\') {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if inBody {
+				break // a blank line ends the tune
+			}
+			continue
+		}
+
+		if len(trimmed) >= 2 && trimmed[1] == ':' && isFieldLine(trimmed[0]) {
+			name, value := trimmed[0], strings.TrimSpace(trimmed[2:])
+			if name == 'X' {
+				if seenTune {
+					break // only the first tune
+				}
+				seenTune = true
+				continue
+			}
+			if name == 'K' && !inBody {
+				if tempo != "" {
+					bpm, err := parseTempo(tempo, p.unitLength())
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %w", lineNo, err)
+					}
+					p.bpm = bpm
+				}
+				p.tune.Tempo = int(math.Round(p.bpm))
+				inBody = true
+			}
+			if name == 'Q' && !inBody {
+				tempo = value // the unit note length may come after
+				continue
+			}
+			if err := p.field(name, value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			continue
+		}
+		if !inBody {
+			continue // free text before the header ends
+		}
+		if err := p.body(trimmed); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if !inBody {
+		return nil, ErrNoTune
+	}
+
+	p.tune.Notes = p.notes()
+	return p.tune, nil
+}
+
+// isFieldLine tells field lines such as "K:G" or "w:lyrics" from music
+func isFieldLine(c byte) bool {
+	return c >= 'A' && c <= 'Z' || strings.IndexByte("mrsw", c) >= 0
+}
+
+func isFieldLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// unitLength is L:, defaulting by the meter as the standard says
+func (p *parser) unitLength() float64 {
+	if p.unit > 0 {
+		return p.unit
+	}
+	if p.meter > 0 && p.meter < 0.75 {
+		return 1.0 / 16
+	}
+	return 1.0 / 8
+}
+
+// field applies a header field, or one given inside the body
+func (p *parser) field(name byte, value string) error {
+	switch name {
+	case 'T':
+		if p.tune.Title == "" {
+			p.tune.Title = value
+		}
+	case 'C':
+		if p.tune.Composer == "" {
+			p.tune.Composer = value
+		}
+	case 'M':
+		meter, length, err := parseMeter(value)
+		if err != nil {
+			return err
+		}
+		if p.tune.Meter == "" && len(p.events) == 0 {
+			p.tune.Meter = meter
+		}
+		p.meter = length
+	case 'L':
+		unit, ok := parseFraction(value)
+		if !ok {
+			return fmt.Errorf("invalid unit note length %q", value)
+		}
+		p.unit = unit
+	case 'Q':
+		bpm, err := parseTempo(value, p.unitLength())
+		if err != nil {
+			return err
+		}
+		p.bpm = bpm
+	case 'K':
+		k, err := parseKey(value)
+		if err != nil {
+			return err
+		}
+		p.key = k
+		if p.tune.Key == "" {
+			p.tune.Key = k.name
+		}
+	case 'V':
+		if len(p.events) > 0 {
+			return errors.New("multiple voices are not supported")
+		}
+	}
+	return nil
+}
+
+// body reads one line of music
+func (p *parser) body(line string) error {
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '`' || c == '\\' || c == '$' || c == 'y' || c == ')':
+			i++
+		case c == '"':
+			end := strings.IndexByte(line[i+1:], '"')
+			if end < 0 {
+				return errors.New("unterminated chord symbol")
+			}
+			i += end + 2
+		case c == '!' || c == '+':
+			end := strings.IndexByte(line[i+1:], c)
+			if end < 0 {
+				return fmt.Errorf("unterminated decoration at %q", line[i:])
+			}
+			i += end + 2
+		case c == '{':
+			end := strings.IndexByte(line[i:], '}')
+			if end < 0 {
+				return errors.New("unterminated grace notes")
+			}
+			i += end + 1
+		case strings.IndexByte(".~HLMOPSTuv", c) >= 0:
+			i++
+		case c == '|' || c == ':':
+			p.barAlter = map[int]int{}
+			for i < len(line) && strings.IndexByte("|:]", line[i]) >= 0 {
+				i++
+			}
+			for i < len(line) && (line[i] >= '0' && line[i] <= '9' || line[i] == ',' || line[i] == '-') {
+				i++ // ending numbers such as |1 or :|2
+			}
+		case c == '[':
+			switch {
+			case i+2 < len(line) && isFieldLetter(line[i+1]) && line[i+2] == ':':
+				end := strings.IndexByte(line[i:], ']')
+				if end < 0 {
+					return errors.New("unterminated inline field")
+				}
+				if err := p.field(line[i+1], strings.TrimSpace(line[i+3:i+end])); err != nil {
+					return err
+				}
+				i += end + 1
+			case i+1 < len(line) && (line[i+1] >= '0' && line[i+1] <= '9' || line[i+1] == '|'):
+				p.barAlter = map[int]int{}
+				i += 2
+				for i < len(line) && (line[i] >= '0' && line[i] <= '9' || line[i] == ',' || line[i] == '-') {
+					i++
+				}
+			default:
+				n, err := p.chord(line[i+1:])
+				if err != nil {
+					return err
+				}
+				i += n + 1
+			}
+		case c == '(':
+			i++
+			if i < len(line) && line[i] >= '1' && line[i] <= '9' {
+				n := int(line[i] - '0')
+				i++
+				q, r := p.tupletTime(n), n
+				if i < len(line) && line[i] == ':' {
+					i++
+					if i < len(line) && line[i] >= '1' && line[i] <= '9' {
+						q = int(line[i] - '0')
+						i++
+					}
+					if i < len(line) && line[i] == ':' {
+						i++
+						if i < len(line) && line[i] >= '1' && line[i] <= '9' {
+							r = int(line[i] - '0')
+							i++
+						}
+					}
+				}
+				p.tuplet.left, p.tuplet.factor = r, float64(q)/float64(n)
+			}
+		case c == '-':
+			p.tieNext = true
+			i++
+		case c == '>' || c == '<':
+			n := 1
+			for i+n < len(line) && line[i+n] == c {
+				n++
+			}
+			if len(p.events) == 0 || n > 3 {
+				return fmt.Errorf("invalid broken rhythm %q", line[i:i+n])
+			}
+			short := math.Pow(0.5, float64(n))
+			long := 2 - short
+			if c == '<' {
+				long, short = short, long
+			}
+			last := &p.events[len(p.events)-1]
+			last.length *= long
+			p.broken = short
+			i += n
+		default:
+			n, err := p.single(line[i:])
+			if err != nil {
+				return err
+			}
+			i += n
+		}
+	}
+	return nil
+}
+
+// tupletTime is how many notes the time of an n-tuplet is worth, by default
+func (p *parser) tupletTime(n int) int {
+	switch n {
+	case 2, 4, 8:
+		return 3
+	case 3, 6:
+		return 2
+	}
+	if p.meter > 0 && math.Mod(p.meter*8, 3) == 0 && p.meter*8 > 3 {
+		return 3 // compound meters such as 6/8
+	}
+	return 2
+}
+
+// single reads a note or rest at the start of s, returning its length in bytes
+func (p *parser) single(s string) (int, error) {
+	if s[0] == 'z' || s[0] == 'x' {
+		length, n := parseLength(s[1:])
+		p.add(nil, length*p.unitLength())
+		return n + 1, nil
+	}
+	if s[0] == 'Z' || s[0] == 'X' {
+		bars, n := parseLength(s[1:])
+		meter := p.meter
+		if meter == 0 {
+			meter = 1
+		}
+		p.add(nil, bars*meter)
+		return n + 1, nil
+	}
+
+	pitch, n, err := p.pitch(s)
+	if err != nil {
+		return 0, err
+	}
+	length, m := parseLength(s[n:])
+	p.add([]int{pitch}, length*p.unitLength())
+	return n + m, nil
+}
+
+// chord reads the notes of [CEG] after its opening bracket, returning the
+// bytes read up to and including the length after the closing bracket. The
+// chord lasts as long as its first note.
+func (p *parser) chord(s string) (int, error) {
+	var pitches []int
+	length := -1.0
+	i := 0
+	for i < len(s) && s[i] != ']' {
+		if s[i] == ' ' || s[i] == '-' || s[i] == '"' || strings.IndexByte(".~", s[i]) >= 0 {
+			i++
+			continue
+		}
+		pitch, n, err := p.pitch(s[i:])
+		if err != nil {
+			return 0, err
+		}
+		l, m := parseLength(s[i+n:])
+		if length < 0 {
+			length = l
+		}
+		pitches = append(pitches, pitch)
+		i += n + m
+	}
+	if i >= len(s) {
+		return 0, errors.New("unterminated chord")
+	}
+	if len(pitches) == 0 {
+		return 0, errors.New("empty chord")
+	}
+	l, m := parseLength(s[i+1:])
+	p.add(pitches, length*l*p.unitLength())
+	return i + 1 + m, nil
+}
+
+// pitch reads an accidental, note letter and octave marks
+func (p *parser) pitch(s string) (int, int, error) {
+	alter, rest := parseAccidental(s)
+	n := len(s) - len(rest)
+	if rest == "" {
+		return 0, 0, fmt.Errorf("unexpected %q", s)
+	}
+	letter := rest[0]
+	upper := strings.ToUpper(string(letter))[0]
+	base, ok := letterPitch[upper]
+	if !ok || letter < 'A' || letter > 'g' {
+		return 0, 0, fmt.Errorf("unexpected %q", s[:n+1])
+	}
+	natural := 60 + base
+	if letter >= 'a' {
+		natural += 12
+	}
+	n++
+	for n < len(s) && (s[n] == '\'' || s[n] == ',') {
+		if s[n] == '\'' {
+			natural += 12
+		} else {
+			natural -= 12
+		}
+		n++
+	}
+
+	if alter != nil {
+		p.barAlter[natural] = *alter
+	}
+	a, ok := p.barAlter[natural]
+	if !ok {
+		a = p.key.alter[upper]
+	}
+	pitch := natural + a
+	if pitch < 0 || pitch > 127 {
+		return 0, 0, fmt.Errorf("note %q is out of range", s[:n])
+	}
+	return pitch, n, nil
+}
+
+// parseLength reads a note length multiplier such as 2, 3/2, / or //
+func parseLength(s string) (float64, int) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	num := 1.0
+	if i > 0 {
+		v, _ := strconv.Atoi(s[:i])
+		num = float64(v)
+	}
+	den := 1.0
+	for i < len(s) && s[i] == '/' {
+		i++
+		j := i
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		if j > i {
+			v, _ := strconv.Atoi(s[i:j])
+			if v > 0 {
+				den *= float64(v)
+			}
+			i = j
+		} else {
+			den *= 2
+		}
+	}
+	return num / den, i
+}
+
+// add appends an event, applying pending ties, broken rhythm and tuplets
+func (p *parser) add(pitches []int, length float64) {
+	length *= p.broken
+	p.broken = 1
+	if p.tuplet.left > 0 {
+		length *= p.tuplet.factor
+		p.tuplet.left--
+	}
+
+	tied := make([]bool, len(pitches))
+	if p.tieNext && len(p.events) > 0 {
+		last := p.events[len(p.events)-1]
+		for i, pitch := range pitches {
+			for _, prev := range last.pitches {
+				if prev == pitch {
+					tied[i] = true
+				}
+			}
+		}
+	}
+	p.tieNext = false
+	p.events = append(p.events, event{pitches: pitches, tied: tied, length: length, bpm: p.bpm})
+}
+
+// notes times the events, merging tied notes
+func (p *parser) notes() []Note {
+	notes := []Note{}
+	open := map[int]int{}
+	t := 0.0
+	for _, ev := range p.events {
+		seconds := ev.length * 240 / ev.bpm
+		next := map[int]int{}
+		for i, pitch := range ev.pitches {
+			if idx, ok := open[pitch]; ok && ev.tied[i] {
+				notes[idx].EndTime = round(t + seconds)
+				notes[idx].Duration = round(notes[idx].EndTime - notes[idx].StartTime)
+				next[pitch] = idx
+				continue
+			}
+			notes = append(notes, Note{
+				Pitch:     pitch,
+				StartTime: round(t),
+				EndTime:   round(t + seconds),
+				Duration:  round(seconds),
+				Velocity:  defaultVelocity,
+			})
+			next[pitch] = len(notes) - 1
+		}
+		open = next
+		t += seconds
+	}
+	return notes
+}
+
+func round(seconds float64) float64 {
+	return math.Round(seconds*1e6) / 1e6
+}
+
+// unitsPerWhole is the grid Format quantizes to: L:1/16
+const unitsPerWhole = 16
+
+// Format writes a tune as ABC. Notes are quantized to sixteenths at the
+// tune's tempo; notes starting together become a chord, and a note is cut
+// short where the next one starts, so the result is a single voice.
+func Format(t *Tune) string {
+	tempo := t.Tempo
+	if tempo <= 0 {
+		tempo = DefaultTempo
+	}
+	meter, barLength, err := parseMeter(t.Meter)
+	if err != nil || barLength == 0 {
+		meter, barLength = "4/4", 1
+	}
+	k, err := parseKey(t.Key)
+	if err != nil {
+		k, _ = parseKey("C")
+	}
+	title := strings.TrimSpace(t.Title)
+	if title == "" {
+		title = "Untitled"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "X:1\nT:%s\n", oneLine(title))
+	if t.Composer != "" {
+		fmt.Fprintf(&b, "C:%s\n", oneLine(t.Composer))
+	}
+	keyName := k.name
+	if keyName == "" {
+		keyName = "none"
+	}
+	fmt.Fprintf(&b, "M:%s\nL:1/%d\nQ:1/4=%d\nK:%s\n", meter, unitsPerWhole, tempo, keyName)
+
+	unit := 240 / float64(tempo) / unitsPerWhole
+	type group struct {
+		start, end int
+		pitches    []int
+	}
+	var groups []group
+	notes := append([]Note(nil), t.Notes...)
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].StartTime < notes[j].StartTime })
+	for _, n := range notes {
+		end := n.EndTime
+		if end <= n.StartTime {
+			end = n.StartTime + n.Duration
+		}
+		start := int(math.Round(n.StartTime / unit))
+		stop := int(math.Round(end / unit))
+		if stop <= start {
+			stop = start + 1
+		}
+		if len(groups) > 0 && groups[len(groups)-1].start == start {
+			g := &groups[len(groups)-1]
+			if !containsInt(g.pitches, n.Pitch) {
+				g.pitches = append(g.pitches, n.Pitch)
+			}
+			if stop > g.end {
+				g.end = stop
+			}
+			continue
+		}
+		groups = append(groups, group{start: start, end: stop, pitches: []int{n.Pitch}})
+	}
+
+	var body strings.Builder
+	w := &writer{b: &body, key: k, barUnits: int(math.Round(barLength * unitsPerWhole)), barAlter: map[int]int{}}
+	pos := 0
+	for i, g := range groups {
+		if g.start > pos {
+			w.write(nil, g.start-pos)
+		}
+		end := g.end
+		if i+1 < len(groups) && end > groups[i+1].start {
+			end = groups[i+1].start
+		}
+		sort.Ints(g.pitches)
+		w.write(g.pitches, end-g.start)
+		pos = end
+	}
+	if w.inBar > 0 {
+		w.write(nil, w.barUnits-w.inBar) // fill the last bar
+	}
+	b.WriteString(strings.TrimSuffix(strings.TrimRight(body.String(), " \n"), "|"))
+	b.WriteString("|]\n")
+	return b.String()
+}
+
+// ScoreNotation is the ABC of a score: the notation stored with it, or else
+// its notes written by Format. It is empty when the score has neither.
+func ScoreNotation(stored string, t *Tune) string {
+	if strings.TrimSpace(stored) != "" {
+		return stored
+	}
+	if len(t.Notes) == 0 {
+		return ""
+	}
+	return Format(t)
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// writer writes notes into bars, tying notes across bar lines
+type writer struct {
+	b        *strings.Builder
+	key      key
+	barUnits int
+	inBar    int
+	bars     int
+	barAlter map[int]int
+}
+
+// write writes a chord, note or rest (no pitches) lasting units sixteenths
+func (w *writer) write(pitches []int, units int) {
+	for units > 0 {
+		n := units
+		if left := w.barUnits - w.inBar; n > left {
+			n = left
+		}
+		if len(pitches) == 0 {
+			w.b.WriteString("z")
+		} else {
+			if len(pitches) > 1 {
+				w.b.WriteString("[")
+			}
+			for _, pitch := range pitches {
+				w.b.WriteString(w.spell(pitch))
+			}
+			if len(pitches) > 1 {
+				w.b.WriteString("]")
+			}
+		}
+		if n != 1 {
+			w.b.WriteString(strconv.Itoa(n))
+		}
+		units -= n
+		if units > 0 && len(pitches) > 0 {
+			w.b.WriteString("-")
+		}
+		w.inBar += n
+		if w.inBar == w.barUnits {
+			w.inBar = 0
+			w.bars++
+			w.barAlter = map[int]int{}
+			if w.bars%4 == 0 {
+				w.b.WriteString("|\n")
+			} else {
+				w.b.WriteString("| ")
+			}
+		} else if units == 0 {
+			w.b.WriteString(" ")
+		}
+	}
+}
+
+// spell writes a MIDI pitch as an ABC note, preferring the key's own notes,
+// then naturals, then sharps in sharp keys and flats otherwise. Accidentals
+// are written only where the key and earlier notes in the bar disagree.
+func (w *writer) spell(pitch int) string {
+	type spelling struct {
+		letter byte
+		alter  int
+	}
+	var candidates []spelling
+	for _, letter := range []byte("CDEFGAB") {
+		if (letterPitch[letter]+w.key.alter[letter]-pitch)%12 == 0 {
+			candidates = append(candidates, spelling{letter, w.key.alter[letter]})
+		}
+	}
+	preferred := []int{0, 1, -1}
+	if w.key.fifths < 0 {
+		preferred = []int{0, -1, 1}
+	}
+	for _, alter := range preferred {
+		for _, letter := range []byte("CDEFGAB") {
+			if ((letterPitch[letter]+alter-pitch)%12+12)%12 == 0 {
+				candidates = append(candidates, spelling{letter, alter})
+			}
+		}
+	}
+	s := candidates[0]
+
+	natural := pitch - s.alter // the natural note's MIDI pitch
+	current, ok := w.barAlter[natural]
+	if !ok {
+		current = w.key.alter[s.letter]
+	}
+	var out strings.Builder
+	if current != s.alter {
+		out.WriteString(map[int]string{2: "^^", 1: "^", 0: "=", -1: "_", -2: "__"}[s.alter])
+		w.barAlter[natural] = s.alter
+	}
+
+	octave := natural/12 - 1 // MIDI 60 is C4, written C
+	switch {
+	case octave >= 5:
+		out.WriteByte(s.letter + ('a' - 'A'))
+		out.WriteString(strings.Repeat("'", octave-5))
+	default:
+		out.WriteByte(s.letter)
+		out.WriteString(strings.Repeat(",", 4-octave))
+	}
+	return out.String()
+}
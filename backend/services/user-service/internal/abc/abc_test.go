@@ -0,0 +1,160 @@
+package abc
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		abc     string
+		pitches []int
+		beats   []float64 // note lengths in quarter notes
+		key     string
+		meter   string
+	}{
+		{
+			name:    "scale in C",
+			abc:     "X:1\nT:Scale\nM:4/4\nL:1/4\nK:C\nCDEF|GABc|]\n",
+			pitches: []int{60, 62, 64, 65, 67, 69, 71, 72},
+			beats:   []float64{1, 1, 1, 1, 1, 1, 1, 1},
+			key:     "C",
+			meter:   "4/4",
+		},
+		{
+			name:    "key signature and bar accidentals",
+			abc:     "X:1\nT:G\nL:1/4\nK:G\nF=FF|F|]\n",
+			pitches: []int{66, 65, 65, 66},
+			beats:   []float64{1, 1, 1, 1},
+			key:     "G",
+		},
+		{
+			name:    "minor mode and octaves",
+			abc:     "X:1\nT:Em\nL:1/8\nK:E minor\nf'F,\n",
+			pitches: []int{90, 54},
+			beats:   []float64{0.5, 0.5},
+			key:     "Em",
+		},
+		{
+			name:    "lengths, rests, ties and broken rhythm",
+			abc:     "X:1\nT:Rhythm\nL:1/8\nK:C\nC2 z C/ C3/2 | D2- | D2 E>F\n",
+			pitches: []int{60, 60, 60, 62, 64, 65},
+			beats:   []float64{1, 0.25, 0.75, 2, 0.75, 0.25},
+			key:     "C",
+		},
+		{
+			name:    "triplet and chord",
+			abc:     "X:1\nT:Tuplet\nL:1/8\nK:D\n(3ABc [DF]2\n",
+			pitches: []int{69, 71, 73, 62, 66},
+			beats:   []float64{1.0 / 3, 1.0 / 3, 1.0 / 3, 1, 1},
+			key:     "D",
+		},
+		{
+			name:    "decorations, chord symbols and second tune ignored",
+			abc:     "X:1\nT:First\nL:1/4\nK:C\n\"Am\"!trill!~A {g}B|]\n\nX:2\nT:Second\nK:C\nc\n",
+			pitches: []int{69, 71},
+			beats:   []float64{1, 1},
+			key:     "C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tune, err := Parse(tt.abc)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tune.Key != tt.key || tune.Meter != tt.meter {
+				t.Errorf("key, meter = %q, %q, want %q, %q", tune.Key, tune.Meter, tt.key, tt.meter)
+			}
+			if len(tune.Notes) != len(tt.pitches) {
+				t.Fatalf("got %d notes, want %d: %+v", len(tune.Notes), len(tt.pitches), tune.Notes)
+			}
+			quarter := 60.0 / DefaultTempo
+			for i, n := range tune.Notes {
+				if n.Pitch != tt.pitches[i] {
+					t.Errorf("note %d pitch = %d, want %d", i, n.Pitch, tt.pitches[i])
+				}
+				if got := n.Duration / quarter; math.Abs(got-tt.beats[i]) > 1e-3 {
+					t.Errorf("note %d length = %v beats, want %v", i, got, tt.beats[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		abc  string
+		want string
+	}{
+		{"no tune body", "X:1\nT:Header only\n", "K: field"},
+		{"unknown symbol", "X:1\nK:C\nC & D\n", "unexpected"},
+		{"bad key", "X:1\nK:Q\nC\n", "invalid key"},
+		{"too many sharps", "X:1\nK:G#\nC\n", "seven"},
+		{"second voice", "X:1\nK:C\nV:1\nC\nV:2\nE\n", "voices"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.abc)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		tune Tune
+	}{
+		{
+			name: "melody with a rest and a note across the bar line",
+			tune: Tune{Title: "Melody", Meter: "4/4", Key: "G", Tempo: 120, Notes: []Note{
+				{Pitch: 67, StartTime: 0, EndTime: 0.5},
+				{Pitch: 66, StartTime: 0.5, EndTime: 0.75},
+				{Pitch: 65, StartTime: 0.75, EndTime: 1},
+				{Pitch: 66, StartTime: 1, EndTime: 1.5},
+				{Pitch: 79, StartTime: 2, EndTime: 3},
+			}},
+		},
+		{
+			name: "flats and a chord in 3/4",
+			tune: Tune{Title: "Waltz", Meter: "3/4", Key: "Bb", Tempo: 90, Notes: []Note{
+				{Pitch: 58, StartTime: 0, EndTime: 2.0 / 3},
+				{Pitch: 62, StartTime: 0, EndTime: 2.0 / 3},
+				{Pitch: 61, StartTime: 2.0 / 3, EndTime: 4.0 / 3},
+				{Pitch: 48, StartTime: 4.0 / 3, EndTime: 2},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := Format(&tt.tune)
+			tune, err := Parse(text)
+			if err != nil {
+				t.Fatalf("Parse(Format()) error = %v\n%s", err, text)
+			}
+			if tune.Title != tt.tune.Title || tune.Key != tt.tune.Key || tune.Meter != tt.tune.Meter || tune.Tempo != tt.tune.Tempo {
+				t.Errorf("header = %q %q %q %d, want %q %q %q %d", tune.Title, tune.Key, tune.Meter, tune.Tempo,
+					tt.tune.Title, tt.tune.Key, tt.tune.Meter, tt.tune.Tempo)
+			}
+			if len(tune.Notes) != len(tt.tune.Notes) {
+				t.Fatalf("got %d notes, want %d\n%s", len(tune.Notes), len(tt.tune.Notes), text)
+			}
+			for i, want := range tt.tune.Notes {
+				got := tune.Notes[i]
+				if got.Pitch != want.Pitch || math.Abs(got.StartTime-want.StartTime) > 1e-3 ||
+					math.Abs(got.EndTime-want.EndTime) > 1e-3 {
+					t.Errorf("note %d = %+v, want %+v\n%s", i, got, want, text)
+				}
+			}
+		})
+	}
+}
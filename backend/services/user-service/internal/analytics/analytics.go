@@ -0,0 +1,205 @@
+package analytics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+)
+
+// Event names
+const (
+	EventSignup                 = "signup"
+	EventLogin                  = "login"
+	EventTranscriptionSubmitted = "transcription_submitted"
+	EventExperimentExposure     = "experiment_exposure"
+)
+
+// saltTTL is how long a day's salt is kept. Once it expires, pseudonyms from
+// that day can no longer be recomputed from a user ID.
+const saltTTL = 48 * time.Hour
+
+// piiProperties are property keys dropped from events as a safety net
+var piiProperties = []string{"email", "user_id", "username", "ip", "ip_address", "name", "phone"}
+
+var (
+	saltMu    sync.Mutex
+	saltDay   string
+	saltValue []byte
+)
+
+// Event is a product analytics event about a user (or anonymous when UserID
+// is empty). OccurredAt defaults to the time the event is recorded.
+type Event struct {
+	Name       string
+	UserID     string
+	Properties models.JSONB
+	OccurredAt time.Time
+}
+
+// Track records an event with the user pseudonymized. Failures are logged
+// rather than returned so analytics never breaks the request that triggered it.
+func Track(ctx context.Context, event Event) {
+	TrackMany(ctx, event.UserID, []Event{event})
+}
+
+// TrackMany records several events about one user, checking consent once for
+// the whole batch. Each event is pseudonymized for the UTC day it occurred on,
+// so late-arriving events join that day's pseudonym rather than today's. The
+// events' own UserID is ignored.
+func TrackMany(ctx context.Context, userID string, events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	now := time.Now().UTC()
+
+	if userID != "" {
+		optedOut, err := OptedOut(ctx, userID)
+		if err != nil {
+			log.Printf("Failed to check analytics consent for event %s: %v", events[0].Name, err)
+			return
+		}
+		if optedOut {
+			return
+		}
+	}
+
+	// Pseudonyms by UTC day; a batch rarely spans more than two
+	pseudonyms := map[string]string{}
+
+	tx, err := database.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to record analytics events: %v", err)
+		return
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		properties := models.JSONB{}
+		for k, v := range event.Properties {
+			if !isPIIProperty(k) {
+				properties[k] = v
+			}
+		}
+		occurredAt := event.OccurredAt
+		if occurredAt.IsZero() {
+			occurredAt = now
+		}
+
+		var subject interface{}
+		if userID != "" {
+			day := occurredAt.UTC().Format("2006-01-02")
+			pseudonym, ok := pseudonyms[day]
+			if !ok {
+				var err error
+				if pseudonym, err = Pseudonymize(ctx, userID, occurredAt); err != nil {
+					log.Printf("Failed to pseudonymize analytics event %s: %v", event.Name, err)
+					return
+				}
+				pseudonyms[day] = pseudonym
+			}
+			subject = pseudonym
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO analytics_events (name, subject, properties, occurred_at)
+			VALUES ($1, $2, $3, $4)`,
+			event.Name, subject, properties, occurredAt,
+		)
+		if err != nil {
+			log.Printf("Failed to record analytics event %s: %v", event.Name, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to record analytics events: %v", err)
+	}
+}
+
+// OptedOut reports whether the user opted out of analytics. Opted-out users'
+// events are dropped at ingestion and they are excluded from experiments.
+func OptedOut(ctx context.Context, userID string) (bool, error) {
+	var optedOut bool
+	err := database.GetReadDBFor(userID).QueryRowContext(ctx,
+		"SELECT analytics_opt_out FROM users WHERE id = $1", userID,
+	).Scan(&optedOut)
+	return optedOut, err
+}
+
+// Forget deletes the user's events from days whose salt still exists. Older
+// events can no longer be linked to the user.
+func Forget(ctx context.Context, userID string) error {
+	now := time.Now().UTC()
+	for _, day := range []time.Time{now, now.AddDate(0, 0, -1)} {
+		pseudonym, err := Pseudonymize(ctx, userID, day)
+		if err != nil {
+			return err
+		}
+		_, err = database.GetDB().ExecContext(ctx, "DELETE FROM analytics_events WHERE subject = $1", pseudonym)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pseudonymize returns the user's pseudonym for the UTC day of t. The same
+// user maps to the same pseudonym within a day and to unrelated ones across days.
+func Pseudonymize(ctx context.Context, userID string, t time.Time) (string, error) {
+	salt, err := daySalt(ctx, t.UTC().Format("2006-01-02"))
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(userID))
+	return hex.EncodeToString(mac.Sum(nil))[:32], nil
+}
+
+// daySalt returns the salt for a day, creating it in Redis on first use so all
+// instances share it
+func daySalt(ctx context.Context, day string) ([]byte, error) {
+	saltMu.Lock()
+	defer saltMu.Unlock()
+	if day == saltDay {
+		return saltValue, nil
+	}
+
+	fresh := make([]byte, 32)
+	if _, err := rand.Read(fresh); err != nil {
+		return nil, err
+	}
+
+	rdb := database.GetRedis()
+	key := "analytics:salt:" + day
+	if err := rdb.SetNX(ctx, key, hex.EncodeToString(fresh), saltTTL).Err(); err != nil {
+		return nil, err
+	}
+	encoded, err := rdb.Get(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	salt, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	saltDay, saltValue = day, salt
+	return salt, nil
+}
+
+func isPIIProperty(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range piiProperties {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// selfVisibleEvents are the event types users may see in their own activity log
+var selfVisibleEvents = []string{
+	EventLogin,
+	EventLoginFailed,
+	EventLogout,
+	EventPasswordChanged,
+	EventProfileUpdated,
+	EventAPIKeyCreated,
+	EventAPIKeyRevoked,
+	EventAppRegistered,
+	EventAppDeleted,
+	EventAppSecretRotated,
+	EventAppAuthorized,
+	EventUsernameChanged,
+	EventEmailChanged,
+	EventVerificationRequested,
+	EventVerificationReviewed,
+	EventChallengeCompleted,
+	EventImpersonationStarted,
+}
+
+// Activity is a privacy-safe view of an audit event for the account owner
+type Activity struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Device    string    `json:"device,omitempty"`
+	Location  string    `json:"location,omitempty"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	ByStaff   bool      `json:"by_staff,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListActivity returns the user's most recent security events, newest first.
+// IPs are masked, user agents reduced to a device summary, and staff actors
+// are not identified.
+func ListActivity(ctx context.Context, userID string, limit int, before time.Time) ([]Activity, error) {
+	db := database.GetReadDBFor(userID)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, event_type, ip_address, user_agent, country, actor_id, created_at
+		FROM audit_log
+		WHERE user_id = $1 AND event_type = ANY($2) AND created_at < $3
+		ORDER BY created_at DESC
+		LIMIT $4`,
+		userID, pq.Array(selfVisibleEvents), before, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []Activity{}
+	for rows.Next() {
+		var a Activity
+		var ip models.EncryptedString
+		var userAgent, country, actorID *string
+		if err := rows.Scan(&a.ID, &a.Type, &ip, &userAgent, &country, &actorID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		a.IPAddress = MaskIP(string(ip))
+		if userAgent != nil {
+			a.Device = DescribeDevice(*userAgent)
+		}
+		if country != nil {
+			a.Location = *country
+		}
+		a.ByStaff = actorID != nil && *actorID != userID
+
+		activities = append(activities, a)
+	}
+
+	return activities, rows.Err()
+}
+
+// MaskIP hides the host part of an address (last IPv4 octet, last 80 bits of IPv6)
+func MaskIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String() + "/48"
+}
+
+// DescribeDevice reduces a user agent to a short "Browser on OS" summary
+func DescribeDevice(userAgent string) string {
+	browser := ""
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	os := ""
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		os = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		os = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		os = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"):
+		os = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	case userAgent != "":
+		return "Other client"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Event types recorded in the audit log
+const (
+	EventLogin                 = "login"
+	EventLoginFailed           = "login_failed"
+	EventLogout                = "logout"
+	EventPasswordChanged       = "password_changed"
+	EventProfileUpdated        = "profile_updated"
+	EventAccountDeleted        = "account_deleted"
+	EventScoreDownloaded       = "score_downloaded"
+	EventTranscriptionRequeued = "transcription_requeued"
+	EventScoresMerged          = "scores_merged"
+	EventAPIKeyCreated         = "api_key_created"
+	EventAPIKeyRevoked         = "api_key_revoked"
+	EventAPIKeySecretRotated   = "api_key_signing_secret_rotated"
+	EventAppRegistered         = "app_registered"
+	EventAppDeleted            = "app_deleted"
+	EventAppSecretRotated      = "app_secret_rotated"
+	EventAppAuthorized         = "app_authorized"
+	EventScoreDeleted          = "score_deleted"
+	EventScoreRestored         = "score_restored"
+	EventUsernameChanged       = "username_changed"
+	EventEmailChanged          = "email_changed"
+	EventVerificationRequested = "verification_requested"
+	EventVerificationReviewed  = "verification_reviewed"
+	EventArtistsMerged         = "artists_merged"
+	EventAlbumVisibility       = "album_visibility_changed"
+	EventChallengeCompleted    = "challenge_completed"
+	EventScoreLicenseSet       = "score_license_set"
+	EventScoreVisibility       = "score_visibility_changed"
+	EventShadowBanApplied      = "shadow_ban_applied"
+	EventShadowBanLifted       = "shadow_ban_lifted"
+	EventContentSearched       = "content_searched"
+	EventLegalHoldPlaced       = "legal_hold_placed"
+	EventLegalHoldReleased     = "legal_hold_released"
+	EventDeletionBlocked       = "deletion_blocked"
+	EventImpersonationStarted  = "impersonation_started"
+)
+
+// Event is a single audit log entry
+type Event struct {
+	UserID    string
+	ActorID   string
+	Type      string
+	IPAddress string
+	UserAgent string
+	Country   string
+	Metadata  models.JSONB
+}
+
+// Log writes an audit event. Failures are logged rather than returned so
+// auditing never breaks the request that triggered it.
+func Log(ctx context.Context, event Event) {
+	db := database.GetDB()
+
+	var actorID interface{}
+	if event.ActorID != "" {
+		actorID = event.ActorID
+	}
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO audit_log (user_id, actor_id, event_type, ip_address, user_agent, country, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.UserID, actorID, event.Type,
+		models.EncryptedString(event.IPAddress), event.UserAgent, event.Country, event.Metadata,
+	)
+	if err != nil {
+		log.Printf("Failed to write audit event %s: %v", event.Type, err)
+	}
+}
+
+// LogRequest records an event about userID, taking the actor, client IP,
+// user agent and country from the request. Under impersonation the actor is
+// the staff member, not the impersonated user.
+func LogRequest(c *gin.Context, userID, eventType string, metadata models.JSONB) {
+	actorID := c.GetString("user_id")
+	if impersonatorID := c.GetString("impersonator_id"); impersonatorID != "" {
+		actorID = impersonatorID
+	}
+	Log(c.Request.Context(), Event{
+		UserID:    userID,
+		ActorID:   actorID,
+		Type:      eventType,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Country:   requestCountry(c),
+		Metadata:  metadata,
+	})
+}
+
+// requestCountry reads the client country set by the CDN or gateway, if any
+func requestCountry(c *gin.Context) string {
+	for _, header := range []string{"CF-IPCountry", "CloudFront-Viewer-Country", "X-Country-Code"} {
+		if country := c.GetHeader(header); len(country) == 2 {
+			return country
+		}
+	}
+	return ""
+}
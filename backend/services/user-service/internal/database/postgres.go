@@ -1,14 +1,25 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log"
 	"os"
+	"sync/atomic"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
 var db *sql.DB
+var replicaDB *sql.DB
+
+// replicaLagging is set by the lag monitor when the replica falls too far behind
+var replicaLagging atomic.Bool
+
+// stopReplicaMonitor ends the lag monitor, set while it runs
+var stopReplicaMonitor context.CancelFunc
 
 // InitDB initializes the PostgreSQL database connection
 func InitDB() error {
@@ -32,17 +43,148 @@ func InitDB() error {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	// Optional read replica
+	replicaURL := os.Getenv("DATABASE_REPLICA_URL")
+	if replicaURL == "" {
+		return nil
+	}
+
+	replicaDB, err = sql.Open("postgres", replicaURL)
+	if err != nil {
+		return fmt.Errorf("failed to open replica database: %w", err)
+	}
+
+	replicaDB.SetMaxOpenConns(25)
+	replicaDB.SetMaxIdleConns(5)
+
+	if err := replicaDB.Ping(); err != nil {
+		return fmt.Errorf("failed to ping replica database: %w", err)
+	}
+
+	// Check once before serving so a lagging replica is never read from
+	checkReplicaLag(context.Background())
+	monitorCtx, cancel := context.WithCancel(context.Background())
+	stopReplicaMonitor = cancel
+	go monitorReplicaLag(monitorCtx)
+
 	return nil
 }
 
-// GetDB returns the database instance
+// GetDB returns the primary database instance, used for all writes
 func GetDB() *sql.DB {
 	return db
 }
 
-// CloseDB closes the database connection
+// GetReadDB returns the database to use for read-only queries. It returns the
+// replica when one is configured and healthy, otherwise the primary.
+func GetReadDB() *sql.DB {
+	if replicaDB == nil || replicaLagging.Load() {
+		return db
+	}
+	return replicaDB
+}
+
+// GetReadDBFor returns the read database for queries about a specific user.
+// Users who wrote recently are pinned to the primary so they can read their
+// own writes before the replica catches up.
+func GetReadDBFor(userID string) *sql.DB {
+	if replicaDB == nil || hasRecentWrite(userID) {
+		return db
+	}
+	return GetReadDB()
+}
+
+// MarkWrite records that a user's data was just written to the primary
+func MarkWrite(userID string) {
+	if replicaDB == nil || redisClient == nil {
+		return
+	}
+	if err := redisClient.Set(ctx, recentWriteKey(userID), 1, readAfterWriteWindow()).Err(); err != nil {
+		log.Printf("Failed to mark recent write: %v", err)
+	}
+}
+
+// hasRecentWrite reports whether the user wrote within the read-after-write window.
+// When Redis is unavailable it errs on the side of reading from the primary.
+func hasRecentWrite(userID string) bool {
+	if redisClient == nil {
+		return true
+	}
+	n, err := redisClient.Exists(ctx, recentWriteKey(userID)).Result()
+	if err != nil {
+		return true
+	}
+	return n > 0
+}
+
+func recentWriteKey(userID string) string {
+	return "db:recent_write:" + userID
+}
+
+// readAfterWriteWindow returns how long users stay pinned to the primary after a write
+func readAfterWriteWindow() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("REPLICA_READ_AFTER_WRITE_WINDOW")); err == nil && d > 0 {
+		return d
+	}
+	return 5 * time.Second
+}
+
+// maxReplicaLag returns the lag above which reads are routed back to the primary
+func maxReplicaLag() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("REPLICA_MAX_LAG")); err == nil && d > 0 {
+		return d
+	}
+	return 10 * time.Second
+}
+
+// monitorReplicaLag periodically checks replication lag until ctx is cancelled
+func monitorReplicaLag(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkReplicaLag(ctx)
+		}
+	}
+}
+
+// checkReplicaLag measures replication lag and toggles replica routing
+func checkReplicaLag(ctx context.Context) {
+	var lagSeconds float64
+	err := replicaDB.QueryRowContext(ctx, `
+		SELECT CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM (NOW() - pg_last_xact_replay_timestamp())), 0)
+		END`,
+	).Scan(&lagSeconds)
+	if ctx.Err() != nil {
+		return
+	}
+
+	lagging := err != nil || time.Duration(lagSeconds*float64(time.Second)) > maxReplicaLag()
+	if lagging != replicaLagging.Load() {
+		if lagging {
+			log.Printf("Replica lag exceeds threshold, routing reads to primary (lag=%.1fs, err=%v)", lagSeconds, err)
+		} else {
+			log.Println("Replica caught up, routing reads to replica")
+		}
+	}
+	replicaLagging.Store(lagging)
+}
+
+// CloseDB closes the database connections
 func CloseDB() {
+	if stopReplicaMonitor != nil {
+		stopReplicaMonitor()
+	}
 	if db != nil {
 		db.Close()
 	}
-}
\ No newline at end of file
+	if replicaDB != nil {
+		replicaDB.Close()
+	}
+}
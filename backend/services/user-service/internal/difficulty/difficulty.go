@@ -0,0 +1,149 @@
+package difficulty
+
+import (
+	"math"
+	"sort"
+	"user-service/internal/fingering"
+)
+
+// Factors are what a score's difficulty is estimated from
+type Factors struct {
+	// Tempo is the score's marked tempo in BPM, used when it has no notes
+	Tempo int `json:"tempo,omitempty"`
+	// NotesPerSecond counts note onsets, chords once, while the score plays
+	NotesPerSecond float64 `json:"notes_per_second"`
+	// MaxStretch is the widest fret span within a chord
+	MaxStretch int `json:"max_stretch"`
+	// HighestFret is the highest fret played, counted from the nut
+	HighestFret int `json:"highest_fret"`
+	// ChordDensity is the share of onsets that are chords of three or more notes
+	ChordDensity float64 `json:"chord_density"`
+	// Techniques are those detected in the score
+	Techniques []string `json:"techniques"`
+}
+
+// Weights of the components; they add up to 1
+const (
+	speedWeight     = 0.35
+	stretchWeight   = 0.2
+	positionWeight  = 0.1
+	techniqueWeight = 0.2
+	chordWeight     = 0.15
+)
+
+// techniqueDifficulty rates techniques detected by the style analyzer from 0
+// to 1. Unlisted techniques rate as defaultTechnique.
+var techniqueDifficulty = map[string]float64{
+	"strumming":         0,
+	"finger_picking":    0.2,
+	"power_chords":      0.1,
+	"palm_muting":       0.2,
+	"palm_mute":         0.2,
+	"octaves":           0.3,
+	"double_stops":      0.3,
+	"hammer_on":         0.3,
+	"pull_off":          0.3,
+	"hammer_pull":       0.3,
+	"slide":             0.3,
+	"slides":            0.3,
+	"arpeggios":         0.4,
+	"bending":           0.4,
+	"vibrato":           0.4,
+	"alternate_picking": 0.4,
+	"hybrid_picking":    0.6,
+	"harmonics":         0.6,
+	"unison_bends":      0.6,
+	"tremolo_picking":   0.6,
+	"tremolo":           0.6,
+	"fast_runs":         0.8,
+	"sweep_picking":     1,
+	"tapping":           1,
+}
+
+const defaultTechnique = 0.3
+
+// Measure computes the factors of a score from its notes, fingerings (nil
+// where a note has none) and detected techniques
+func Measure(notes []fingering.Note, positions []*fingering.Position, capo, tempo int, techniques []string) Factors {
+	f := Factors{Tempo: tempo, Techniques: techniques, MaxStretch: fingering.Span(notes, positions)}
+	if f.Techniques == nil {
+		f.Techniques = []string{}
+	}
+	for _, pos := range positions {
+		if pos != nil && pos.Fret > 0 && pos.Fret+capo > f.HighestFret {
+			f.HighestFret = pos.Fret + capo
+		}
+	}
+	if len(notes) == 0 {
+		return f
+	}
+
+	starts := make([]float64, len(notes))
+	for i, n := range notes {
+		starts[i] = n.Start
+	}
+	sort.Float64s(starts)
+
+	// Onsets group notes starting within 30ms, like the fingering suggester
+	onsets, chords, size := 0, 0, 0
+	for i, start := range starts {
+		if i == 0 || start-starts[i-1] > 0.03 {
+			if size >= 3 {
+				chords++
+			}
+			onsets++
+			size = 0
+		}
+		size++
+	}
+	if size >= 3 {
+		chords++
+	}
+	f.ChordDensity = float64(chords) / float64(onsets)
+	if duration := starts[len(starts)-1] - starts[0]; duration > 0 {
+		f.NotesPerSecond = float64(onsets) / duration
+	}
+	return f
+}
+
+// Estimate rates factors from 1 (beginner) to 10 (virtuoso)
+func Estimate(f Factors) int {
+	speed := scale(f.NotesPerSecond, 1, 8)
+	if f.NotesPerSecond == 0 {
+		speed = scale(float64(f.Tempo), 60, 200)
+	}
+
+	// The hardest technique counts fully; each further one adds a little
+	ratings := make([]float64, len(f.Techniques))
+	for i, name := range f.Techniques {
+		ratings[i] = techniqueRating(name)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(ratings)))
+	technique := 0.0
+	for i, rating := range ratings {
+		if i == 0 {
+			technique = rating
+		} else {
+			technique += 0.1 * rating
+		}
+	}
+
+	total := speedWeight*speed +
+		stretchWeight*scale(float64(f.MaxStretch), 3, 6) +
+		positionWeight*scale(float64(f.HighestFret), 5, 17) +
+		techniqueWeight*math.Min(technique, 1) +
+		chordWeight*scale(f.ChordDensity, 0, 0.6)
+	return 1 + int(math.Round(9*total))
+}
+
+func techniqueRating(name string) float64 {
+	if rating, ok := techniqueDifficulty[name]; ok {
+		return rating
+	}
+	return defaultTechnique
+}
+
+// scale maps v from [low, high] onto [0, 1], clamping outside the range
+func scale(v, low, high float64) float64 {
+	return math.Max(0, math.Min(1, (v-low)/(high-low)))
+}
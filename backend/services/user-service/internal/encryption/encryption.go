@@ -0,0 +1,185 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prefix marks values produced by Encrypt; anything else is treated as legacy plaintext
+const prefix = "enc:v1:"
+
+// ErrUnknownKey is returned when a ciphertext references a key that is not loaded
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Encrypt seals plaintext with a fresh data key, which is itself wrapped with
+// the active key-encryption key. The result is safe to store in a TEXT column:
+//
+//	enc:v1:<key id>:<wrapped data key>:<ciphertext>
+func Encrypt(plaintext string) (string, error) {
+	kr, err := getKeyring()
+	if err != nil {
+		return "", err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := kr.provider.Wrap(kr.active, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + kr.active + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt reverses Encrypt. Values without the encryption prefix are returned
+// unchanged so columns can be migrated incrementally.
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, wrapped, ciphertext, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	kr, err := getKeyring()
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := kr.provider.Unwrap(keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap re-encrypts the data key of a value under the active key without
+// touching the payload. It reports whether the value changed; plaintext values
+// are encrypted in full.
+func Rewrap(value string) (string, bool, error) {
+	if !IsEncrypted(value) {
+		encrypted, err := Encrypt(value)
+		return encrypted, err == nil, err
+	}
+
+	keyID, wrapped, ciphertext, err := parse(value)
+	if err != nil {
+		return "", false, err
+	}
+
+	kr, err := getKeyring()
+	if err != nil {
+		return "", false, err
+	}
+	if keyID == kr.active {
+		return value, false, nil
+	}
+
+	dataKey, err := kr.provider.Unwrap(keyID, wrapped)
+	if err != nil {
+		return "", false, err
+	}
+
+	rewrapped, err := kr.provider.Wrap(kr.active, dataKey)
+	if err != nil {
+		return "", false, err
+	}
+
+	return prefix + kr.active + ":" +
+		base64.RawStdEncoding.EncodeToString(rewrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), true, nil
+}
+
+// IsEncrypted reports whether a stored value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// ActiveKeyID returns the id of the key currently used for new encryptions
+func ActiveKeyID() (string, error) {
+	kr, err := getKeyring()
+	if err != nil {
+		return "", err
+	}
+	return kr.active, nil
+}
+
+func parse(value string) (keyID string, wrapped, ciphertext []byte, err error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, errors.New("malformed encrypted value")
+	}
+
+	wrapped, err = base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("malformed wrapped key: %w", err)
+	}
+
+	ciphertext, err = base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("malformed ciphertext: %w", err)
+	}
+
+	return parts[0], wrapped, ciphertext, nil
+}
+
+// seal encrypts with AES-256-GCM, prepending the nonce to the output
+func seal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts output produced by seal
+func open(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
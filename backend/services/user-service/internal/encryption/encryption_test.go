@@ -0,0 +1,172 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+// useKeys installs an in-memory keyring for the duration of a test
+func useKeys(t *testing.T, active string, keys map[string][]byte) {
+	t.Helper()
+	SetProvider(active, &staticProvider{keys: keys})
+}
+
+// tamper flips one byte of the decoded field at index (1 wrapped key, 2 ciphertext)
+func tamper(t *testing.T, value string, index int) string {
+	t.Helper()
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	raw, err := base64.RawStdEncoding.DecodeString(parts[index])
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw[len(raw)-1] ^= 0xff
+	parts[index] = base64.RawStdEncoding.EncodeToString(raw)
+	return prefix + strings.Join(parts, ":")
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	useKeys(t, "k1", map[string][]byte{"k1": testKey(1)})
+
+	tests := []struct {
+		name      string
+		plaintext string
+	}{
+		{"empty", ""},
+		{"ascii", "jane@example.com"},
+		{"unicode", "작곡가 ♪ Ünïcødé"},
+		{"contains separator", "a:b:c:enc:v1:"},
+		{"long", strings.Repeat("x", 10000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := Encrypt(tt.plaintext)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			if !IsEncrypted(encrypted) {
+				t.Fatalf("Encrypt() output %q is not marked encrypted", encrypted)
+			}
+			if tt.plaintext != "" && strings.Contains(encrypted, tt.plaintext) {
+				t.Fatalf("Encrypt() output contains the plaintext")
+			}
+			decrypted, err := Decrypt(encrypted)
+			if err != nil {
+				t.Fatalf("Decrypt() error = %v", err)
+			}
+			if decrypted != tt.plaintext {
+				t.Errorf("Decrypt() = %q, want %q", decrypted, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestDecryptLegacyPlaintext(t *testing.T) {
+	useKeys(t, "k1", map[string][]byte{"k1": testKey(1)})
+
+	got, err := Decrypt("jane@example.com")
+	if err != nil || got != "jane@example.com" {
+		t.Errorf("Decrypt() = %q, %v; want plaintext passed through", got, err)
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	useKeys(t, "k1", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+
+	encrypted, err := Encrypt("jane@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"flipped ciphertext byte", tamper(t, encrypted, 2)},
+		{"flipped wrapped key byte", tamper(t, encrypted, 1)},
+		{"swapped key id", strings.Replace(encrypted, prefix+"k1:", prefix+"k2:", 1)},
+		{"truncated ciphertext", encrypted[:len(encrypted)-4]},
+		{"missing field", encrypted[:strings.LastIndex(encrypted, ":")]},
+		{"invalid base64", encrypted + "!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Decrypt(tt.value); err == nil {
+				t.Errorf("Decrypt() = %q, want error", got)
+			}
+		})
+	}
+}
+
+func TestUnknownKey(t *testing.T) {
+	useKeys(t, "retired", map[string][]byte{"retired": testKey(1)})
+	encrypted, err := Encrypt("jane@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useKeys(t, "current", map[string][]byte{"current": testKey(2)})
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"decrypt", func() error { _, err := Decrypt(encrypted); return err }},
+		{"rewrap", func() error { _, _, err := Rewrap(encrypted); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, ErrUnknownKey) {
+				t.Errorf("error = %v, want ErrUnknownKey", err)
+			}
+		})
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	keys := map[string][]byte{"old": testKey(1), "new": testKey(2)}
+	useKeys(t, "old", keys)
+	underOld, err := Encrypt("jane@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useKeys(t, "new", keys)
+	underNew, err := Encrypt("jane@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		wantChanged bool
+	}{
+		{"value under retired key", underOld, true},
+		{"value under active key", underNew, false},
+		{"legacy plaintext", "jane@example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewrapped, changed, err := Rewrap(tt.value)
+			if err != nil {
+				t.Fatalf("Rewrap() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("Rewrap() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if !strings.HasPrefix(rewrapped, prefix+"new:") {
+				t.Errorf("Rewrap() = %q, want it under the active key", rewrapped)
+			}
+			if got, err := Decrypt(rewrapped); err != nil || got != "jane@example.com" {
+				t.Errorf("Decrypt(Rewrap()) = %q, %v", got, err)
+			}
+		})
+	}
+}
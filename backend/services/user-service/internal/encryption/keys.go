@@ -0,0 +1,120 @@
+package encryption
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// KeyProvider wraps and unwraps data keys with a key-encryption key. The
+// keyfile provider is built in; a KMS-backed provider can be installed with
+// SetProvider.
+type KeyProvider interface {
+	Wrap(keyID string, dataKey []byte) ([]byte, error)
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+type keyring struct {
+	active   string
+	provider KeyProvider
+}
+
+var (
+	ring     *keyring
+	ringErr  error
+	ringOnce sync.Once
+)
+
+// SetProvider installs a custom key provider (e.g. KMS) with the given active key id
+func SetProvider(activeKeyID string, provider KeyProvider) {
+	ringOnce.Do(func() {})
+	ring = &keyring{active: activeKeyID, provider: provider}
+	ringErr = nil
+}
+
+func getKeyring() (*keyring, error) {
+	ringOnce.Do(func() {
+		ring, ringErr = loadKeyring()
+	})
+	return ring, ringErr
+}
+
+// keyFile is the on-disk format of ENCRYPTION_KEYFILE:
+//
+//	{"active": "2024-06", "keys": {"2024-01": "<base64>", "2024-06": "<base64>"}}
+//
+// Old keys stay in the file until a rotation run has rewrapped every value.
+type keyFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// loadKeyring reads keys from ENCRYPTION_KEYFILE, falling back to a single
+// ENCRYPTION_KEY, and outside production to a development default
+func loadKeyring() (*keyring, error) {
+	if path := os.Getenv("ENCRYPTION_KEYFILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption keyfile: %w", err)
+		}
+
+		var kf keyFile
+		if err := json.Unmarshal(data, &kf); err != nil {
+			return nil, fmt.Errorf("failed to parse encryption keyfile: %w", err)
+		}
+
+		keys := make(map[string][]byte, len(kf.Keys))
+		for id, encoded := range kf.Keys {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil || len(key) != 32 {
+				return nil, fmt.Errorf("encryption key %q must be 32 bytes, base64 encoded", id)
+			}
+			keys[id] = key
+		}
+		if _, ok := keys[kf.Active]; !ok {
+			return nil, fmt.Errorf("active encryption key %q not found in keyfile", kf.Active)
+		}
+
+		return &keyring{active: kf.Active, provider: &staticProvider{keys: keys}}, nil
+	}
+
+	if encoded := os.Getenv("ENCRYPTION_KEY"); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("ENCRYPTION_KEY must be 32 bytes, base64 encoded")
+		}
+		return &keyring{active: "default", provider: &staticProvider{keys: map[string][]byte{"default": key}}}, nil
+	}
+
+	if os.Getenv("GO_ENV") == "production" {
+		return nil, errors.New("no encryption key configured: set ENCRYPTION_KEYFILE or ENCRYPTION_KEY, or install a KMS provider")
+	}
+
+	key := sha256.Sum256([]byte("default-encryption-key-change-in-production"))
+	return &keyring{active: "dev", provider: &staticProvider{keys: map[string][]byte{"dev": key[:]}}}, nil
+}
+
+// staticProvider wraps data keys locally with AES-GCM using keys held in memory
+type staticProvider struct {
+	keys map[string][]byte
+}
+
+func (p *staticProvider) Wrap(keyID string, dataKey []byte) ([]byte, error) {
+	kek, ok := p.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return seal(kek, dataKey)
+}
+
+func (p *staticProvider) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	kek, ok := p.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return open(kek, wrapped)
+}
@@ -0,0 +1,339 @@
+package fingering
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MaxFret is the highest fret a fingering may use
+const MaxFret = 24
+
+// chordWindow groups notes starting within this many seconds into one chord
+const chordWindow = 0.03
+
+// statesPerChord bounds how many ways of playing each chord are kept
+const statesPerChord = 48
+
+// Named tunings, lowest string first, as MIDI pitches. These match the
+// presets of the tab converter in ai-models.
+var namedTunings = map[string][]int{
+	"standard":       {40, 45, 50, 55, 59, 64},
+	"drop d":         {38, 45, 50, 55, 59, 64},
+	"half step down": {39, 44, 49, 54, 58, 63},
+	"eb standard":    {39, 44, 49, 54, 58, 63},
+	"open g":         {38, 43, 50, 55, 59, 62},
+	"open d":         {38, 45, 50, 54, 57, 62},
+	"dadgad":         {38, 45, 50, 55, 57, 62},
+}
+
+var pitchClasses = map[string]int{
+	"C": 0, "C#": 1, "DB": 1, "D": 2, "D#": 3, "EB": 3, "E": 4, "F": 5, "F#": 6,
+	"GB": 6, "G": 7, "G#": 8, "AB": 8, "A": 9, "A#": 10, "BB": 10, "B": 11,
+}
+
+// ErrUnknownTuning is returned for tunings that are neither named nor spelled
+// out note by note
+var ErrUnknownTuning = errors.New("unknown tuning")
+
+// Position is where a note is played. Strings are numbered from the lowest
+// pitched string, starting at 1; frets count from the capo.
+type Position struct {
+	String int `json:"string"`
+	Fret   int `json:"fret"`
+}
+
+// Note is a transcribed note to be fingered
+type Note struct {
+	Pitch int
+	Start float64
+}
+
+// ParseNotes reads the notes array of a score's transcription_data, keeping
+// each note's position in the array
+func ParseNotes(data []byte) ([]Note, error) {
+	var raw []struct {
+		Pitch     float64 `json:"pitch"`
+		StartTime float64 `json:"start_time"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	notes := make([]Note, len(raw))
+	for i, n := range raw {
+		notes[i] = Note{Pitch: int(math.Round(n.Pitch)), Start: n.StartTime}
+	}
+	return notes, nil
+}
+
+// ParseTuning returns the open string pitches, lowest first, for a named
+// tuning ("standard", "drop d", "dadgad", ...) or one spelled out from the
+// lowest string: "EADGBE", "DADGAD", "Eb Ab Db Gb Bb Eb" or "D-A-D-G-B-E".
+// Unseparated spellings mark flats with a lowercase "b". Spelled tunings
+// start between B1 and A#2 and rise string by string.
+func ParseTuning(name string) ([]int, error) {
+	key := strings.Join(strings.Fields(strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(name))), " ")
+	if key == "" {
+		key = "standard"
+	}
+	if pitches, ok := namedTunings[key]; ok {
+		return append([]int(nil), pitches...), nil
+	}
+
+	var tokens []string
+	if fields := strings.FieldsFunc(name, func(r rune) bool { return r == ' ' || r == '-' || r == ',' }); len(fields) > 1 {
+		tokens = fields
+	} else {
+		spelled := strings.TrimSpace(name)
+		if strings.ToLower(spelled) == spelled {
+			spelled = strings.ToUpper(spelled) // "eadgbe": every letter is a string
+		}
+		for i := 0; i < len(spelled); i++ {
+			if (spelled[i] == '#' || spelled[i] == 'b') && len(tokens) > 0 {
+				tokens[len(tokens)-1] += string(spelled[i])
+				continue
+			}
+			tokens = append(tokens, string(spelled[i]))
+		}
+	}
+	if len(tokens) < 4 || len(tokens) > 8 {
+		return nil, ErrUnknownTuning
+	}
+
+	pitches := make([]int, 0, len(tokens))
+	for _, token := range tokens {
+		class, ok := pitchClasses[strings.ToUpper(token)]
+		if !ok {
+			return nil, ErrUnknownTuning
+		}
+		pitch := 35 + (class+1)%12 // B1 (35) up to A#2 (46)
+		if n := len(pitches); n > 0 {
+			prev := pitches[n-1]
+			pitch = prev + 1 + ((class-(prev+1))%12+12)%12
+		}
+		pitches = append(pitches, pitch)
+	}
+	return pitches, nil
+}
+
+// Playable reports whether a position produces the pitch on the tuning
+func Playable(tuning []int, capo int, pos Position, pitch int) bool {
+	if pos.String < 1 || pos.String > len(tuning) || pos.Fret < 0 || pos.Fret+capo > MaxFret {
+		return false
+	}
+	return tuning[pos.String-1]+capo+pos.Fret == pitch
+}
+
+// candidates lists every position that plays the pitch
+func candidates(tuning []int, capo, pitch int) []Position {
+	var out []Position
+	for i := range tuning {
+		pos := Position{String: i + 1, Fret: pitch - tuning[i] - capo}
+		if Playable(tuning, capo, pos, pitch) {
+			out = append(out, pos)
+		}
+	}
+	return out
+}
+
+// chordState is one way of playing a chord: a position per note (nil when
+// the note cannot be placed) and the fretting hand's resulting position
+type chordState struct {
+	positions []*Position
+	hand      float64
+	cost      float64
+}
+
+// Suggest proposes a position for each note, minimizing fret-hand movement
+// between chords and stretches within them, preferring lower positions.
+// Fixed positions (by note index) are kept as given. Notes that cannot be
+// played on the tuning get nil.
+func Suggest(notes []Note, tuning []int, capo int, fixed map[int]Position) []*Position {
+	result := make([]*Position, len(notes))
+
+	order := make([]int, len(notes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return notes[order[a]].Start < notes[order[b]].Start })
+
+	var chords [][]int
+	for _, idx := range order {
+		if n := len(chords); n > 0 && notes[idx].Start-notes[chords[n-1][0]].Start <= chordWindow {
+			chords[n-1] = append(chords[n-1], idx)
+			continue
+		}
+		chords = append(chords, []int{idx})
+	}
+
+	// Viterbi over chords: each layer holds, per state, the cheapest path
+	// ending in it and the state it came from
+	type pathState struct {
+		state chordState
+		total float64
+		back  int
+	}
+	var layers [][]pathState
+	for c, chord := range chords {
+		states := chordStates(notes, chord, tuning, capo, fixed)
+		layer := make([]pathState, len(states))
+		for i, s := range states {
+			layer[i] = pathState{state: s, total: s.cost, back: -1}
+			if c == 0 {
+				continue
+			}
+			layer[i].total = math.Inf(1)
+			for j, prev := range layers[c-1] {
+				move := 0.0
+				if s.hand >= 0 && prev.state.hand >= 0 {
+					move = math.Abs(s.hand - prev.state.hand)
+				}
+				if total := prev.total + move + s.cost; total < layer[i].total {
+					layer[i].total, layer[i].back = total, j
+				}
+			}
+		}
+		// A chord whose hand position is unknown (all open or unplayable)
+		// inherits the previous hand position for the next transition
+		for i := range layer {
+			if layer[i].state.hand < 0 && layer[i].back >= 0 {
+				layer[i].state.hand = layers[c-1][layer[i].back].state.hand
+			}
+		}
+		layers = append(layers, layer)
+	}
+	if len(layers) == 0 {
+		return result
+	}
+
+	best := 0
+	last := layers[len(layers)-1]
+	for i := range last {
+		if last[i].total < last[best].total {
+			best = i
+		}
+	}
+	for c := len(layers) - 1; c >= 0 && best >= 0; c-- {
+		state := layers[c][best]
+		for k, idx := range chords[c] {
+			result[idx] = state.state.positions[k]
+		}
+		best = state.back
+	}
+	return result
+}
+
+// chordStates enumerates ways to play a chord on distinct strings, keeping
+// the cheapest. Notes that do not fit are left unplaced, highest cost.
+func chordStates(notes []Note, chord []int, tuning []int, capo int, fixed map[int]Position) []chordState {
+	options := make([][]Position, len(chord))
+	for k, idx := range chord {
+		if pos, ok := fixed[idx]; ok && Playable(tuning, capo, pos, notes[idx].Pitch) {
+			options[k] = []Position{pos}
+			continue
+		}
+		options[k] = candidates(tuning, capo, notes[idx].Pitch)
+	}
+
+	var states []chordState
+	current := make([]*Position, len(chord))
+	used := make([]bool, len(tuning)+1)
+	var walk func(k int)
+	walk = func(k int) {
+		if len(states) > 4096 {
+			return
+		}
+		if k == len(chord) {
+			states = append(states, scoreChord(current))
+			return
+		}
+		placed := false
+		for _, pos := range options[k] {
+			if used[pos.String] {
+				continue
+			}
+			pos := pos
+			used[pos.String], current[k] = true, &pos
+			walk(k + 1)
+			used[pos.String], current[k] = false, nil
+			placed = true
+		}
+		if !placed {
+			walk(k + 1)
+		}
+	}
+	walk(0)
+
+	sort.SliceStable(states, func(a, b int) bool { return states[a].cost < states[b].cost })
+	if len(states) > statesPerChord {
+		states = states[:statesPerChord]
+	}
+	return states
+}
+
+// scoreChord costs one way of playing a chord: unplaced notes, stretches
+// beyond four frets and high positions are penalized
+func scoreChord(positions []*Position) chordState {
+	state := chordState{positions: append([]*Position(nil), positions...), hand: -1}
+	low, high := -1, -1
+	for _, pos := range positions {
+		if pos == nil {
+			state.cost += 100
+			continue
+		}
+		if pos.Fret == 0 {
+			continue
+		}
+		if low < 0 || pos.Fret < low {
+			low = pos.Fret
+		}
+		if pos.Fret > high {
+			high = pos.Fret
+		}
+		state.cost += 0.1 * float64(pos.Fret)
+	}
+	if low >= 0 {
+		state.hand = float64(low)
+		if span := high - low; span > 3 {
+			state.cost += 10 * float64(span-3)
+		}
+	}
+	return state
+}
+
+// Span returns the largest fret stretch within any chord of a fingering,
+// counting only fretted notes that start together
+func Span(notes []Note, positions []*Position) int {
+	type chord struct {
+		start     float64
+		low, high int
+	}
+	order := make([]int, 0, len(notes))
+	for i := range notes {
+		if i < len(positions) && positions[i] != nil && positions[i].Fret > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return notes[order[a]].Start < notes[order[b]].Start })
+
+	widest := 0
+	var cur *chord
+	for _, idx := range order {
+		fret := positions[idx].Fret
+		if cur == nil || notes[idx].Start-cur.start > chordWindow {
+			cur = &chord{start: notes[idx].Start, low: fret, high: fret}
+		}
+		if fret < cur.low {
+			cur.low = fret
+		}
+		if fret > cur.high {
+			cur.high = fret
+		}
+		if cur.high-cur.low > widest {
+			widest = cur.high - cur.low
+		}
+	}
+	return widest
+}
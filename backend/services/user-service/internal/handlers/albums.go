@@ -0,0 +1,434 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// albumColumns selects an album a with its number of tracks still in the
+// library; trashed scores keep their place and reappear when restored
+const albumColumns = `
+	a.id, a.title, a.artist, to_char(a.release_date, 'YYYY-MM-DD'), a.label, a.cover_url,
+	a.description, a.is_public,
+	(SELECT COUNT(*) FROM album_tracks t JOIN scores s ON s.id = t.score_id
+	 WHERE t.album_id = a.id AND s.deleted_at IS NULL),
+	a.created_at, a.updated_at`
+
+func scanAlbum(row rowScanner) (models.Album, error) {
+	var a models.Album
+	err := row.Scan(&a.ID, &a.Title, &a.Artist, &a.ReleaseDate, &a.Label, &a.CoverURL,
+		&a.Description, &a.IsPublic, &a.TrackCount, &a.CreatedAt, &a.UpdatedAt)
+	return a, err
+}
+
+// respondAlbum writes an owner's album with its tracks in order
+func respondAlbum(c *gin.Context, db *sql.DB, status int, userID, albumID string) {
+	album, err := scanAlbum(db.QueryRow(
+		"SELECT "+albumColumns+" FROM albums a WHERE a.id = $1 AND a.user_id = $2",
+		albumID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get album"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT `+libraryScoreColumns+`
+		FROM album_tracks t
+		JOIN scores s ON s.id = t.score_id
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id
+		WHERE t.album_id = $1 AND s.deleted_at IS NULL
+		ORDER BY t.position`,
+		albumID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get album"})
+		return
+	}
+	defer rows.Close()
+
+	tracks := []models.LibraryScore{}
+	for rows.Next() {
+		s, err := scanLibraryScore(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get album"})
+			return
+		}
+		tracks = append(tracks, s)
+	}
+
+	c.JSON(status, gin.H{"album": album, "tracks": tracks})
+}
+
+// ListAlbums lists the current user's albums, newest first
+func ListAlbums(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	rows, err := database.GetReadDBFor(userID).Query(
+		"SELECT "+albumColumns+" FROM albums a WHERE a.user_id = $1 ORDER BY a.created_at DESC",
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get albums"})
+		return
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	for rows.Next() {
+		a, err := scanAlbum(rows)
+		if err != nil {
+			continue
+		}
+		albums = append(albums, a)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"albums": albums})
+}
+
+// GetAlbum returns one of the current user's albums with its tracks
+func GetAlbum(c *gin.Context) {
+	userID := c.GetString("user_id")
+	albumID := c.Param("id")
+	if _, err := uuid.Parse(albumID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid album ID"})
+		return
+	}
+
+	respondAlbum(c, database.GetReadDBFor(userID), http.StatusOK, userID, albumID)
+}
+
+// CreateAlbum creates an empty, private album
+func CreateAlbum(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.AlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var albumID string
+	err := db.QueryRow(`
+		INSERT INTO albums (user_id, title, artist, release_date, label, cover_url, description)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		userID, req.Title, req.Artist, req.ReleaseDate, req.Label, req.CoverURL, req.Description,
+	).Scan(&albumID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create album"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondAlbum(c, db, http.StatusCreated, userID, albumID)
+}
+
+// UpdateAlbum replaces an album's metadata
+func UpdateAlbum(c *gin.Context) {
+	userID := c.GetString("user_id")
+	albumID := c.Param("id")
+	if _, err := uuid.Parse(albumID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid album ID"})
+		return
+	}
+
+	var req models.AlbumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE albums SET title = $3, artist = $4, release_date = $5, label = $6, cover_url = $7, description = $8
+		WHERE id = $1 AND user_id = $2`,
+		albumID, userID, req.Title, req.Artist, req.ReleaseDate, req.Label, req.CoverURL, req.Description,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update album"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondAlbum(c, db, http.StatusOK, userID, albumID)
+}
+
+// DeleteAlbum deletes an album; its scores stay in the library
+func DeleteAlbum(c *gin.Context) {
+	userID := c.GetString("user_id")
+	albumID := c.Param("id")
+	if _, err := uuid.Parse(albumID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid album ID"})
+		return
+	}
+
+	result, err := database.GetDB().Exec("DELETE FROM albums WHERE id = $1 AND user_id = $2", albumID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete album"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Album deleted"})
+}
+
+// SetAlbumTracks replaces an album's track list with the given scores, in
+// order. Scores already in another of the user's albums move to this one.
+func SetAlbumTracks(c *gin.Context) {
+	userID := c.GetString("user_id")
+	albumID := c.Param("id")
+	if _, err := uuid.Parse(albumID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid album ID"})
+		return
+	}
+
+	var req models.AlbumTracks
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scoreIDs := make([]string, 0, len(req.ScoreIDs))
+	seen := map[uuid.UUID]bool{}
+	for _, id := range req.ScoreIDs {
+		if seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A score can only appear once in an album"})
+			return
+		}
+		seen[id] = true
+		scoreIDs = append(scoreIDs, id.String())
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var locked string
+	err = tx.QueryRow("SELECT id FROM albums WHERE id = $1 AND user_id = $2 FOR UPDATE", albumID, userID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var owned int
+	err = tx.QueryRow(
+		"SELECT COUNT(*) FROM scores WHERE id = ANY($1::uuid[]) AND user_id = $2 AND deleted_at IS NULL",
+		pq.Array(scoreIDs), userID,
+	).Scan(&owned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if owned != len(scoreIDs) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+
+	// Trashed tracks are not listed but keep their album until purged
+	_, err = tx.Exec(`
+		DELETE FROM album_tracks t USING scores s
+		WHERE s.id = t.score_id AND (t.album_id = $1 AND s.deleted_at IS NULL OR t.score_id = ANY($2::uuid[]))`,
+		albumID, pq.Array(scoreIDs),
+	)
+	if err == nil {
+		_, err = tx.Exec(`
+			INSERT INTO album_tracks (album_id, score_id, position)
+			SELECT $1, ids.score_id, COALESCE((SELECT MAX(position) FROM album_tracks WHERE album_id = $1), 0) + ids.n
+			FROM unnest($2::uuid[]) WITH ORDINALITY AS ids(score_id, n)`,
+			albumID, pq.Array(scoreIDs),
+		)
+	}
+	if err == nil {
+		_, err = tx.Exec("UPDATE albums SET updated_at = NOW() WHERE id = $1", albumID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tracks"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tracks"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondAlbum(c, db, http.StatusOK, userID, albumID)
+}
+
+// UpdateAlbumVisibility publishes or unpublishes an album together with its
+// tracks, so it is shared as a unit. Publishing requires every track to be
+// finished and to carry license information.
+func UpdateAlbumVisibility(c *gin.Context) {
+	userID := c.GetString("user_id")
+	albumID := c.Param("id")
+	if _, err := uuid.Parse(albumID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid album ID"})
+		return
+	}
+
+	var req models.ScoreVisibility
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE albums SET is_public = $3 WHERE id = $1 AND user_id = $2", albumID, userID, *req.IsPublic)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visibility"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+		return
+	}
+
+	if *req.IsPublic {
+		rows, err := tx.Query(`
+			SELECT s.id FROM album_tracks t JOIN scores s ON s.id = t.score_id
+			WHERE t.album_id = $1 AND s.deleted_at IS NULL
+			  AND (s.license_type IS NULL OR s.is_draft IS TRUE)
+			ORDER BY t.position
+			FOR UPDATE OF s`,
+			albumID,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visibility"})
+			return
+		}
+		blocked := []string{}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err == nil {
+				blocked = append(blocked, id)
+			}
+		}
+		rows.Close()
+		if len(blocked) > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     "Finish every track and add its license information before publishing the album",
+				"score_ids": blocked,
+			})
+			return
+		}
+	}
+
+	result, err = tx.Exec(`
+		UPDATE scores s SET is_public = $2
+		FROM album_tracks t
+		WHERE t.score_id = s.id AND t.album_id = $1 AND s.deleted_at IS NULL
+		  AND s.is_public IS DISTINCT FROM $2`,
+		albumID, *req.IsPublic,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visibility"})
+		return
+	}
+	changed, _ := result.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visibility"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventAlbumVisibility, models.JSONB{
+		"album_id":       albumID,
+		"is_public":      *req.IsPublic,
+		"scores_changed": changed,
+	})
+
+	respondAlbum(c, db, http.StatusOK, userID, albumID)
+}
+
+// GetPublicAlbum returns a public album with its public tracks in order. The
+// route is cached for anonymous visitors.
+func GetPublicAlbum(c *gin.Context) {
+	albumID := c.Param("id")
+	if _, err := uuid.Parse(albumID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid album ID"})
+		return
+	}
+
+	db := database.GetReadDB()
+	var owner string
+	var album models.Album
+	err := db.QueryRow(`
+		SELECT `+albumColumns+`, u.username
+		FROM albums a JOIN users u ON u.id = a.user_id
+		WHERE a.id = $1 AND a.is_public = true AND u.is_active = true AND u.shadow_banned_at IS NULL`,
+		albumID,
+	).Scan(&album.ID, &album.Title, &album.Artist, &album.ReleaseDate, &album.Label, &album.CoverURL,
+		&album.Description, &album.IsPublic, &album.TrackCount, &album.CreatedAt, &album.UpdatedAt, &owner)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Album not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get album"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT `+publicScoreColumns+`
+		FROM album_tracks t
+		JOIN scores s ON s.id = t.score_id
+		JOIN users u ON u.id = s.user_id
+		WHERE t.album_id = $1 AND s.is_public = true AND s.is_draft = false AND s.deleted_at IS NULL
+		ORDER BY t.position`,
+		albumID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get album"})
+		return
+	}
+	defer rows.Close()
+
+	tracks := []models.PublicScore{}
+	for rows.Next() {
+		s, err := scanPublicScore(rows)
+		if err != nil {
+			continue
+		}
+		tracks = append(tracks, s)
+	}
+	album.TrackCount = len(tracks)
+	if err := translateScores(c, db, tracks); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get album"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"album": album, "owner": owner, "tracks": tracks})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAnalyticsMetrics returns daily aggregates of a product metric for the
+// last ?days= days (default 30). Dashboards only ever read aggregates, never
+// raw events. ?dimension= filters per-event metrics to one event (admin).
+func GetAnalyticsMetrics(c *gin.Context) {
+	metric := c.DefaultQuery("metric", models.MetricActiveUsers)
+	if !contains([]string{models.MetricActiveUsers, models.MetricEvents, models.MetricEventUsers, models.MetricExperimentExposures}, metric) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "metric must be active_users, events, event_users or experiment_exposures"})
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return
+	}
+
+	rows, err := database.GetReadDB().Query(`
+		SELECT day, metric, dimension, value
+		FROM analytics_daily_metrics
+		WHERE metric = $1 AND day > $2 AND ($3 = '' OR dimension = $3)
+		ORDER BY day, dimension`,
+		metric, time.Now().UTC().AddDate(0, 0, -days), c.Query("dimension"),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get metrics"})
+		return
+	}
+	defer rows.Close()
+
+	metrics := []models.DailyMetric{}
+	for rows.Next() {
+		var m models.DailyMetric
+		if err := rows.Scan(&m.Day, &m.Metric, &m.Dimension, &m.Value); err != nil {
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"metric": metric, "days": days, "values": metrics})
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// lastSeenAnnouncement returns the user's seen watermark; users who never
+// opened the feed have seen nothing
+func lastSeenAnnouncement(db *sql.DB, userID string) (time.Time, error) {
+	var seen time.Time
+	err := db.QueryRow("SELECT last_seen_at FROM announcement_receipts WHERE user_id = $1", userID).Scan(&seen)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return seen, err
+}
+
+// ListAnnouncements returns published announcements, newest first, each
+// flagged as seen or not for the current user
+func ListAnnouncements(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	seen, err := lastSeenAnnouncement(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get announcements"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, title, body, kind, link_url, published_at
+		FROM announcements WHERE published_at <= NOW()
+		ORDER BY published_at DESC
+		LIMIT 20`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get announcements"})
+		return
+	}
+	defer rows.Close()
+
+	unseen := 0
+	announcements := []models.Announcement{}
+	for rows.Next() {
+		var a models.Announcement
+		if err := rows.Scan(&a.ID, &a.Title, &a.Body, &a.Kind, &a.LinkURL, &a.PublishedAt); err != nil {
+			continue
+		}
+		a.Seen = !a.PublishedAt.After(seen)
+		if !a.Seen {
+			unseen++
+		}
+		announcements = append(announcements, a)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"announcements": announcements, "unseen_count": unseen})
+}
+
+// GetUnseenAnnouncementCount returns how many published announcements the
+// user has not seen, for the what's-new badge
+func GetUnseenAnnouncementCount(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	seen, err := lastSeenAnnouncement(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get announcements"})
+		return
+	}
+
+	var unseen int
+	err = db.QueryRow(
+		"SELECT COUNT(*) FROM announcements WHERE published_at <= NOW() AND published_at > $1", seen,
+	).Scan(&unseen)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get announcements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unseen_count": unseen})
+}
+
+// MarkAnnouncementsSeen marks every announcement published so far as seen
+func MarkAnnouncementsSeen(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	_, err := database.GetDB().Exec(`
+		INSERT INTO announcement_receipts (user_id, last_seen_at) VALUES ($1, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET last_seen_at = GREATEST(announcement_receipts.last_seen_at, NOW())`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update announcements"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, gin.H{"unseen_count": 0})
+}
+
+// errPastPublishedAt explains why a backdated published_at is rejected: seen
+// state is a single watermark, so an entry published before a user's last
+// visit would never show as unseen to them
+const errPastPublishedAt = "published_at must be in the future; omit it to publish now"
+
+// CreateAnnouncement publishes or schedules an announcement (admin)
+func CreateAnnouncement(c *gin.Context) {
+	var req models.AnnouncementInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.PublishedAt != nil && !req.PublishedAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errPastPublishedAt})
+		return
+	}
+
+	a := models.Announcement{Title: req.Title, Body: req.Body, Kind: req.Kind}
+	err := database.GetDB().QueryRow(`
+		INSERT INTO announcements (title, body, kind, link_url, published_at, created_by)
+		VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), $6)
+		RETURNING id, link_url, published_at`,
+		req.Title, req.Body, req.Kind, nullIfEmpty(req.LinkURL), req.PublishedAt, c.GetString("user_id"),
+	).Scan(&a.ID, &a.LinkURL, &a.PublishedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create announcement"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, a)
+}
+
+// UpdateAnnouncement replaces an announcement's content (admin). Keeping the
+// original published_at avoids re-flagging an edited entry as unseen; it may
+// be moved into the future but not backdated.
+func UpdateAnnouncement(c *gin.Context) {
+	announcementID := c.Param("id")
+	if _, err := uuid.Parse(announcementID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid announcement ID"})
+		return
+	}
+
+	var req models.AnnouncementInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	if req.PublishedAt != nil && !req.PublishedAt.After(time.Now()) {
+		// Echoing the current value back is not a change
+		var current time.Time
+		err := db.QueryRow("SELECT published_at FROM announcements WHERE id = $1", announcementID).Scan(&current)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update announcement"})
+			return
+		}
+		if !current.Equal(*req.PublishedAt) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errPastPublishedAt})
+			return
+		}
+	}
+
+	a := models.Announcement{Title: req.Title, Body: req.Body, Kind: req.Kind}
+	err := db.QueryRow(`
+		UPDATE announcements
+		SET title = $1, body = $2, kind = $3, link_url = $4, published_at = COALESCE($5, published_at)
+		WHERE id = $6
+		RETURNING id, link_url, published_at`,
+		req.Title, req.Body, req.Kind, nullIfEmpty(req.LinkURL), req.PublishedAt, announcementID,
+	).Scan(&a.ID, &a.LinkURL, &a.PublishedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update announcement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, a)
+}
+
+// DeleteAnnouncement removes an announcement (admin)
+func DeleteAnnouncement(c *gin.Context) {
+	announcementID := c.Param("id")
+	if _, err := uuid.Parse(announcementID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid announcement ID"})
+		return
+	}
+
+	result, err := database.GetDB().Exec("DELETE FROM announcements WHERE id = $1", announcementID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete announcement"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Announcement deleted"})
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/encryption"
+	"user-service/internal/models"
+	"user-service/internal/ratelimit"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CreateAPIKey issues a new public API key for the current user
+func CreateAPIKey(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.APIKeyCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = models.DefaultAPIKeyScopes
+	}
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scope: " + scope})
+			return
+		}
+	}
+
+	db := database.GetDB()
+
+	// Enforce the tier's key limit
+	var tier string
+	var activeKeys int
+	err := db.QueryRow(`
+		SELECT u.subscription_tier,
+			   (SELECT COUNT(*) FROM api_keys WHERE user_id = u.id AND revoked_at IS NULL)
+		FROM users u WHERE u.id = $1`,
+		userID,
+	).Scan(&tier, &activeKeys)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
+		return
+	}
+
+	caps := models.GetTierCapabilities(tier)
+	if activeKeys >= caps.MaxAPIKeys {
+		c.JSON(http.StatusForbidden, gin.H{"error": &models.QuotaError{
+			Code:    models.QuotaAPIKeys,
+			Message: "API key limit reached for your plan",
+			Tier:    tier,
+			Limit:   caps.MaxAPIKeys,
+		}})
+		return
+	}
+
+	key, prefix, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate API key"})
+		return
+	}
+
+	secret, encryptedSecret, err := newSigningSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate signing secret"})
+		return
+	}
+
+	var created models.APIKeyCreated
+	err = db.QueryRow(`
+		INSERT INTO api_keys (user_id, name, key_prefix, key_hash, scopes, signing_secret)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, name, key_prefix, scopes, created_at`,
+		userID, req.Name, prefix, hash, pq.Array(scopes), encryptedSecret,
+	).Scan(&created.ID, &created.UserID, &created.Name, &created.KeyPrefix, pq.Array(&created.Scopes), &created.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+	created.Key = key
+	created.SigningSecret = secret
+
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventAPIKeyCreated, models.JSONB{"api_key_id": created.ID, "name": created.Name, "scopes": created.Scopes})
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListAPIKeys lists the current user's API keys
+func ListAPIKeys(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	rows, err := db.Query(`
+		SELECT id, user_id, name, key_prefix, scopes, created_at, last_used_at, revoked_at
+		FROM api_keys WHERE user_id = $1
+		ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get API keys"})
+		return
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		if err := rows.Scan(&k.ID, &k.UserID, &k.Name, &k.KeyPrefix, pq.Array(&k.Scopes), &k.CreatedAt, &k.LastUsedAt, &k.RevokedAt); err != nil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// RevokeAPIKey revokes one of the current user's API keys
+func RevokeAPIKey(c *gin.Context) {
+	userID := c.GetString("user_id")
+	keyID := c.Param("id")
+
+	if _, err := uuid.Parse(keyID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE api_keys SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		keyID, userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventAPIKeyRevoked, models.JSONB{"api_key_id": keyID})
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+// RotateAPIKeySigningSecret issues a new request signing secret for one of the
+// current user's API keys. The old secret stops working immediately; keys
+// created before request signing use this to get their first secret.
+func RotateAPIKeySigningSecret(c *gin.Context) {
+	userID := c.GetString("user_id")
+	keyID := c.Param("id")
+
+	if _, err := uuid.Parse(keyID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	secret, encryptedSecret, err := newSigningSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate signing secret"})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE api_keys SET signing_secret = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`,
+		keyID, userID, encryptedSecret,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate signing secret"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventAPIKeySecretRotated, models.JSONB{"api_key_id": keyID})
+
+	c.JSON(http.StatusOK, gin.H{"signing_secret": secret})
+}
+
+// newSigningSecret returns a request signing secret and its encrypted form for storage
+func newSigningSecret() (secret, encrypted string, err error) {
+	secret, err = utils.GenerateSigningSecret()
+	if err != nil {
+		return "", "", err
+	}
+	encrypted, err = encryption.Encrypt(secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, encrypted, nil
+}
+
+// GetAPIKeyUsage reports daily usage per endpoint and the current month's quota for a key
+func GetAPIKeyUsage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	keyID := c.Param("id")
+
+	if _, err := uuid.Parse(keyID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 90"})
+		return
+	}
+
+	db := database.GetReadDBFor(userID)
+
+	var tier string
+	err = db.QueryRow(`
+		SELECT u.subscription_tier FROM api_keys k JOIN users u ON u.id = k.user_id
+		WHERE k.id = $1 AND k.user_id = $2`,
+		keyID, userID,
+	).Scan(&tier)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT to_char(day, 'YYYY-MM-DD'), endpoint, request_count
+		FROM api_key_usage
+		WHERE api_key_id = $1 AND day > CURRENT_DATE - $2::int
+		ORDER BY day DESC, endpoint`,
+		keyID, days,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get usage"})
+		return
+	}
+	defer rows.Close()
+
+	usage := []models.APIKeyUsage{}
+	total := 0
+	for rows.Next() {
+		var u models.APIKeyUsage
+		if err := rows.Scan(&u.Day, &u.Endpoint, &u.RequestCount); err != nil {
+			continue
+		}
+		total += u.RequestCount
+		usage = append(usage, u)
+	}
+
+	monthUsed, err := ratelimit.MonthlyUsage(c.Request.Context(), "apikey:"+keyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get quota"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"usage":          usage,
+		"total_requests": total,
+		"quota": gin.H{
+			"monthly_limit":         models.GetTierCapabilities(tier).APIMonthlyQuota,
+			"used_this_month":       monthUsed,
+			"rate_limit_per_minute": models.GetTierCapabilities(tier).APIRateLimitPerMinute,
+		},
+	})
+}
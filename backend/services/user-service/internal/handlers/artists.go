@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// artistSelect selects artists with their follower and public score counts.
+// Scores link to artists through the trigger in migration 045, which
+// resolves the free-text artist name through artist_aliases.
+const artistSelect = `
+	SELECT a.id, a.name, a.bio, a.image_url,
+		(SELECT COUNT(*) FROM artist_follows f WHERE f.artist_id = a.id),
+		(SELECT COUNT(*) FROM scores s JOIN users u ON u.id = s.user_id
+		 WHERE s.artist_id = a.id AND s.is_public = true AND s.is_draft = false
+		   AND s.deleted_at IS NULL AND u.is_active = true AND u.shadow_banned_at IS NULL),
+		a.created_at
+	FROM artists a`
+
+func scanArtist(row rowScanner) (models.Artist, error) {
+	var a models.Artist
+	err := row.Scan(&a.ID, &a.Name, &a.Bio, &a.ImageURL, &a.FollowerCount, &a.ScoreCount, &a.CreatedAt)
+	return a, err
+}
+
+// GetArtistPage returns an artist and a page of their public scores. The
+// route is cached for anonymous visitors, so nothing here is per-viewer.
+func GetArtistPage(c *gin.Context) {
+	artistID := c.Param("id")
+	if _, err := uuid.Parse(artistID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid artist ID"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	db := database.GetReadDB()
+	artist, err := scanArtist(db.QueryRow(artistSelect+" WHERE a.id = $1", artistID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Artist not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT `+publicScoreColumns+`
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.artist_id = $1 AND s.is_public = true AND s.is_draft = false
+		  AND s.deleted_at IS NULL AND u.is_active = true AND u.shadow_banned_at IS NULL
+		ORDER BY s.created_at DESC
+		LIMIT $2 OFFSET $3`,
+		artistID, limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.PublicScore{}
+	for rows.Next() {
+		s, err := scanPublicScore(rows)
+		if err != nil {
+			continue
+		}
+		scores = append(scores, s)
+	}
+	if err := translateScores(c, db, scores); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"artist": artist, "scores": scores, "limit": limit, "offset": offset})
+}
+
+// ListFollowedArtists lists the artists the current user follows
+func ListFollowedArtists(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	rows, err := database.GetReadDBFor(userID).Query(artistSelect+`
+		JOIN artist_follows af ON af.artist_id = a.id AND af.user_id = $1
+		ORDER BY af.created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get artists"})
+		return
+	}
+	defer rows.Close()
+
+	artists := []models.Artist{}
+	for rows.Next() {
+		a, err := scanArtist(rows)
+		if err != nil {
+			continue
+		}
+		artists = append(artists, a)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"artists": artists})
+}
+
+// FollowArtist follows an artist for the current user
+func FollowArtist(c *gin.Context) {
+	setArtistFollow(c, true)
+}
+
+// UnfollowArtist stops following an artist
+func UnfollowArtist(c *gin.Context) {
+	setArtistFollow(c, false)
+}
+
+func setArtistFollow(c *gin.Context, follow bool) {
+	userID := c.GetString("user_id")
+	artistID := c.Param("id")
+	if _, err := uuid.Parse(artistID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid artist ID"})
+		return
+	}
+
+	db := database.GetDB()
+	var exists bool
+	if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM artists WHERE id = $1)", artistID).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Artist not found"})
+		return
+	}
+
+	var err error
+	if follow {
+		_, err = db.Exec(`
+			INSERT INTO artist_follows (artist_id, user_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`,
+			artistID, userID,
+		)
+	} else {
+		_, err = db.Exec("DELETE FROM artist_follows WHERE artist_id = $1 AND user_id = $2", artistID, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update follow"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	var followers int
+	if err := db.QueryRow("SELECT COUNT(*) FROM artist_follows WHERE artist_id = $1", artistID).Scan(&followers); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"artist_id": artistID, "following": follow, "follower_count": followers})
+}
+
+// ListArtists lists artists by name for curation (admin)
+func ListArtists(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	rows, err := database.GetReadDB().Query(artistSelect+`
+		WHERE $1 = '' OR a.name ILIKE '%' || $1 || '%'
+		ORDER BY lower(a.name), a.created_at
+		LIMIT $2 OFFSET $3`,
+		c.Query("q"), limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get artists"})
+		return
+	}
+	defer rows.Close()
+
+	artists := []models.Artist{}
+	for rows.Next() {
+		a, err := scanArtist(rows)
+		if err != nil {
+			continue
+		}
+		artists = append(artists, a)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"artists": artists, "limit": limit, "offset": offset})
+}
+
+// UpdateArtist edits an artist's name, bio and image (admin). The new name
+// becomes an alias so scores spelled that way link to the artist too.
+func UpdateArtist(c *gin.Context) {
+	artistID := c.Param("id")
+	if _, err := uuid.Parse(artistID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid artist ID"})
+		return
+	}
+
+	var req models.ArtistUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Artist name cannot be blank"})
+		return
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"UPDATE artists SET name = btrim($2), bio = $3, image_url = $4 WHERE id = $1",
+		artistID, req.Name, req.Bio, req.ImageURL,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update artist"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Artist not found"})
+		return
+	}
+
+	var owner string
+	err = tx.QueryRow(`
+		INSERT INTO artist_aliases (name_key, artist_id) VALUES (artist_name_key($2), $1)
+		ON CONFLICT (name_key) DO UPDATE SET name_key = EXCLUDED.name_key
+		RETURNING artist_id`,
+		artistID, req.Name,
+	).Scan(&owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update artist"})
+		return
+	}
+	if owner != artistID {
+		c.JSON(http.StatusConflict, gin.H{"error": "Another artist already uses this name; merge them instead"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update artist"})
+		return
+	}
+
+	artist, err := scanArtist(db.QueryRow(artistSelect+" WHERE a.id = $1", artistID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, artist)
+}
+
+// MergeArtists folds duplicate artists into the artist in the URL (admin).
+// Their scores, followers and aliases move over and the duplicates are
+// deleted, so every spelling that pointed at them now resolves here.
+func MergeArtists(c *gin.Context) {
+	artistID := c.Param("id")
+	if _, err := uuid.Parse(artistID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid artist ID"})
+		return
+	}
+
+	var req models.ArtistMerge
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dupes := make([]string, 0, len(req.DuplicateIDs))
+	for _, id := range req.DuplicateIDs {
+		if id.String() == artistID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge an artist into itself"})
+			return
+		}
+		dupes = append(dupes, id.String())
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM (
+			SELECT id FROM artists WHERE id = $1 OR id = ANY($2::uuid[]) ORDER BY id FOR UPDATE
+		) a`,
+		artistID, pq.Array(dupes),
+	).Scan(&locked)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if locked != len(dupes)+1 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Artist not found"})
+		return
+	}
+
+	// Scores keep the spelling their uploader typed; the moved aliases make
+	// any later edit of that spelling resolve to the canonical artist
+	_, err = tx.Exec("UPDATE artist_aliases SET artist_id = $1 WHERE artist_id = ANY($2::uuid[])", artistID, pq.Array(dupes))
+	var moved int64
+	if err == nil {
+		var result sql.Result
+		result, err = tx.Exec("UPDATE scores SET artist_id = $1 WHERE artist_id = ANY($2::uuid[])", artistID, pq.Array(dupes))
+		if err == nil {
+			moved, _ = result.RowsAffected()
+		}
+	}
+	if err == nil {
+		_, err = tx.Exec(`
+			INSERT INTO artist_follows (artist_id, user_id, created_at)
+			SELECT $1, user_id, MIN(created_at) FROM artist_follows
+			WHERE artist_id = ANY($2::uuid[])
+			GROUP BY user_id
+			ON CONFLICT DO NOTHING`,
+			artistID, pq.Array(dupes),
+		)
+	}
+	if err == nil {
+		_, err = tx.Exec("DELETE FROM artists WHERE id = ANY($1::uuid[])", pq.Array(dupes))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge artists"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge artists"})
+		return
+	}
+
+	audit.LogRequest(c, c.GetString("user_id"), audit.EventArtistsMerged, models.JSONB{
+		"artist_id":     artistID,
+		"duplicate_ids": dupes,
+		"scores_moved":  moved,
+	})
+
+	artist, err := scanArtist(db.QueryRow(artistSelect+" WHERE a.id = $1", artistID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, artist)
+}
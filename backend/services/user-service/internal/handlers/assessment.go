@@ -0,0 +1,445 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+// quizQuestionsPerLevel is how many questions the placement quiz draws from each difficulty level
+const quizQuestionsPerLevel = 2
+
+// placementQuizTTL is how long a drawn quiz can be submitted
+const placementQuizTTL = time.Hour
+
+// placementPassRate is the share of answers at a level that must be correct to place at it
+const placementPassRate = 0.6
+
+// placeSkillLevel walks difficulty levels upward and returns the highest level
+// passed before the first failed one. Users who pass nothing are placed at 1.
+func placeSkillLevel(correct, total map[int]int) int {
+	levels := make([]int, 0, len(total))
+	for level := range total {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	placed := 1
+	for _, level := range levels {
+		if float64(correct[level]) < placementPassRate*float64(total[level]) {
+			break
+		}
+		placed = level
+	}
+	return placed
+}
+
+// placementRetakeCooldown returns the minimum time between graded placement quizzes
+func placementRetakeCooldown() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PLACEMENT_RETAKE_COOLDOWN")); err == nil && d >= 0 {
+		return d
+	}
+	return 7 * 24 * time.Hour
+}
+
+// checkPlacementCooldown returns when the user may next take the placement
+// quiz, or the zero time if they may take it now
+func checkPlacementCooldown(db *sql.DB, userID string) (time.Time, error) {
+	var lastTaken sql.NullTime
+	err := db.QueryRow(
+		"SELECT MAX(created_at) FROM assessment_results WHERE user_id = $1 AND method = 'quiz'",
+		userID,
+	).Scan(&lastTaken)
+	if err != nil || !lastTaken.Valid {
+		return time.Time{}, err
+	}
+
+	next := lastTaken.Time.Add(placementRetakeCooldown())
+	if time.Now().Before(next) {
+		return next, nil
+	}
+	return time.Time{}, nil
+}
+
+func placementQuizKey(userID string) string {
+	return "assessment:quiz:" + userID
+}
+
+// GetPlacementQuiz draws a random set of active questions across difficulty
+// levels and remembers the draw, so only these questions can be submitted.
+// Drawing again replaces the previous quiz. Correct answers are never sent to
+// the client.
+func GetPlacementQuiz(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	nextAttempt, err := checkPlacementCooldown(database.GetReadDBFor(userID), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !nextAttempt.IsZero() {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":           "Placement quiz was taken too recently",
+			"next_attempt_at": nextAttempt,
+		})
+		return
+	}
+
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT id, prompt, choices, difficulty_level, created_at
+		FROM (
+			SELECT *, row_number() OVER (PARTITION BY difficulty_level ORDER BY random()) AS rn
+			FROM assessment_questions WHERE is_active = true
+		) q
+		WHERE rn <= $1
+		ORDER BY difficulty_level, random()`,
+		quizQuestionsPerLevel,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get quiz"})
+		return
+	}
+	defer rows.Close()
+
+	questions := []models.AssessmentQuestion{}
+	drawn := []uuid.UUID{}
+	for rows.Next() {
+		var q models.AssessmentQuestion
+		if err := rows.Scan(&q.ID, &q.Prompt, pq.Array(&q.Choices), &q.DifficultyLevel, &q.CreatedAt); err != nil {
+			continue
+		}
+		questions = append(questions, q)
+		drawn = append(drawn, q.ID)
+	}
+	if len(questions) == 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "No quiz questions available"})
+		return
+	}
+
+	payload, _ := json.Marshal(drawn)
+	if err := database.GetRedis().Set(c.Request.Context(), placementQuizKey(userID), payload, placementQuizTTL).Err(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to start quiz"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"questions": questions, "expires_at": time.Now().Add(placementQuizTTL)})
+}
+
+// SubmitPlacementQuiz grades the quiz drawn by GetPlacementQuiz server-side,
+// places the user at a skill level and stores it on the profile. Every drawn
+// question must be answered, and the draw is consumed so it cannot be
+// resubmitted; retakes are limited by placementRetakeCooldown.
+func SubmitPlacementQuiz(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.QuizSubmission
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	nextAttempt, err := checkPlacementCooldown(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !nextAttempt.IsZero() {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":           "Placement quiz was taken too recently",
+			"next_attempt_at": nextAttempt,
+		})
+		return
+	}
+
+	payload, err := database.GetRedis().GetDel(c.Request.Context(), placementQuizKey(userID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusConflict, gin.H{"error": "No quiz in progress; request a new quiz"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to load quiz"})
+		return
+	}
+	var drawn []uuid.UUID
+	if err := json.Unmarshal(payload, &drawn); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Corrupt quiz"})
+		return
+	}
+
+	inQuiz := make(map[uuid.UUID]bool, len(drawn))
+	ids := make([]string, 0, len(drawn))
+	for _, id := range drawn {
+		inQuiz[id] = true
+		ids = append(ids, id.String())
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, a := range req.Answers {
+		if !inQuiz[a.QuestionID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Question not in this quiz: " + a.QuestionID.String()})
+			return
+		}
+		if seen[a.QuestionID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Each question may be answered only once"})
+			return
+		}
+		seen[a.QuestionID] = true
+	}
+	if len(seen) != len(drawn) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Every question in the quiz must be answered"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, correct_choice, difficulty_level
+		FROM assessment_questions
+		WHERE id = ANY($1)`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grade quiz"})
+		return
+	}
+	type key struct{ correct, level int }
+	answerKey := map[uuid.UUID]key{}
+	for rows.Next() {
+		var id uuid.UUID
+		var k key
+		if err := rows.Scan(&id, &k.correct, &k.level); err != nil {
+			rows.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grade quiz"})
+			return
+		}
+		answerKey[id] = k
+	}
+	rows.Close()
+
+	correct, total := map[int]int{}, map[int]int{}
+	result := models.AssessmentResult{Method: "quiz"}
+	for _, a := range req.Answers {
+		k, ok := answerKey[a.QuestionID]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown question: " + a.QuestionID.String()})
+			return
+		}
+		total[k.level]++
+		result.Total++
+		if a.Choice == k.correct {
+			correct[k.level]++
+			result.Correct++
+		}
+	}
+	result.SkillLevel = placeSkillLevel(correct, total)
+
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO assessment_results (user_id, method, correct, total, skill_level)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		userID, result.Method, result.Correct, result.Total, result.SkillLevel,
+	).Scan(&result.ID, &result.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save assessment"})
+		return
+	}
+	_, err = tx.Exec(
+		"UPDATE users SET skill_level = $1, skill_assessed_at = NOW(), updated_at = NOW() WHERE id = $2",
+		result.SkillLevel, userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save assessment"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save assessment"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetAssessmentHistory returns the user's skill level and past assessments
+func GetAssessmentHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	var skillLevel *int
+	if err := db.QueryRow("SELECT skill_level FROM users WHERE id = $1", userID).Scan(&skillLevel); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, method, correct, total, skill_level, created_at
+		FROM assessment_results WHERE user_id = $1
+		ORDER BY created_at DESC LIMIT 20`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get assessments"})
+		return
+	}
+	defer rows.Close()
+
+	results := []models.AssessmentResult{}
+	for rows.Next() {
+		var r models.AssessmentResult
+		if err := rows.Scan(&r.ID, &r.Method, &r.Correct, &r.Total, &r.SkillLevel, &r.CreatedAt); err != nil {
+			continue
+		}
+		results = append(results, r)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"skill_level": skillLevel, "assessments": results})
+}
+
+// GetRecommendedScores lists public scores within one difficulty level of the
+// user's skill level, most viewed first. Unassessed users get no filter.
+func GetRecommendedScores(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	var skillLevel *int
+	err = database.GetReadDBFor(userID).QueryRow("SELECT skill_level FROM users WHERE id = $1", userID).Scan(&skillLevel)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT `+publicScoreColumns+`
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.is_public = true AND s.is_draft = false AND s.deleted_at IS NULL AND u.is_active = true
+		  AND u.shadow_banned_at IS NULL AND s.user_id <> $1
+		  AND ($2::int IS NULL OR s.difficulty_level BETWEEN $2 - 1 AND $2 + 1)
+		ORDER BY s.view_count DESC, s.created_at DESC
+		LIMIT $3`,
+		userID, skillLevel, limit,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recommendations"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.PublicScore{}
+	for rows.Next() {
+		s, err := scanPublicScore(rows)
+		if err != nil {
+			continue
+		}
+		scores = append(scores, s)
+	}
+	if err := translateScores(c, db, scores); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recommendations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"skill_level": skillLevel, "scores": scores})
+}
+
+// ListAssessmentQuestions lists quiz questions with their answers (admin)
+func ListAssessmentQuestions(c *gin.Context) {
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT id, prompt, choices, correct_choice, difficulty_level, created_at
+		FROM assessment_questions WHERE is_active = true
+		ORDER BY difficulty_level, created_at`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get questions"})
+		return
+	}
+	defer rows.Close()
+
+	questions := []models.AssessmentQuestion{}
+	for rows.Next() {
+		var q models.AssessmentQuestion
+		if err := rows.Scan(&q.ID, &q.Prompt, pq.Array(&q.Choices), &q.CorrectChoice, &q.DifficultyLevel, &q.CreatedAt); err != nil {
+			continue
+		}
+		questions = append(questions, q)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"questions": questions})
+}
+
+// CreateAssessmentQuestion adds a placement quiz question (admin)
+func CreateAssessmentQuestion(c *gin.Context) {
+	var req models.AssessmentQuestionCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CorrectChoice >= len(req.Choices) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "correct_choice must index into choices"})
+		return
+	}
+
+	q := models.AssessmentQuestion{
+		Prompt:          req.Prompt,
+		Choices:         req.Choices,
+		CorrectChoice:   &req.CorrectChoice,
+		DifficultyLevel: req.DifficultyLevel,
+	}
+	err := database.GetDB().QueryRow(`
+		INSERT INTO assessment_questions (prompt, choices, correct_choice, difficulty_level, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		req.Prompt, pq.Array(req.Choices), req.CorrectChoice, req.DifficultyLevel, c.GetString("user_id"),
+	).Scan(&q.ID, &q.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create question"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, q)
+}
+
+// RetireAssessmentQuestion removes a question from future quizzes (admin).
+// Retired questions are kept so past results stay explainable.
+func RetireAssessmentQuestion(c *gin.Context) {
+	questionID := c.Param("id")
+	if _, err := uuid.Parse(questionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	result, err := database.GetDB().Exec(
+		"UPDATE assessment_questions SET is_active = false WHERE id = $1 AND is_active = true", questionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retire question"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Question retired"})
+}
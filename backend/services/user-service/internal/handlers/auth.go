@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"time"
+	"user-service/internal/analytics"
+	"user-service/internal/audit"
 	"user-service/internal/database"
 	"user-service/internal/models"
 	"user-service/internal/utils"
@@ -35,8 +37,8 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	// Check if username already exists
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)", req.Username).Scan(&exists)
+	// Check if username already exists or was recently released
+	exists, err = usernameUnavailable(db, req.Username, "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
 		return
@@ -46,6 +48,16 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	reserved, err := usernameReserved(db, req.Username, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if reserved {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username is reserved"})
+		return
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -55,12 +67,11 @@ func Register(c *gin.Context) {
 
 	// Create user
 	userID := uuid.New()
-	storageLimitMB := models.GetStorageLimit(models.TierFree)
 	
 	query := `
 		INSERT INTO users (id, email, username, password_hash, first_name, last_name, 
-						  subscription_tier, storage_limit_mb, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+						  subscription_tier, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, email, username, created_at`
 
 	var user models.User
@@ -68,7 +79,7 @@ func Register(c *gin.Context) {
 		userID, req.Email, req.Username, hashedPassword, 
 		sql.NullString{String: req.FirstName, Valid: req.FirstName != ""},
 		sql.NullString{String: req.LastName, Valid: req.LastName != ""},
-		models.TierFree, storageLimitMB, time.Now(), time.Now(),
+		models.TierFree, time.Now(), time.Now(),
 	).Scan(&user.ID, &user.Email, &user.Username, &user.CreatedAt)
 
 	if err != nil {
@@ -77,8 +88,12 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	// Pin the new user to the primary until replicas catch up
+	database.MarkWrite(user.ID.String())
+	analytics.Track(c.Request.Context(), analytics.Event{Name: analytics.EventSignup, UserID: user.ID.String()})
+
 	// Generate tokens
-	accessToken, refreshToken, err := utils.GenerateTokens(user.ID, user.Email, user.Username, "user")
+	accessToken, refreshToken, err := utils.GenerateTokens(user.ID, user.Email, user.Username, models.RoleUser)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
 		return
@@ -89,13 +104,13 @@ func Register(c *gin.Context) {
 		INSERT INTO refresh_tokens (user_id, token, expires_at, ip_address, user_agent)
 		VALUES ($1, $2, $3, $4, $5)`,
 		user.ID, refreshToken, time.Now().Add(7*24*time.Hour),
-		c.ClientIP(), c.Request.UserAgent(),
+		models.EncryptedString(c.ClientIP()), c.Request.UserAgent(),
 	)
 	if err != nil {
 		log.Printf("Failed to save refresh token: %v", err)
 	}
 
-	c.JSON(http.StatusCreated, models.TokenResponse{
+	respondWithTokens(c, http.StatusCreated, models.TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    "Bearer",
@@ -117,10 +132,10 @@ func Login(c *gin.Context) {
 	// Find user by email
 	var user models.User
 	err := db.QueryRow(`
-		SELECT id, email, username, password_hash, subscription_tier, is_active
+		SELECT id, email, username, password_hash, subscription_tier, is_active, role
 		FROM users WHERE email = $1`,
 		req.Email,
-	).Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.SubscriptionTier, &user.IsActive)
+	).Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.SubscriptionTier, &user.IsActive, &user.Role)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -139,6 +154,7 @@ func Login(c *gin.Context) {
 
 	// Verify password
 	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
+		audit.LogRequest(c, user.ID.String(), audit.EventLoginFailed, nil)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
@@ -148,9 +164,15 @@ func Login(c *gin.Context) {
 	if err != nil {
 		log.Printf("Failed to update last login: %v", err)
 	}
+	audit.LogRequest(c, user.ID.String(), audit.EventLogin, nil)
+	analytics.Track(c.Request.Context(), analytics.Event{
+		Name:       analytics.EventLogin,
+		UserID:     user.ID.String(),
+		Properties: models.JSONB{"tier": user.SubscriptionTier},
+	})
 
 	// Generate tokens
-	accessToken, refreshToken, err := utils.GenerateTokens(user.ID, user.Email, user.Username, "user")
+	accessToken, refreshToken, err := utils.GenerateTokens(user.ID, user.Email, user.Username, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
 		return
@@ -161,7 +183,7 @@ func Login(c *gin.Context) {
 		INSERT INTO refresh_tokens (user_id, token, expires_at, ip_address, user_agent)
 		VALUES ($1, $2, $3, $4, $5)`,
 		user.ID, refreshToken, time.Now().Add(7*24*time.Hour),
-		c.ClientIP(), c.Request.UserAgent(),
+		models.EncryptedString(c.ClientIP()), c.Request.UserAgent(),
 	)
 	if err != nil {
 		log.Printf("Failed to save refresh token: %v", err)
@@ -170,7 +192,7 @@ func Login(c *gin.Context) {
 	// Clear password hash before sending response
 	user.PasswordHash = ""
 
-	c.JSON(http.StatusOK, models.TokenResponse{
+	respondWithTokens(c, http.StatusOK, models.TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    "Bearer",
@@ -187,8 +209,13 @@ func RefreshToken(c *gin.Context) {
 		return
 	}
 
+	refreshSession(c, req.RefreshToken)
+}
+
+// refreshSession rotates a refresh token and responds with a new token pair
+func refreshSession(c *gin.Context, refreshToken string) {
 	// Validate refresh token
-	claims, err := utils.ValidateRefreshToken(req.RefreshToken)
+	claims, err := utils.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
 		return
@@ -201,7 +228,7 @@ func RefreshToken(c *gin.Context) {
 	err = db.QueryRow(`
 		SELECT is_revoked FROM refresh_tokens 
 		WHERE token = $1 AND user_id = $2`,
-		req.RefreshToken, claims.UserID,
+		refreshToken, claims.UserID,
 	).Scan(&isRevoked)
 
 	if err != nil || isRevoked {
@@ -212,10 +239,10 @@ func RefreshToken(c *gin.Context) {
 	// Get user info
 	var user models.User
 	err = db.QueryRow(`
-		SELECT id, email, username, subscription_tier 
+		SELECT id, email, username, subscription_tier, role 
 		FROM users WHERE id = $1`,
 		claims.UserID,
-	).Scan(&user.ID, &user.Email, &user.Username, &user.SubscriptionTier)
+	).Scan(&user.ID, &user.Email, &user.Username, &user.SubscriptionTier, &user.Role)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
@@ -223,7 +250,7 @@ func RefreshToken(c *gin.Context) {
 	}
 
 	// Generate new tokens
-	accessToken, newRefreshToken, err := utils.GenerateTokens(user.ID, user.Email, user.Username, "user")
+	accessToken, newRefreshToken, err := utils.GenerateTokens(user.ID, user.Email, user.Username, user.Role)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tokens"})
 		return
@@ -233,7 +260,7 @@ func RefreshToken(c *gin.Context) {
 	_, _ = db.Exec(`
 		UPDATE refresh_tokens SET is_revoked = true, revoked_at = $1 
 		WHERE token = $2`,
-		time.Now(), req.RefreshToken,
+		time.Now(), refreshToken,
 	)
 
 	// Save new refresh token
@@ -241,10 +268,10 @@ func RefreshToken(c *gin.Context) {
 		INSERT INTO refresh_tokens (user_id, token, expires_at, ip_address, user_agent)
 		VALUES ($1, $2, $3, $4, $5)`,
 		user.ID, newRefreshToken, time.Now().Add(7*24*time.Hour),
-		c.ClientIP(), c.Request.UserAgent(),
+		models.EncryptedString(c.ClientIP()), c.Request.UserAgent(),
 	)
 
-	c.JSON(http.StatusOK, models.TokenResponse{
+	respondWithTokens(c, http.StatusOK, models.TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: newRefreshToken,
 		TokenType:    "Bearer",
@@ -268,7 +295,9 @@ func Logout(c *gin.Context) {
 	if err != nil {
 		log.Printf("Failed to revoke tokens: %v", err)
 	}
+	audit.LogRequest(c, userID, audit.EventLogout, nil)
 
+	clearSessionCookies(c)
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
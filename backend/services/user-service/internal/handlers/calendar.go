@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/ics"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var icsWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func newCalendarNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// calendarFeedResponse returns the signed feed location for a user
+func calendarFeedResponse(userID, nonce string) gin.H {
+	path := "/api/v1/calendar/" + userID + "/feed.ics?sig=" + utils.SignCalendarFeed(userID, nonce)
+	resp := gin.H{"feed_path": path}
+	if base := os.Getenv("PUBLIC_BASE_URL"); base != "" {
+		resp["feed_url"] = strings.TrimRight(base, "/") + path
+	}
+	return resp
+}
+
+// GetCalendarFeed returns the current user's private ICS subscription URL,
+// creating it on first use
+func GetCalendarFeed(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	nonce, err := newCalendarNonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar feed"})
+		return
+	}
+
+	db := database.GetDB()
+	err = db.QueryRow(`
+		INSERT INTO calendar_feeds (user_id, nonce) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING nonce`,
+		userID, nonce,
+	).Scan(&nonce)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse(userID, nonce))
+}
+
+// ResetCalendarFeed issues a new feed URL, revoking the old one
+func ResetCalendarFeed(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	nonce, err := newCalendarNonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset calendar feed"})
+		return
+	}
+
+	db := database.GetDB()
+	_, err = db.Exec(`
+		INSERT INTO calendar_feeds (user_id, nonce) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET nonce = EXCLUDED.nonce, created_at = NOW()`,
+		userID, nonce,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse(userID, nonce))
+}
+
+// ServeCalendarFeed renders a user's scheduled practice blocks as ICS. It is
+// fetched by calendar apps, so it is authenticated by the URL signature alone.
+func ServeCalendarFeed(c *gin.Context) {
+	userID := c.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+		return
+	}
+
+	db := database.GetReadDB()
+	var nonce string
+	err := db.QueryRow(`
+		SELECT f.nonce FROM calendar_feeds f JOIN users u ON u.id = f.user_id
+		WHERE f.user_id = $1 AND u.is_active = true`,
+		userID,
+	).Scan(&nonce)
+	if err != nil || !utils.VerifyCalendarFeed(userID, nonce, c.Query("sig")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, label, days_of_week, time_of_day, timezone, duration_minutes, skip_until, created_at
+		FROM practice_reminders WHERE user_id = $1 AND enabled = true`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build calendar"})
+		return
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	events := []ics.Event{}
+	for rows.Next() {
+		var r models.PracticeReminder
+		if err := rows.Scan(&r.ID, &r.Label, pq.Array(&r.DaysOfWeek), &r.TimeOfDay, &r.Timezone,
+			&r.DurationMinutes, &r.SkipUntil, &r.CreatedAt); err != nil {
+			continue
+		}
+		schedule, err := models.ParseReminderSchedule(r.DaysOfWeek, r.TimeOfDay, r.Timezone)
+		if err != nil {
+			continue
+		}
+
+		days := make([]string, 0, len(r.DaysOfWeek))
+		for _, d := range r.DaysOfWeek {
+			days = append(days, icsWeekdays[d])
+		}
+
+		// Skipped occurrences of the current week
+		var exDates []time.Time
+		if r.SkipUntil != nil {
+			for t := schedule.Next(now); !t.IsZero() && t.Before(*r.SkipUntil); t = schedule.Next(t) {
+				exDates = append(exDates, t)
+			}
+		}
+
+		events = append(events, ics.Event{
+			UID:      "practice-" + r.ID.String() + "@genesis-music",
+			Summary:  r.Label,
+			Start:    schedule.Next(r.CreatedAt),
+			Duration: time.Duration(r.DurationMinutes) * time.Minute,
+			RRule:    "FREQ=WEEKLY;BYDAY=" + strings.Join(days, ","),
+			ExDates:  exDates,
+		})
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics.Calendar("Genesis Music practice", events)))
+}
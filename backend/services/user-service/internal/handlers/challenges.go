@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// challengeSelect selects challenges with participation counts and, when $1
+// is a user ID, that user's own enrollment
+const challengeSelect = `
+	SELECT c.id, c.title, c.description, c.kind, c.score_id, c.target_bpm,
+		c.starts_at, c.ends_at, c.created_at,
+		(SELECT COUNT(*) FROM challenge_enrollments e WHERE e.challenge_id = c.id),
+		(SELECT COUNT(*) FROM challenge_enrollments e WHERE e.challenge_id = c.id AND e.completed_at IS NOT NULL),
+		me.enrolled_at, me.completed_at
+	FROM challenges c
+	LEFT JOIN challenge_enrollments me ON me.challenge_id = c.id AND me.user_id = $1`
+
+func scanChallenge(row rowScanner) (models.Challenge, error) {
+	var ch models.Challenge
+	err := row.Scan(&ch.ID, &ch.Title, &ch.Description, &ch.Kind, &ch.ScoreID, &ch.TargetBPM,
+		&ch.StartsAt, &ch.EndsAt, &ch.CreatedAt, &ch.Enrolled, &ch.Completed,
+		&ch.EnrolledAt, &ch.CompletedAt)
+	return ch, err
+}
+
+const challengeSubmissionColumns = `
+	s.id, s.challenge_id, s.user_id, u.username, s.recording_url, s.achieved_bpm,
+	s.notes, s.status, s.reviewed_at, s.created_at`
+
+func scanChallengeSubmission(row rowScanner) (models.ChallengeSubmission, error) {
+	var s models.ChallengeSubmission
+	err := row.Scan(&s.ID, &s.ChallengeID, &s.UserID, &s.Username, &s.RecordingURL, &s.AchievedBPM,
+		&s.Notes, &s.Status, &s.ReviewedAt, &s.CreatedAt)
+	return s, err
+}
+
+// ListChallenges lists active challenges, or upcoming/past ones via ?status=
+func ListChallenges(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var window string
+	switch c.DefaultQuery("status", "active") {
+	case "active":
+		window = "c.starts_at <= NOW() AND c.ends_at > NOW() ORDER BY c.ends_at"
+	case "upcoming":
+		window = "c.starts_at > NOW() ORDER BY c.starts_at"
+	case "past":
+		window = "c.ends_at <= NOW() ORDER BY c.ends_at DESC"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be active, upcoming or past"})
+		return
+	}
+
+	db := database.GetReadDBFor(userID)
+	rows, err := db.Query(challengeSelect+" WHERE "+window+" LIMIT 50", userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get challenges"})
+		return
+	}
+	defer rows.Close()
+
+	challenges := []models.Challenge{}
+	for rows.Next() {
+		ch, err := scanChallenge(rows)
+		if err != nil {
+			continue
+		}
+		challenges = append(challenges, ch)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"challenges": challenges})
+}
+
+// GetChallenge returns a challenge with the caller's enrollment
+func GetChallenge(c *gin.Context) {
+	userID := c.GetString("user_id")
+	challengeID := c.Param("id")
+	if _, err := uuid.Parse(challengeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return
+	}
+
+	ch, err := scanChallenge(database.GetReadDBFor(userID).QueryRow(challengeSelect+" WHERE c.id = $2", userID, challengeID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get challenge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ch)
+}
+
+// EnrollChallenge enrolls the current user in a challenge that has not ended
+func EnrollChallenge(c *gin.Context) {
+	userID := c.GetString("user_id")
+	challengeID := c.Param("id")
+	if _, err := uuid.Parse(challengeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return
+	}
+
+	db := database.GetDB()
+	var open bool
+	err := db.QueryRow("SELECT ends_at > NOW() FROM challenges WHERE id = $1", challengeID).Scan(&open)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !open {
+		c.JSON(http.StatusConflict, gin.H{"error": "Challenge has ended"})
+		return
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO challenge_enrollments (challenge_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		challengeID, userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enroll in challenge"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	ch, err := scanChallenge(db.QueryRow(challengeSelect+" WHERE c.id = $2", userID, challengeID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get challenge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ch)
+}
+
+// SubmitChallenge records an attempt at an active challenge for admin review
+func SubmitChallenge(c *gin.Context) {
+	userID := c.GetString("user_id")
+	challengeID := c.Param("id")
+	if _, err := uuid.Parse(challengeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return
+	}
+
+	var req models.ChallengeSubmit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var active, completed, pending bool
+	err := db.QueryRow(`
+		SELECT c.starts_at <= NOW() AND c.ends_at > NOW(), e.completed_at IS NOT NULL,
+			EXISTS(SELECT 1 FROM challenge_submissions s
+				WHERE s.challenge_id = e.challenge_id AND s.user_id = e.user_id AND s.status = 'pending')
+		FROM challenge_enrollments e JOIN challenges c ON c.id = e.challenge_id
+		WHERE e.challenge_id = $1 AND e.user_id = $2`,
+		challengeID, userID,
+	).Scan(&active, &completed, &pending)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not enrolled in this challenge"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	switch {
+	case !active:
+		c.JSON(http.StatusConflict, gin.H{"error": "Challenge is not open for submissions"})
+		return
+	case completed:
+		c.JSON(http.StatusConflict, gin.H{"error": "Challenge already completed"})
+		return
+	case pending:
+		c.JSON(http.StatusConflict, gin.H{"error": "A submission is already awaiting review"})
+		return
+	}
+
+	var notes interface{}
+	if req.Notes != "" {
+		notes = req.Notes
+	}
+
+	var id uuid.UUID
+	err = db.QueryRow(`
+		INSERT INTO challenge_submissions (challenge_id, user_id, recording_url, achieved_bpm, notes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		challengeID, userID, req.RecordingURL, req.AchievedBPM, notes,
+	).Scan(&id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit attempt"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	s, err := scanChallengeSubmission(db.QueryRow(
+		"SELECT "+challengeSubmissionColumns+" FROM challenge_submissions s JOIN users u ON u.id = s.user_id WHERE s.id = $1", id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get submission"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, s)
+}
+
+// ListMyChallengeSubmissions lists the current user's submissions for a challenge
+func ListMyChallengeSubmissions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	challengeID := c.Param("id")
+	if _, err := uuid.Parse(challengeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return
+	}
+
+	listChallengeSubmissions(c, database.GetReadDBFor(userID), `
+		SELECT `+challengeSubmissionColumns+`
+		FROM challenge_submissions s JOIN users u ON u.id = s.user_id
+		WHERE s.challenge_id = $1 AND s.user_id = $2
+		ORDER BY s.created_at DESC`,
+		challengeID, userID,
+	)
+}
+
+// ListChallengeSubmissions lists submissions for a challenge, oldest first (admin)
+func ListChallengeSubmissions(c *gin.Context) {
+	challengeID := c.Param("id")
+	if _, err := uuid.Parse(challengeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return
+	}
+
+	listChallengeSubmissions(c, database.GetReadDB(), `
+		SELECT `+challengeSubmissionColumns+`
+		FROM challenge_submissions s JOIN users u ON u.id = s.user_id
+		WHERE s.challenge_id = $1 AND s.status = $2
+		ORDER BY s.created_at
+		LIMIT 100`,
+		challengeID, c.DefaultQuery("status", "pending"),
+	)
+}
+
+func listChallengeSubmissions(c *gin.Context, db *sql.DB, query string, args ...interface{}) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get submissions"})
+		return
+	}
+	defer rows.Close()
+
+	submissions := []models.ChallengeSubmission{}
+	for rows.Next() {
+		s, err := scanChallengeSubmission(rows)
+		if err != nil {
+			continue
+		}
+		submissions = append(submissions, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"submissions": submissions})
+}
+
+// PublishChallenge creates a challenge. Admins call it from /admin; scheduling
+// services call it from /internal and are recorded as the publisher.
+func PublishChallenge(c *gin.Context) {
+	var req models.ChallengeCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var description, createdBy, createdByService interface{}
+	if req.Description != "" {
+		description = req.Description
+	}
+	if userID := c.GetString("user_id"); userID != "" {
+		createdBy = userID
+	} else {
+		createdByService = c.GetString("service")
+	}
+
+	db := database.GetDB()
+	var id uuid.UUID
+	err := db.QueryRow(`
+		INSERT INTO challenges (title, description, kind, score_id, target_bpm, starts_at, ends_at, created_by, created_by_service)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		req.Title, description, req.Kind, req.ScoreID, req.TargetBPM, req.StartsAt, req.EndsAt, createdBy, createdByService,
+	).Scan(&id)
+	if err != nil {
+		log.Printf("Failed to publish challenge: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish challenge"})
+		return
+	}
+
+	ch, err := scanChallenge(db.QueryRow(challengeSelect+" WHERE c.id = $2", nil, id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get challenge"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ch)
+}
+
+// DeleteChallenge removes a challenge with its enrollments and submissions (admin)
+func DeleteChallenge(c *gin.Context) {
+	challengeID := c.Param("id")
+	if _, err := uuid.Parse(challengeID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return
+	}
+
+	result, err := database.GetDB().Exec("DELETE FROM challenges WHERE id = $1", challengeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete challenge"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Challenge deleted"})
+}
+
+// ReviewChallengeSubmission accepts or rejects a pending submission (admin).
+// Acceptance completes the enrollment, records it in the user's activity
+// feed and notifies the user.
+func ReviewChallengeSubmission(c *gin.Context) {
+	submissionID := c.Param("id")
+	if _, err := uuid.Parse(submissionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+
+	var req models.SubmissionReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := "rejected"
+	if req.Decision == "accept" {
+		status = "accepted"
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var userID, challengeID string
+	err = tx.QueryRow(`
+		UPDATE challenge_submissions
+		SET status = $1, reviewed_by = $2, reviewed_at = NOW()
+		WHERE id = $3 AND status = 'pending'
+		RETURNING user_id, challenge_id`,
+		status, c.GetString("user_id"), submissionID,
+	).Scan(&userID, &challengeID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending submission not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review submission"})
+		return
+	}
+
+	var title string
+	if status == "accepted" {
+		err = tx.QueryRow(`
+			UPDATE challenge_enrollments e SET completed_at = NOW()
+			FROM challenges c
+			WHERE c.id = e.challenge_id AND e.challenge_id = $1 AND e.user_id = $2
+			RETURNING c.title`,
+			challengeID, userID,
+		).Scan(&title)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review submission"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review submission"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	if status == "accepted" {
+		audit.LogRequest(c, userID, audit.EventChallengeCompleted, models.JSONB{
+			"challenge_id":  challengeID,
+			"submission_id": submissionID,
+		})
+		err := notify.Enqueue(context.Background(), notify.Notification{
+			Type:     notify.TypeChallengeCompleted,
+			UserID:   userID,
+			Channels: []string{models.ChannelPush, models.ChannelEmail},
+			Data:     map[string]interface{}{"challenge_id": challengeID, "title": title},
+		})
+		if err != nil {
+			log.Printf("Failed to enqueue challenge completion for %s: %v", userID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Submission " + status, "status": status})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+	"strconv"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const errorGroupColumns = `
+	id, fingerprint, error_type, message, release, status, occurrences, last_sample,
+	first_seen_at, last_seen_at`
+
+func scanErrorGroup(row rowScanner) (models.ErrorGroup, error) {
+	var g models.ErrorGroup
+	err := row.Scan(&g.ID, &g.Fingerprint, &g.Type, &g.Message, &g.Release, &g.Status, &g.Occurrences,
+		&g.LastSample, &g.FirstSeenAt, &g.LastSeenAt)
+	return g, err
+}
+
+// stripQuery drops the query string and fragment of a page URL, which may
+// carry tokens or search terms
+func stripQuery(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String()
+}
+
+// SubmitClientError records an anonymous frontend error report, folding it
+// into the group with the same fingerprint. A resolved group that recurs is
+// reopened as a regression.
+func SubmitClientError(c *gin.Context) {
+	var req models.ClientErrorReport
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	breadcrumbs := req.Breadcrumbs
+	if breadcrumbs == nil {
+		breadcrumbs = []models.Breadcrumb{}
+	}
+	sample := models.JSONB{
+		"stack":       req.Stack,
+		"url":         stripQuery(req.URL),
+		"user_agent":  c.Request.UserAgent(),
+		"breadcrumbs": breadcrumbs,
+	}
+	fingerprint := models.ErrorFingerprint(req)
+
+	_, err := database.GetDB().Exec(`
+		INSERT INTO client_error_groups (fingerprint, error_type, message, release, last_sample)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (fingerprint) DO UPDATE SET
+			occurrences = client_error_groups.occurrences + 1,
+			last_seen_at = NOW(),
+			release = COALESCE(EXCLUDED.release, client_error_groups.release),
+			last_sample = EXCLUDED.last_sample,
+			status = CASE WHEN client_error_groups.status = 'resolved' THEN 'open' ELSE client_error_groups.status END`,
+		fingerprint, nullIfEmpty(req.Type), req.Message, nullIfEmpty(req.Release), sample,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record error report"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"fingerprint": fingerprint})
+}
+
+// ListErrorGroups lists client error groups in a status (default open) for
+// triage, most recently seen first or with ?sort=occurrences most frequent first (admin)
+func ListErrorGroups(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	order := "last_seen_at DESC"
+	if c.Query("sort") == "occurrences" {
+		order = "occurrences DESC, last_seen_at DESC"
+	}
+
+	rows, err := database.GetReadDB().Query(`
+		SELECT `+errorGroupColumns+`
+		FROM client_error_groups
+		WHERE status = $1 AND ($2 = '' OR release = $2)
+		ORDER BY `+order+`
+		LIMIT $3 OFFSET $4`,
+		c.DefaultQuery("status", models.ErrorGroupOpen), c.Query("release"), limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get error groups"})
+		return
+	}
+	defer rows.Close()
+
+	groups := []models.ErrorGroup{}
+	for rows.Next() {
+		g, err := scanErrorGroup(rows)
+		if err != nil {
+			continue
+		}
+		groups = append(groups, g)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"error_groups": groups, "limit": limit, "offset": offset})
+}
+
+// UpdateErrorGroup sets an error group's triage status (admin)
+func UpdateErrorGroup(c *gin.Context) {
+	groupID := c.Param("id")
+	if _, err := uuid.Parse(groupID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid error group ID"})
+		return
+	}
+
+	var req models.ErrorGroupUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	g, err := scanErrorGroup(database.GetDB().QueryRow(`
+		UPDATE client_error_groups SET status = $1 WHERE id = $2
+		RETURNING `+errorGroupColumns,
+		req.Status, groupID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Error group not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update error group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, g)
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// creatorStatsDeltas selects the activity of the creator's ($1) scores, or
+// of one score ($2), on each snapshot day: the rise in each running total
+// since the score's previous snapshot. A score's first snapshot has none.
+// Totals that drop, such as unfavorites, count as no activity.
+const creatorStatsDeltas = `
+	WITH snapshots AS (
+		SELECT d.score_id, d.day, d.views, d.favorites, d.downloads, d.learners,
+			   LAG(d.views) OVER w AS prev_views, LAG(d.favorites) OVER w AS prev_favorites,
+			   LAG(d.downloads) OVER w AS prev_downloads, LAG(d.learners) OVER w AS prev_learners
+		FROM score_daily_stats d JOIN scores s ON s.id = d.score_id
+		WHERE s.user_id = $1 AND s.deleted_at IS NULL AND ($2 = '' OR d.score_id::text = $2)
+		WINDOW w AS (PARTITION BY d.score_id ORDER BY d.day)
+	), deltas AS (
+		SELECT score_id, day, views, favorites, downloads, learners,
+			   GREATEST(views - COALESCE(prev_views, views), 0) AS new_views,
+			   GREATEST(favorites - COALESCE(prev_favorites, favorites), 0) AS new_favorites,
+			   GREATEST(downloads - COALESCE(prev_downloads, downloads), 0) AS new_downloads,
+			   GREATEST(learners - COALESCE(prev_learners, learners), 0) AS new_learners
+		FROM snapshots
+	)`
+
+// creatorAnalyticsQuery parses the period and optional score of a creator
+// analytics request, writing the error response if they are invalid
+func creatorAnalyticsQuery(c *gin.Context) (days int, scoreID string, ok bool) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return 0, "", false
+	}
+	scoreID = c.Query("score_id")
+	if scoreID != "" {
+		if _, err := uuid.Parse(scoreID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+			return 0, "", false
+		}
+	}
+	if format := c.Query("format"); format != "" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv"})
+		return 0, "", false
+	}
+	return days, scoreID, true
+}
+
+// writeStatsCSV sends rows as a CSV attachment
+func writeStatsCSV(c *gin.Context, filename string, rows [][]string) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	w.WriteAll(rows)
+}
+
+// csvText keeps user-written text from being read as a formula by spreadsheets
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func statsColumns(s models.CreatorStats) []string {
+	return []string{
+		strconv.FormatInt(s.Views, 10), strconv.FormatInt(s.Favorites, 10),
+		strconv.FormatInt(s.Downloads, 10), strconv.FormatInt(s.Learners, 10),
+	}
+}
+
+// GetCreatorAnalytics returns the daily activity on the current user's public
+// scores, or on one of them with score_id, over the last days (default 30),
+// as JSON or with format=csv as a CSV file
+func GetCreatorAnalytics(c *gin.Context) {
+	userID := c.GetString("user_id")
+	days, scoreID, ok := creatorAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+	db := database.GetReadDBFor(userID)
+	if scoreID != "" {
+		var owned bool
+		if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM scores WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", scoreID, userID).Scan(&owned); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+			return
+		}
+		if !owned {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+			return
+		}
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-days)
+
+	rows, err := db.Query(creatorStatsDeltas+`
+		SELECT series.day::date,
+			   COALESCE(SUM(d.new_views), 0), COALESCE(SUM(d.new_favorites), 0),
+			   COALESCE(SUM(d.new_downloads), 0), COALESCE(SUM(d.new_learners), 0)
+		FROM generate_series($3::date, $4::date, interval '1 day') AS series(day)
+		LEFT JOIN deltas d ON d.day = series.day::date
+		GROUP BY series.day
+		ORDER BY series.day`,
+		userID, scoreID, since, today,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+		return
+	}
+	defer rows.Close()
+
+	daily := []models.CreatorDailyStats{}
+	var totals models.CreatorStats
+	for rows.Next() {
+		var d models.CreatorDailyStats
+		if err := rows.Scan(&d.Day, &d.Views, &d.Favorites, &d.Downloads, &d.Learners); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+			return
+		}
+		totals.Views += d.Views
+		totals.Favorites += d.Favorites
+		totals.Downloads += d.Downloads
+		totals.Learners += d.Learners
+		daily = append(daily, d)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		records := [][]string{{"day", "views", "favorites", "downloads", "learners"}}
+		for _, d := range daily {
+			records = append(records, append([]string{d.Day.Format("2006-01-02")}, statsColumns(d.CreatorStats)...))
+		}
+		writeStatsCSV(c, "analytics.csv", records)
+		return
+	}
+
+	response := gin.H{"days": days, "totals": totals, "daily": daily}
+	if scoreID != "" {
+		response["score_id"] = scoreID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCreatorScoreAnalytics ranks the current user's public scores by views
+// over the last days (default 30), with each score's running totals, as JSON
+// or with format=csv as a CSV file
+func GetCreatorScoreAnalytics(c *gin.Context) {
+	userID := c.GetString("user_id")
+	days, _, ok := creatorAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+
+	rows, err := database.GetReadDBFor(userID).Query(creatorStatsDeltas+`
+		SELECT d.score_id, s.title,
+			   COALESCE(SUM(d.new_views) FILTER (WHERE d.day >= $3), 0),
+			   COALESCE(SUM(d.new_favorites) FILTER (WHERE d.day >= $3), 0),
+			   COALESCE(SUM(d.new_downloads) FILTER (WHERE d.day >= $3), 0),
+			   COALESCE(SUM(d.new_learners) FILTER (WHERE d.day >= $3), 0),
+			   (array_agg(d.views ORDER BY d.day DESC))[1], (array_agg(d.favorites ORDER BY d.day DESC))[1],
+			   (array_agg(d.downloads ORDER BY d.day DESC))[1], (array_agg(d.learners ORDER BY d.day DESC))[1]
+		FROM deltas d JOIN scores s ON s.id = d.score_id
+		GROUP BY d.score_id, s.title
+		ORDER BY 3 DESC, d.score_id
+		LIMIT $4 OFFSET $5`,
+		userID, "", since, limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.CreatorScoreStats{}
+	for rows.Next() {
+		var s models.CreatorScoreStats
+		if err := rows.Scan(&s.ScoreID, &s.Title,
+			&s.Period.Views, &s.Period.Favorites, &s.Period.Downloads, &s.Period.Learners,
+			&s.Totals.Views, &s.Totals.Favorites, &s.Totals.Downloads, &s.Totals.Learners); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+			return
+		}
+		scores = append(scores, s)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get analytics"})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		records := [][]string{{"score_id", "title", "views", "favorites", "downloads", "learners",
+			"total_views", "total_favorites", "total_downloads", "total_learners"}}
+		for _, s := range scores {
+			record := append([]string{s.ScoreID.String(), csvText(s.Title)}, statsColumns(s.Period)...)
+			records = append(records, append(record, statsColumns(s.Totals)...))
+		}
+		writeStatsCSV(c, "score-analytics.csv", records)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"days": days, "scores": scores, "limit": limit, "offset": offset})
+}
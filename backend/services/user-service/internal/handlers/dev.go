@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"user-service/internal/database"
+	"user-service/internal/seed"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeedDatabase populates the database with development data. The route is only
+// registered outside production when ENABLE_DEV_ENDPOINTS=true.
+func SeedDatabase(c *gin.Context) {
+	var req struct {
+		Users         int   `json:"users"`
+		ScoresPerUser int   `json:"scores_per_user"`
+		Seed          int64 `json:"seed"`
+	}
+	req.Users, req.ScoresPerUser, req.Seed = 20, 5, 1
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	opts := seed.Options{Users: req.Users, ScoresPerUser: req.ScoresPerUser, Seed: req.Seed}
+
+	if opts.Users < 1 || opts.Users > 1000 || opts.ScoresPerUser < 0 || opts.ScoresPerUser > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "users must be 1-1000 and scores_per_user 0-100"})
+		return
+	}
+
+	result, err := seed.Run(c.Request.Context(), database.GetDB(), opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// secretRotationGrace is how long a rotated-out client secret keeps working
+const secretRotationGrace = 24 * time.Hour
+
+// validateRedirectURIs requires absolute https URIs without fragments
+// (plain http is allowed for localhost during development)
+func validateRedirectURIs(uris []string) error {
+	for _, raw := range uris {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid redirect URI: %s", raw)
+		}
+		if u.Fragment != "" {
+			return fmt.Errorf("redirect URI must not contain a fragment: %s", raw)
+		}
+		isLocal := u.Hostname() == "localhost" || u.Hostname() == "127.0.0.1"
+		if u.Scheme != "https" && !(u.Scheme == "http" && isLocal) {
+			return fmt.Errorf("redirect URI must use https: %s", raw)
+		}
+	}
+	return nil
+}
+
+func validateScopes(scopes []string) error {
+	for _, s := range scopes {
+		if !models.IsValidScope(s) {
+			return fmt.Errorf("unknown scope: %s", s)
+		}
+	}
+	return nil
+}
+
+func scanOAuthClient(row rowScanner) (models.OAuthClient, error) {
+	var app models.OAuthClient
+	err := row.Scan(&app.ID, &app.OwnerID, &app.Name, &app.ClientID,
+		pq.Array(&app.RedirectURIs), pq.Array(&app.Scopes), &app.CreatedAt, &app.UpdatedAt)
+	return app, err
+}
+
+const oauthClientColumns = `id, owner_id, name, client_id, redirect_uris, scopes, created_at, updated_at`
+
+// RegisterApp registers a third-party application and issues its credentials
+func RegisterApp(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.OAuthClientRegistration
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateRedirectURIs(req.RedirectURIs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateScopes(req.Scopes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clientID, secret, secretHash, err := utils.GenerateClientCredentials()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate credentials"})
+		return
+	}
+
+	db := database.GetDB()
+	app, err := scanOAuthClient(db.QueryRow(`
+		INSERT INTO oauth_clients (owner_id, name, client_id, client_secret_hash, redirect_uris, scopes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+oauthClientColumns,
+		userID, req.Name, clientID, secretHash, pq.Array(req.RedirectURIs), pq.Array(req.Scopes),
+	))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register application"})
+		return
+	}
+
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventAppRegistered, models.JSONB{"client_id": clientID})
+
+	c.JSON(http.StatusCreated, models.OAuthClientCredentials{OAuthClient: app, ClientSecret: secret})
+}
+
+// ListApps lists the current user's registered applications
+func ListApps(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	rows, err := db.Query(`
+		SELECT `+oauthClientColumns+` FROM oauth_clients
+		WHERE owner_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get applications"})
+		return
+	}
+	defer rows.Close()
+
+	apps := []models.OAuthClient{}
+	for rows.Next() {
+		app, err := scanOAuthClient(rows)
+		if err != nil {
+			continue
+		}
+		apps = append(apps, app)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"applications": apps})
+}
+
+// GetApp returns one of the current user's applications
+func GetApp(c *gin.Context) {
+	userID := c.GetString("user_id")
+	appID := c.Param("id")
+	if _, err := uuid.Parse(appID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	db := database.GetReadDBFor(userID)
+	app, err := scanOAuthClient(db.QueryRow(`
+		SELECT `+oauthClientColumns+` FROM oauth_clients
+		WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL`,
+		appID, userID,
+	))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
+
+// UpdateApp changes an application's name, redirect URIs or scopes
+func UpdateApp(c *gin.Context) {
+	userID := c.GetString("user_id")
+	appID := c.Param("id")
+	if _, err := uuid.Parse(appID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	var req models.OAuthClientUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.RedirectURIs != nil {
+		if err := validateRedirectURIs(req.RedirectURIs); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Scopes != nil {
+		if err := validateScopes(req.Scopes); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var redirectURIs, scopes interface{}
+	if req.RedirectURIs != nil {
+		redirectURIs = pq.Array(req.RedirectURIs)
+	}
+	if req.Scopes != nil {
+		scopes = pq.Array(req.Scopes)
+	}
+
+	db := database.GetDB()
+	app, err := scanOAuthClient(db.QueryRow(`
+		UPDATE oauth_clients
+		SET name = COALESCE($1, name),
+			redirect_uris = COALESCE($2, redirect_uris),
+			scopes = COALESCE($3, scopes)
+		WHERE id = $4 AND owner_id = $5 AND deleted_at IS NULL
+		RETURNING `+oauthClientColumns,
+		req.Name, redirectURIs, scopes, appID, userID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
+		}
+		return
+	}
+
+	database.MarkWrite(userID)
+	c.JSON(http.StatusOK, app)
+}
+
+// DeleteApp removes an application; its credentials stop working immediately
+func DeleteApp(c *gin.Context) {
+	userID := c.GetString("user_id")
+	appID := c.Param("id")
+	if _, err := uuid.Parse(appID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE oauth_clients SET deleted_at = NOW()
+		WHERE id = $1 AND owner_id = $2 AND deleted_at IS NULL`,
+		appID, userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete application"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventAppDeleted, models.JSONB{"application_id": appID})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Application deleted"})
+}
+
+// RotateAppSecret issues a new client secret. The previous secret keeps
+// working for a grace period so deployed clients can be updated.
+func RotateAppSecret(c *gin.Context) {
+	userID := c.GetString("user_id")
+	appID := c.Param("id")
+	if _, err := uuid.Parse(appID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	_, secret, secretHash, err := utils.GenerateClientCredentials()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate credentials"})
+		return
+	}
+
+	db := database.GetDB()
+	app, err := scanOAuthClient(db.QueryRow(`
+		UPDATE oauth_clients
+		SET previous_secret_hash = client_secret_hash,
+			previous_secret_expires_at = $1,
+			client_secret_hash = $2
+		WHERE id = $3 AND owner_id = $4 AND deleted_at IS NULL
+		RETURNING `+oauthClientColumns,
+		time.Now().Add(secretRotationGrace), secretHash, appID, userID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate secret"})
+		}
+		return
+	}
+
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventAppSecretRotated, models.JSONB{"client_id": app.ClientID})
+
+	c.JSON(http.StatusOK, gin.H{
+		"application":                models.OAuthClientCredentials{OAuthClient: app, ClientSecret: secret},
+		"previous_secret_expires_at": time.Now().Add(secretRotationGrace),
+	})
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"user-service/internal/analytics"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const experimentColumns = `
+	id, key, description, status, variants, weights, traffic_percent,
+	target_tiers, target_locales, created_at, updated_at`
+
+func scanExperiment(row rowScanner) (models.Experiment, error) {
+	var e models.Experiment
+	var names []string
+	var weights []int64
+	err := row.Scan(&e.ID, &e.Key, &e.Description, &e.Status, pq.Array(&names), pq.Array(&weights),
+		&e.TrafficPercent, pq.Array(&e.TargetTiers), pq.Array(&e.TargetLocales), &e.CreatedAt, &e.UpdatedAt)
+	e.Variants = make([]models.ExperimentVariant, 0, len(names))
+	for i, name := range names {
+		if i < len(weights) {
+			e.Variants = append(e.Variants, models.ExperimentVariant{Name: name, Weight: int(weights[i])})
+		}
+	}
+	if e.TargetTiers == nil {
+		e.TargetTiers = []string{}
+	}
+	if e.TargetLocales == nil {
+		e.TargetLocales = []string{}
+	}
+	return e, err
+}
+
+// variantColumns splits variants into the parallel name and weight arrays they are stored as
+func variantColumns(variants []models.ExperimentVariant) (interface{}, interface{}) {
+	names := make([]string, len(variants))
+	weights := make([]int64, len(variants))
+	for i, v := range variants {
+		names[i], weights[i] = v.Name, int64(v.Weight)
+	}
+	return pq.Array(names), pq.Array(weights)
+}
+
+// requestLocale returns the client's locale from ?locale= or the first
+// Accept-Language tag
+func requestLocale(c *gin.Context) string {
+	if locale := c.Query("locale"); locale != "" {
+		return locale
+	}
+	tag := strings.Split(c.GetHeader("Accept-Language"), ",")[0]
+	return strings.TrimSpace(strings.Split(tag, ";")[0])
+}
+
+// experimentAssignments returns the user's variant in every running experiment
+// that targets them. Users who opted out of analytics are not enrolled.
+func experimentAssignments(c *gin.Context, userID string) (map[string]string, error) {
+	assignments := map[string]string{}
+
+	optedOut, err := analytics.OptedOut(c.Request.Context(), userID)
+	if err != nil || optedOut {
+		return assignments, err
+	}
+
+	var tier string
+	db := database.GetReadDBFor(userID)
+	if err := db.QueryRow("SELECT subscription_tier FROM users WHERE id = $1", userID).Scan(&tier); err != nil {
+		return nil, err
+	}
+
+	rows, err := database.GetReadDB().Query(
+		"SELECT "+experimentColumns+" FROM experiments WHERE status = $1", models.ExperimentRunning)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locale := requestLocale(c)
+	for rows.Next() {
+		e, err := scanExperiment(rows)
+		if err != nil {
+			return nil, err
+		}
+		if !e.Targets(tier, locale) {
+			continue
+		}
+		if variant, ok := e.Assign(userID); ok {
+			assignments[e.Key] = variant
+		}
+	}
+	return assignments, rows.Err()
+}
+
+// GetExperimentAssignments returns the current user's variant for each running
+// experiment they are enrolled in, keyed by experiment key. ?locale= overrides
+// Accept-Language for locale targeting.
+func GetExperimentAssignments(c *gin.Context) {
+	assignments, err := experimentAssignments(c, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get experiments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments})
+}
+
+// LogExperimentExposure records that the client showed the user their variant.
+// The variant is recomputed server-side rather than trusted from the client.
+func LogExperimentExposure(c *gin.Context) {
+	userID := c.GetString("user_id")
+	key := c.Param("key")
+
+	assignments, err := experimentAssignments(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record exposure"})
+		return
+	}
+	variant, ok := assignments[key]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not enrolled in experiment"})
+		return
+	}
+
+	analytics.Track(c.Request.Context(), analytics.Event{
+		Name:       analytics.EventExperimentExposure,
+		UserID:     userID,
+		Properties: models.JSONB{"experiment": key, "variant": variant},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"experiment": key, "variant": variant})
+}
+
+// ListExperiments lists all experiments, newest first (admin)
+func ListExperiments(c *gin.Context) {
+	rows, err := database.GetReadDB().Query(
+		"SELECT " + experimentColumns + " FROM experiments ORDER BY created_at DESC")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get experiments"})
+		return
+	}
+	defer rows.Close()
+
+	experiments := []models.Experiment{}
+	for rows.Next() {
+		e, err := scanExperiment(rows)
+		if err != nil {
+			continue
+		}
+		experiments = append(experiments, e)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"experiments": experiments})
+}
+
+// validateExperimentInput checks what the binding tags cannot: unique variant names
+func validateExperimentInput(c *gin.Context, req models.ExperimentInput) bool {
+	seen := map[string]bool{}
+	for _, v := range req.Variants {
+		if seen[v.Name] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Variant names must be unique"})
+			return false
+		}
+		seen[v.Name] = true
+	}
+	return true
+}
+
+// withEmptyTargets replaces omitted targeting lists with empty ones; pq.Array
+// sends a nil slice as NULL, which the NOT NULL columns reject
+func withEmptyTargets(req models.ExperimentInput) models.ExperimentInput {
+	if req.TargetTiers == nil {
+		req.TargetTiers = []string{}
+	}
+	if req.TargetLocales == nil {
+		req.TargetLocales = []string{}
+	}
+	return req
+}
+
+// CreateExperiment creates an experiment (admin)
+func CreateExperiment(c *gin.Context) {
+	var req models.ExperimentCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !models.ValidExperimentKey(req.Key) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key may only contain lowercase letters, digits, '-' and '_'"})
+		return
+	}
+	if !validateExperimentInput(c, req.ExperimentInput) {
+		return
+	}
+	req.ExperimentInput = withEmptyTargets(req.ExperimentInput)
+
+	names, weights := variantColumns(req.Variants)
+	e, err := scanExperiment(database.GetDB().QueryRow(`
+		INSERT INTO experiments (key, description, status, variants, weights, traffic_percent,
+			target_tiers, target_locales, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (key) DO NOTHING
+		RETURNING `+experimentColumns,
+		req.Key, nullIfEmpty(req.Description), req.Status, names, weights, req.TrafficPercent,
+		pq.Array(req.TargetTiers), pq.Array(req.TargetLocales), c.GetString("user_id"),
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "An experiment with this key already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create experiment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, e)
+}
+
+// UpdateExperiment replaces an experiment's settings (admin). Changing
+// variants or weights of a running experiment reassigns users.
+func UpdateExperiment(c *gin.Context) {
+	experimentID := c.Param("id")
+	if _, err := uuid.Parse(experimentID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid experiment ID"})
+		return
+	}
+
+	var req models.ExperimentInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validateExperimentInput(c, req) {
+		return
+	}
+	req = withEmptyTargets(req)
+
+	names, weights := variantColumns(req.Variants)
+	e, err := scanExperiment(database.GetDB().QueryRow(`
+		UPDATE experiments
+		SET description = $1, status = $2, variants = $3, weights = $4, traffic_percent = $5,
+			target_tiers = $6, target_locales = $7
+		WHERE id = $8
+		RETURNING `+experimentColumns,
+		nullIfEmpty(req.Description), req.Status, names, weights, req.TrafficPercent,
+		pq.Array(req.TargetTiers), pq.Array(req.TargetLocales), experimentID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Experiment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update experiment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, e)
+}
+
+// DeleteExperiment removes an experiment (admin)
+func DeleteExperiment(c *gin.Context) {
+	experimentID := c.Param("id")
+	if _, err := uuid.Parse(experimentID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid experiment ID"})
+		return
+	}
+
+	result, err := database.GetDB().Exec("DELETE FROM experiments WHERE id = $1", experimentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete experiment"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Experiment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Experiment deleted"})
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// feedbackPerHour limits how many feedback reports one user may send per hour
+const feedbackPerHour = 10
+
+const feedbackColumns = `
+	f.id, f.user_id, u.username, f.category, f.message, f.client_version, f.platform,
+	f.page_url, f.screenshot_url, f.status, f.labels, f.created_at, f.updated_at`
+
+func scanFeedback(row rowScanner) (models.Feedback, error) {
+	var f models.Feedback
+	err := row.Scan(&f.ID, &f.UserID, &f.Username, &f.Category, &f.Message, &f.ClientVersion, &f.Platform,
+		&f.PageURL, &f.ScreenshotURL, &f.Status, pq.Array(&f.Labels), &f.CreatedAt, &f.UpdatedAt)
+	if f.Labels == nil {
+		f.Labels = []string{}
+	}
+	return f, err
+}
+
+// nullIfEmpty stores empty optional strings as NULL
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// SubmitFeedback files in-app feedback into the triage queue
+func SubmitFeedback(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.FeedbackSubmit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rate, err := ratelimit.Allow(c.Request.Context(), "feedback:"+userID, feedbackPerHour, time.Hour)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limiter unavailable"})
+		return
+	}
+	if !rate.Allowed {
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(rate.ResetAt).Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too much feedback sent, try again later"})
+		return
+	}
+
+	var id uuid.UUID
+	err = database.GetDB().QueryRow(`
+		INSERT INTO feedback (user_id, category, message, client_version, platform, page_url, screenshot_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		userID, req.Category, req.Message, nullIfEmpty(req.ClientVersion), nullIfEmpty(req.Platform),
+		nullIfEmpty(req.PageURL), nullIfEmpty(req.ScreenshotURL),
+	).Scan(&id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit feedback"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"id": id, "message": "Thanks for your feedback"})
+}
+
+// ListFeedback lists feedback for triage, oldest first (admin). Filters:
+// ?status= (default new), ?category= and ?label=.
+func ListFeedback(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	rows, err := database.GetReadDB().Query(`
+		SELECT `+feedbackColumns+`
+		FROM feedback f LEFT JOIN users u ON u.id = f.user_id
+		WHERE f.status = $1
+		  AND ($2 = '' OR f.category = $2)
+		  AND ($3 = '' OR $3 = ANY(f.labels))
+		ORDER BY f.created_at
+		LIMIT $4 OFFSET $5`,
+		c.DefaultQuery("status", "new"), c.Query("category"), strings.ToLower(c.Query("label")), limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get feedback"})
+		return
+	}
+	defer rows.Close()
+
+	feedback := []models.Feedback{}
+	for rows.Next() {
+		f, err := scanFeedback(rows)
+		if err != nil {
+			continue
+		}
+		feedback = append(feedback, f)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"feedback": feedback, "limit": limit, "offset": offset})
+}
+
+// TriageFeedback sets a feedback report's status and replaces its labels (admin).
+// Labels are lowercased so filters match regardless of case.
+func TriageFeedback(c *gin.Context) {
+	feedbackID := c.Param("id")
+	if _, err := uuid.Parse(feedbackID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback ID"})
+		return
+	}
+
+	var req models.FeedbackTriage
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var labels interface{}
+	if req.Labels != nil {
+		normalized := []string{}
+		for _, label := range req.Labels {
+			label = strings.ToLower(strings.TrimSpace(label))
+			if label != "" && !contains(normalized, label) {
+				normalized = append(normalized, label)
+			}
+		}
+		labels = pq.Array(normalized)
+	}
+
+	db := database.GetDB()
+	f, err := scanFeedback(db.QueryRow(`
+		WITH updated AS (
+			UPDATE feedback SET
+				status = COALESCE($1, status),
+				labels = COALESCE($2, labels),
+				triaged_by = $3
+			WHERE id = $4
+			RETURNING *
+		)
+		SELECT `+feedbackColumns+`
+		FROM updated f LEFT JOIN users u ON u.id = f.user_id`,
+		req.Status, labels, c.GetString("user_id"), feedbackID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update feedback"})
+		return
+	}
+
+	c.JSON(http.StatusOK, f)
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/feed"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedCacheTTL is how long rendered feeds are cached and may be cached by clients
+const feedCacheTTL = 5 * time.Minute
+
+// feedEntryLimit caps the number of entries in each feed
+const feedEntryLimit = 50
+
+// cachedFeed is a rendered feed with its validators
+type cachedFeed struct {
+	Body     []byte    `json:"body"`
+	ETag     string    `json:"etag"`
+	Modified time.Time `json:"modified"`
+}
+
+// feedFormat maps the requested file name to a feed format
+func feedFormat(file string) (format, contentType string, ok bool) {
+	switch file {
+	case "feed.atom":
+		return "atom", "application/atom+xml; charset=utf-8", true
+	case "feed.rss":
+		return "rss", "application/rss+xml; charset=utf-8", true
+	}
+	return "", "", false
+}
+
+// webAppURL returns the web app base used for links in feed entries
+func webAppURL() string {
+	if base := os.Getenv("WEB_APP_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return "http://localhost:5173"
+}
+
+// feedURL returns the canonical absolute URL of a feed under /api/v1/feeds. It
+// is built from PUBLIC_BASE_URL rather than the request so the feed ID does not
+// depend on the Host header or on how the client spelled the path.
+func feedURL(segments ...string) string {
+	base := "http://localhost:3000"
+	if configured := os.Getenv("PUBLIC_BASE_URL"); configured != "" {
+		base = strings.TrimRight(configured, "/")
+	}
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return base + "/api/v1/feeds/" + strings.Join(segments, "/")
+}
+
+// serveFeed renders a feed in the requested format, caching the result in
+// Redis and answering conditional requests with 304 Not Modified
+func serveFeed(c *gin.Context, cacheKey string, build func() (feed.Feed, bool, error)) {
+	format, contentType, ok := feedFormat(c.Param("file"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+	key := "feed:" + format + ":" + cacheKey
+
+	var cached cachedFeed
+	payload, err := database.GetRedis().Get(c.Request.Context(), key).Bytes()
+	if err != nil || json.Unmarshal(payload, &cached) != nil {
+		f, found, err := build()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+			return
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+			return
+		}
+
+		// Empty feeds have no entry dates to validate against
+		if f.Updated.IsZero() {
+			f.Updated = time.Now()
+		}
+
+		var body []byte
+		if format == "atom" {
+			body, err = feed.Atom(f)
+		} else {
+			body, err = feed.RSS(f)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		cached = cachedFeed{
+			Body:     body,
+			ETag:     `"` + hex.EncodeToString(sum[:16]) + `"`,
+			Modified: f.Updated.UTC().Truncate(time.Second),
+		}
+		if payload, err := json.Marshal(cached); err == nil {
+			if err := database.GetRedis().Set(c.Request.Context(), key, payload, feedCacheTTL).Err(); err != nil {
+				log.Printf("Failed to cache feed %s: %v", key, err)
+			}
+		}
+	}
+
+	c.Header("ETag", cached.ETag)
+	c.Header("Last-Modified", cached.Modified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=300")
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == cached.ETag || tag == "*" {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !cached.Modified.After(since) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, cached.Body)
+}
+
+// queryFeedEntries loads public scores as feed entries, newest first
+func queryFeedEntries(db *sql.DB, where string, args ...interface{}) ([]feed.Entry, time.Time, error) {
+	rows, err := db.Query(`
+		SELECT `+publicScoreColumns+`
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.is_public = true AND s.is_draft = false AND s.deleted_at IS NULL AND u.is_active = true
+		  AND u.shadow_banned_at IS NULL`+where+`
+		ORDER BY s.created_at DESC
+		LIMIT `+strconv.Itoa(feedEntryLimit),
+		args...,
+	)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var updated time.Time
+	entries := []feed.Entry{}
+	for rows.Next() {
+		s, err := scanPublicScore(rows)
+		if err != nil {
+			continue
+		}
+		if s.CreatedAt.After(updated) {
+			updated = s.CreatedAt
+		}
+		entries = append(entries, scoreFeedEntry(s))
+	}
+	return entries, updated, rows.Err()
+}
+
+// scoreFeedEntry describes a public score as a feed entry
+func scoreFeedEntry(s models.PublicScore) feed.Entry {
+	title := s.Title
+	var details []string
+	if s.Artist != nil && *s.Artist != "" {
+		title = *s.Artist + " - " + s.Title
+	}
+	for _, field := range []*string{s.Album, s.Genre, s.Tuning} {
+		if field != nil && *field != "" {
+			details = append(details, *field)
+		}
+	}
+	if s.DifficultyLevel != nil {
+		details = append(details, "Difficulty "+strconv.Itoa(*s.DifficultyLevel)+"/10")
+	}
+
+	return feed.Entry{
+		ID:         "urn:uuid:" + s.ID.String(),
+		Title:      title,
+		Link:       webAppURL() + "/viewer?score=" + s.ID.String(),
+		Summary:    strings.Join(details, " · "),
+		Author:     s.Owner,
+		Categories: s.Tags,
+		Published:  s.CreatedAt,
+		Updated:    s.CreatedAt,
+	}
+}
+
+// ServeSiteFeed serves the newest public transcriptions across the site
+func ServeSiteFeed(c *gin.Context) {
+	serveFeed(c, "site", func() (feed.Feed, bool, error) {
+		entries, updated, err := queryFeedEntries(database.GetReadDB(), "")
+		self := feedURL("scores", c.Param("file"))
+		return feed.Feed{
+			ID:       self,
+			Title:    "Genesis Music - New transcriptions",
+			Subtitle: "The newest public scores on Genesis Music",
+			Link:     webAppURL(),
+			Self:     self,
+			Updated:  updated,
+			Entries:  entries,
+		}, true, err
+	})
+}
+
+// ServeUserFeed serves a user's public scores. The user is resolved before
+// the cache is consulted so entries are keyed by the stored username and a
+// lookup that misses is never cached.
+func ServeUserFeed(c *gin.Context) {
+	db := database.GetReadDB()
+	var userID, username string
+	err := db.QueryRow(
+		"SELECT id, username FROM users WHERE username = $1 AND is_active = true AND shadow_banned_at IS NULL",
+		c.Param("username"),
+	).Scan(&userID, &username)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+
+	serveFeed(c, "user:"+username, func() (feed.Feed, bool, error) {
+		entries, updated, err := queryFeedEntries(db, " AND s.user_id = $1", userID)
+		self := feedURL("users", username, c.Param("file"))
+		return feed.Feed{
+			ID:      self,
+			Title:   username + " on Genesis Music",
+			Link:    webAppURL(),
+			Self:    self,
+			Updated: updated,
+			Entries: entries,
+		}, true, err
+	})
+}
+
+// ServeArtistFeed serves public scores of songs by an artist
+func ServeArtistFeed(c *gin.Context) {
+	artist := strings.TrimSpace(c.Param("artist"))
+	if artist == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+
+	// The match is case-insensitive, so the cache entry and feed ID are keyed
+	// by the lowercased name
+	key := strings.ToLower(artist)
+	serveFeed(c, "artist:"+key, func() (feed.Feed, bool, error) {
+		entries, updated, err := queryFeedEntries(database.GetReadDB(), " AND LOWER(s.artist) = $1", key)
+		self := feedURL("artists", key, c.Param("file"))
+		return feed.Feed{
+			ID:       self,
+			Title:    artist + " transcriptions on Genesis Music",
+			Subtitle: "New public scores of songs by " + artist,
+			Link:     webAppURL(),
+			Self:     self,
+			Updated:  updated,
+			Entries:  entries,
+		}, true, err
+	})
+}
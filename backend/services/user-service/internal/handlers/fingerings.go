@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"user-service/internal/database"
+	"user-service/internal/fingering"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fingeringScore is what the fingering endpoints need of a score
+type fingeringScore struct {
+	ownerID    string
+	visible    bool
+	tuning     string
+	capo       int
+	notes      []fingering.Note
+	fingerings []models.Fingering
+}
+
+// loadFingeringScore reads a score's notes, tuning and saved fingerings.
+// visible is true when anyone may read the score.
+func loadFingeringScore(db *sql.DB, scoreID string) (*fingeringScore, error) {
+	s := &fingeringScore{}
+	var notes, fingerings []byte
+	err := db.QueryRow(`
+		SELECT s.user_id, s.is_public = true AND s.is_draft IS NOT TRUE AND u.is_active = true
+				AND u.shadow_banned_at IS NULL,
+			   COALESCE(s.tuning, 'standard'), COALESCE(s.capo_position, 0),
+			   COALESCE(s.transcription_data->'notes', '[]'), s.fingerings
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL`,
+		scoreID,
+	).Scan(&s.ownerID, &s.visible, &s.tuning, &s.capo, &notes, &fingerings)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notes, err = fingering.ParseNotes(notes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fingerings, &s.fingerings); err != nil {
+		return nil, err
+	}
+	if s.fingerings == nil {
+		s.fingerings = []models.Fingering{}
+	}
+	return s, nil
+}
+
+// ownedFingeringScore loads a score for one of its owner's fingering edits,
+// writing the error response and returning nil when it cannot be edited
+func ownedFingeringScore(c *gin.Context, userID string) (*fingeringScore, []int) {
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return nil, nil
+	}
+
+	s, err := loadFingeringScore(database.GetDB(), scoreID)
+	if err == sql.ErrNoRows || (err == nil && s.ownerID != userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return nil, nil
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score"})
+		return nil, nil
+	}
+	tuning, err := fingering.ParseTuning(s.tuning)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Score tuning is not recognized", "tuning": s.tuning})
+		return nil, nil
+	}
+	return s, tuning
+}
+
+// GetScoreFingerings returns a score's saved fingerings. Public scores can be
+// read by anyone signed in; private ones only by their owner.
+func GetScoreFingerings(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	s, err := loadFingeringScore(database.GetReadDBFor(userID), scoreID)
+	if err == sql.ErrNoRows || (err == nil && !s.visible && s.ownerID != userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get fingerings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fingerings": s.fingerings, "tuning": s.tuning, "capo": s.capo})
+}
+
+// UpdateScoreFingerings replaces the fingerings of the current user's score.
+// Every fingering must play its note's pitch on the score's tuning and capo.
+func UpdateScoreFingerings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.FingeringUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, tuning := ownedFingeringScore(c, userID)
+	if s == nil {
+		return
+	}
+
+	seen := make(map[int]bool, len(req.Fingerings))
+	for i, f := range req.Fingerings {
+		if f.Note >= len(s.notes) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fingering refers to a note the score does not have", "index": i})
+			return
+		}
+		if seen[f.Note] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Note is fingered more than once", "index": i})
+			return
+		}
+		seen[f.Note] = true
+		if !fingering.Playable(tuning, s.capo, fingering.Position{String: f.String, Fret: f.Fret}, s.notes[f.Note].Pitch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fingering does not play the note's pitch", "index": i})
+			return
+		}
+	}
+	sort.Slice(req.Fingerings, func(a, b int) bool { return req.Fingerings[a].Note < req.Fingerings[b].Note })
+
+	payload, err := json.Marshal(req.Fingerings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fingerings"})
+		return
+	}
+	result, err := database.GetDB().Exec(
+		"UPDATE scores SET fingerings = $1 WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL", payload, c.Param("id"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save fingerings"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, gin.H{"fingerings": req.Fingerings, "tuning": s.tuning, "capo": s.capo})
+}
+
+// SuggestScoreFingerings proposes playable fingerings for a passage of the
+// current user's score without saving them; accepting them is a PUT. Saved
+// fingerings in the passage are kept and planned around unless overwrite is
+// set. Notes no string can play are listed as unplayable.
+func SuggestScoreFingerings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.FingeringSuggest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.StartTime != nil && req.EndTime != nil && *req.StartTime > *req.EndTime {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_time must not exceed end_time"})
+		return
+	}
+
+	s, tuning := ownedFingeringScore(c, userID)
+	if s == nil {
+		return
+	}
+
+	saved := make(map[int]fingering.Position, len(s.fingerings))
+	if !req.Overwrite {
+		for _, f := range s.fingerings {
+			saved[f.Note] = fingering.Position{String: f.String, Fret: f.Fret}
+		}
+	}
+
+	// The passage: notes starting in range, with saved positions as fixed
+	var indexes []int
+	var passage []fingering.Note
+	fixed := map[int]fingering.Position{}
+	for i, n := range s.notes {
+		if (req.StartTime != nil && n.Start < *req.StartTime) || (req.EndTime != nil && n.Start > *req.EndTime) {
+			continue
+		}
+		if pos, ok := saved[i]; ok {
+			fixed[len(passage)] = pos
+		}
+		indexes = append(indexes, i)
+		passage = append(passage, n)
+	}
+
+	positions := fingering.Suggest(passage, tuning, s.capo, fixed)
+	suggested := []models.Fingering{}
+	unplayable := []int{}
+	for k, pos := range positions {
+		if pos == nil {
+			unplayable = append(unplayable, indexes[k])
+			continue
+		}
+		suggested = append(suggested, models.Fingering{Note: indexes[k], String: pos.String, Fret: pos.Fret})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"fingerings":  suggested,
+		"unplayable":  unplayable,
+		"max_stretch": fingering.Span(passage, positions),
+		"tuning":      s.tuning,
+		"capo":        s.capo,
+	})
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/servicecall"
+
+	"github.com/gin-gonic/gin"
+)
+
+var startedAt = time.Now()
+
+// ReadinessReport is the /readyz payload shared by all backend services
+type ReadinessReport struct {
+	Service       string            `json:"service"`
+	Status        string            `json:"status"`
+	Version       string            `json:"version,omitempty"`
+	UptimeSeconds int64             `json:"uptime_seconds,omitempty"`
+	Dependencies  map[string]string `json:"dependencies,omitempty"`
+	Error         string            `json:"error,omitempty"`
+}
+
+func serviceVersion() string {
+	if v := os.Getenv("SERVICE_VERSION"); v != "" {
+		return v
+	}
+	return "dev"
+}
+
+// localReadiness checks this service's own dependencies
+func localReadiness(ctx context.Context) ReadinessReport {
+	report := ReadinessReport{
+		Service:       "user-service",
+		Status:        "healthy",
+		Version:       serviceVersion(),
+		UptimeSeconds: int64(time.Since(startedAt).Seconds()),
+		Dependencies:  map[string]string{},
+	}
+
+	if err := database.GetDB().PingContext(ctx); err != nil {
+		report.Dependencies["postgres"] = "unavailable"
+		report.Status = "unhealthy"
+	} else {
+		report.Dependencies["postgres"] = "ok"
+	}
+
+	if database.GetReadDB() != database.GetDB() {
+		if err := database.GetReadDB().PingContext(ctx); err != nil {
+			report.Dependencies["postgres_replica"] = "unavailable"
+			report.Status = "unhealthy"
+		} else {
+			report.Dependencies["postgres_replica"] = "ok"
+		}
+	}
+
+	if err := database.GetRedis().Ping(ctx).Err(); err != nil {
+		report.Dependencies["redis"] = "unavailable"
+		report.Status = "unhealthy"
+	} else {
+		report.Dependencies["redis"] = "ok"
+	}
+
+	return report
+}
+
+// Readyz reports whether this instance can serve traffic
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	report := localReadiness(ctx)
+	status := http.StatusOK
+	if report.Status != "healthy" {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// healthTargets parses HEALTH_CHECK_SERVICES ("name=http://host:port,...")
+func healthTargets() map[string]string {
+	targets := map[string]string{}
+	for _, entry := range strings.Split(os.Getenv("HEALTH_CHECK_SERVICES"), ",") {
+		name, url, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if ok && name != "" && url != "" {
+			targets[name] = strings.TrimRight(url, "/")
+		}
+	}
+	return targets
+}
+
+// fetchReadiness asks a service for its readiness report. Services without
+// /readyz are asked for /health, whose report has the same status field.
+// Health endpoints are unauthenticated, so no service identity is needed.
+func fetchReadiness(ctx context.Context, name, baseURL string) ReadinessReport {
+	resp, err := servicecall.Probe(ctx, baseURL+"/readyz")
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		resp, err = servicecall.Probe(ctx, baseURL+"/health")
+	}
+	if errors.Is(err, servicecall.ErrNotInitialized) {
+		return ReadinessReport{Service: name, Status: "not configured", Error: err.Error()}
+	}
+	if err != nil {
+		return ReadinessReport{Service: name, Status: "unreachable", Error: err.Error()}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ReadinessReport{Service: name, Status: "unhealthy", Error: "no /readyz or /health endpoint"}
+	}
+
+	var report ReadinessReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		report = ReadinessReport{Error: "invalid readiness response"}
+	}
+	report.Service = name
+	if resp.StatusCode != http.StatusOK && report.Status == "healthy" {
+		report.Status = "unhealthy"
+	}
+	if report.Status == "" {
+		report.Status = "unhealthy"
+	}
+	return report
+}
+
+// GetSystemHealth fans out to every backend service's /readyz, or /health
+// where there is none, and returns a consolidated report for the ops
+// dashboard. Services that are "not configured" do not count as unhealthy.
+func GetSystemHealth(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	targets := healthTargets()
+	reports := make([]ReadinessReport, 0, len(targets)+1)
+	reports = append(reports, localReadiness(ctx))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, url := range targets {
+		wg.Add(1)
+		go func(name, url string) {
+			defer wg.Done()
+			report := fetchReadiness(ctx, name, url)
+			mu.Lock()
+			reports = append(reports, report)
+			mu.Unlock()
+		}(name, url)
+	}
+	wg.Wait()
+
+	overall := "healthy"
+	unhealthy := 0
+	checked := 0
+	for _, r := range reports {
+		if r.Status == "not configured" {
+			continue
+		}
+		checked++
+		if r.Status != "healthy" {
+			unhealthy++
+		}
+	}
+	if unhealthy == checked {
+		overall = "unhealthy"
+	} else if unhealthy > 0 {
+		overall = "degraded"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":     overall,
+		"services":   reports,
+		"checked_at": time.Now().Unix(),
+	})
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+	"time"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// usernameChangeCooldown returns the minimum time between username changes
+func usernameChangeCooldown() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("USERNAME_CHANGE_COOLDOWN")); err == nil && d >= 0 {
+		return d
+	}
+	return 30 * 24 * time.Hour
+}
+
+// usernameRedirectGrace returns how long an old username stays reserved and
+// redirects to its owner's current profile
+func usernameRedirectGrace() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("USERNAME_REDIRECT_GRACE")); err == nil && d >= 0 {
+		return d
+	}
+	return 90 * 24 * time.Hour
+}
+
+// usernameUnavailable reports whether username is in use by, or was recently
+// released by, someone other than exceptUserID (empty for new accounts)
+func usernameUnavailable(db *sql.DB, username, exceptUserID string) (bool, error) {
+	var except interface{}
+	if exceptUserID != "" {
+		except = exceptUserID
+	}
+
+	var taken bool
+	err := db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM users WHERE username = $1 AND id IS DISTINCT FROM $2::uuid)
+			OR EXISTS(SELECT 1 FROM username_history
+					  WHERE username = $1 AND user_id IS DISTINCT FROM $2::uuid AND changed_at > $3)`,
+		username, except, time.Now().Add(-usernameRedirectGrace()),
+	).Scan(&taken)
+	return taken, err
+}
+
+// checkUsernameCooldown returns when the user may next change their username,
+// or the zero time if they may change it now
+func checkUsernameCooldown(db *sql.DB, userID string) (time.Time, error) {
+	var lastChange sql.NullTime
+	err := db.QueryRow(
+		"SELECT MAX(changed_at) FROM username_history WHERE user_id = $1",
+		userID,
+	).Scan(&lastChange)
+	if err != nil || !lastChange.Valid {
+		return time.Time{}, err
+	}
+
+	next := lastChange.Time.Add(usernameChangeCooldown())
+	if time.Now().Before(next) {
+		return next, nil
+	}
+	return time.Time{}, nil
+}
+
+// ChangeEmail changes the current user's email after re-checking their password.
+// The new address must be verified again.
+func ChangeEmail(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.EmailChange
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+
+	var currentEmail, currentHash string
+	err := db.QueryRow("SELECT email, password_hash FROM users WHERE id = $1", userID).Scan(&currentEmail, &currentHash)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !utils.CheckPasswordHash(req.CurrentPassword, currentHash) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	if req.NewEmail == currentEmail {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "New email is the same as the current one"})
+		return
+	}
+
+	var exists bool
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)", req.NewEmail).Scan(&exists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if exists {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE users SET email = $1, email_verified = false, email_verified_at = NULL, updated_at = NOW()
+		WHERE id = $2`,
+		req.NewEmail, userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email"})
+		return
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO email_history (user_id, email_encrypted) VALUES ($1, $2)",
+		userID, models.EncryptedString(currentEmail),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventEmailChanged, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email changed successfully", "email_verified": false})
+}
+
+// GetIdentityHistory lists the current user's previous usernames and emails
+func GetIdentityHistory(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	rows, err := db.Query(`
+		SELECT 'username', username, changed_at FROM username_history WHERE user_id = $1
+		UNION ALL
+		SELECT 'email', email_encrypted, changed_at FROM email_history WHERE user_id = $1
+		ORDER BY changed_at DESC`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get history"})
+		return
+	}
+	defer rows.Close()
+
+	history := []models.IdentityChange{}
+	for rows.Next() {
+		var h models.IdentityChange
+		var value models.EncryptedString
+		if err := rows.Scan(&h.Field, &value, &h.ChangedAt); err != nil {
+			log.Printf("Failed to read identity history: %v", err)
+			continue
+		}
+		h.OldValue = string(value)
+		history = append(history, h)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+// GetPublicProfile returns a user's public profile by username. Recently
+// changed usernames redirect to the owner's current profile.
+func GetPublicProfile(c *gin.Context) {
+	username := c.Param("username")
+	// Shadow-banned users still see their own profile
+	caller := nullIfEmpty(c.GetString("user_id"))
+
+	db := database.GetReadDB()
+	var user models.User
+	err := db.QueryRow(`
+		SELECT id, username, first_name, last_name, avatar_url, bio, subscription_tier,
+			   verified, verified_badge, created_at
+		FROM users WHERE username = $1 AND is_active = true AND (shadow_banned_at IS NULL OR id = $2)`,
+		username, caller,
+	).Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName,
+		&user.AvatarURL, &user.Bio, &user.SubscriptionTier,
+		&user.Verified, &user.VerifiedBadge, &user.CreatedAt)
+
+	if err == nil {
+		c.JSON(http.StatusOK, user.ToProfile())
+		return
+	}
+	if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var current string
+	err = db.QueryRow(`
+		SELECT u.username FROM username_history h
+		JOIN users u ON u.id = h.user_id
+		WHERE h.username = $1 AND h.changed_at > $2 AND u.is_active = true
+		  AND (u.shadow_banned_at IS NULL OR u.id = $3)
+		ORDER BY h.changed_at DESC LIMIT 1`,
+		username, time.Now().Add(-usernameRedirectGrace()), caller,
+	).Scan(&current)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/api/v1/profiles/"+current)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ImpersonateUser issues a super admin a short-lived access token for another
+// user, restricted to the requested scopes or read-only ones by default.
+// Staff accounts cannot be impersonated. Requests made with the token are
+// audit-logged with the super admin as the actor, and the user sees the
+// impersonation in their activity log.
+func ImpersonateUser(c *gin.Context) {
+	userID := c.Param("id")
+	target, err := uuid.Parse(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.ImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scopes := models.DefaultImpersonationScopes
+	if len(req.Scopes) > 0 {
+		scopes = []string{}
+		for _, scope := range req.Scopes {
+			if !models.IsValidScope(scope) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown scope: " + scope})
+				return
+			}
+			if !containsString(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	var email, username, role string
+	err = database.GetDB().QueryRow(
+		"SELECT email, username, role FROM users WHERE id = $1 AND is_active = true", userID,
+	).Scan(&email, &username, &role)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to impersonate user"})
+		return
+	}
+	if role != models.RoleUser {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Staff accounts cannot be impersonated"})
+		return
+	}
+
+	expiresAt := time.Now().Add(models.ImpersonationTTL)
+	token, err := utils.GenerateScopedAccessToken(target, email, username, role, scopes,
+		models.ImpersonationTTL, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to impersonate user"})
+		return
+	}
+	audit.LogRequest(c, userID, audit.EventImpersonationStarted, models.JSONB{
+		"reason": req.Reason, "scopes": scopes, "expires_at": expiresAt,
+	})
+
+	c.JSON(http.StatusOK, models.ImpersonationToken{
+		UserID:      target,
+		AccessToken: token,
+		TokenType:   "Bearer",
+		Scopes:      scopes,
+		ExpiresAt:   expiresAt,
+	})
+}
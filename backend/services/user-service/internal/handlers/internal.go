@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetInternalUser returns the account details other services need to authorize
+// work on a user's behalf (tier, quota, active state)
+func GetInternalUser(c *gin.Context) {
+	userID := c.Param("id")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	db := database.GetReadDB()
+	var user struct {
+		ID               string `json:"id"`
+		Username         string `json:"username"`
+		SubscriptionTier string `json:"subscription_tier"`
+		IsActive         bool   `json:"is_active"`
+		StorageUsedMB    int    `json:"storage_used_mb"`
+		StorageLimitMB   int    `json:"storage_limit_mb"`
+	}
+
+	err := db.QueryRow(`
+		SELECT id, username, subscription_tier, is_active, storage_used_mb
+		FROM users WHERE id = $1`,
+		userID,
+	).Scan(&user.ID, &user.Username, &user.SubscriptionTier, &user.IsActive,
+		&user.StorageUsedMB)
+
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	user.StorageLimitMB = models.GetTierCapabilities(user.SubscriptionTier).StorageLimitMB
+
+	c.JSON(http.StatusOK, user)
+}
+
+// GetTiers returns the tier capabilities table shared with media-service and billing
+func GetTiers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"tiers": models.AllTierCapabilities()})
+}
+
+// CheckUploadQuota validates a pending upload against the user's tier limits,
+// answering 403 with a structured quota error when it would exceed them
+func CheckUploadQuota(c *gin.Context) {
+	userID := c.Param("id")
+
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Quota checks must see the latest usage
+	db := database.GetDB()
+	var tier string
+	var storageUsed int
+	err := db.QueryRow(`
+		SELECT subscription_tier, storage_used_mb FROM users
+		WHERE id = $1 AND is_active = true`,
+		userID,
+	).Scan(&tier, &storageUsed)
+
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	caps := models.GetTierCapabilities(tier)
+	if quotaErr := caps.CheckUpload(req, storageUsed); quotaErr != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": quotaErr})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"allowed": true, "capabilities": caps})
+}
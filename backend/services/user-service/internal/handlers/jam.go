@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"user-service/internal/middleware"
+	"user-service/internal/models"
+	"user-service/internal/signaling"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+// CreateJamRoom creates a signaling room for peer-to-peer jamming
+func CreateJamRoom(c *gin.Context) {
+	var req models.JamRoomCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MaxPeers == 0 {
+		req.MaxPeers = 4
+	}
+
+	room, err := signaling.CreateRoom(c.Request.Context(), c.GetString("user_id"), req.Name, req.MaxPeers)
+	if err != nil {
+		log.Printf("Failed to create jam room: %v", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to create room"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, room)
+}
+
+// GetJamRoom returns a room and its connected peers
+func GetJamRoom(c *gin.Context) {
+	roomID := c.Param("id")
+	if _, err := uuid.Parse(roomID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	room, err := signaling.GetRoom(c.Request.Context(), roomID)
+	if errors.Is(err, signaling.ErrRoomNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to get room"})
+		return
+	}
+
+	peers, err := signaling.Peers(c.Request.Context(), roomID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to get room"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"room": room, "peers": peers})
+}
+
+// IssueJamTicket returns a single-use ticket for opening the room's WebSocket
+func IssueJamTicket(c *gin.Context) {
+	roomID := c.Param("id")
+	if _, err := uuid.Parse(roomID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid room ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := signaling.GetRoom(ctx, roomID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	ticket, expiresAt, err := signaling.IssueTicket(ctx, signaling.Ticket{
+		RoomID:   roomID,
+		UserID:   c.GetString("user_id"),
+		Username: c.GetString("username"),
+	})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to issue ticket"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"ticket":     ticket,
+		"expires_at": expiresAt,
+		"ws_path":    "/api/v1/jam/ws?ticket=" + ticket,
+	})
+}
+
+// JamWebSocket upgrades a ticketed request to the room's signaling WebSocket
+func JamWebSocket(c *gin.Context) {
+	ticket, err := signaling.RedeemTicket(c.Request.Context(), c.Query("ticket"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired ticket"})
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if origin := r.Header.Get("Origin"); origin != "" && !middleware.IsAllowedOrigin(origin) {
+				return fmt.Errorf("origin %q not allowed", origin)
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			signaling.Serve(ws, ticket)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// GetTURNCredentials issues short-lived TURN credentials for NAT traversal
+func GetTURNCredentials(c *gin.Context) {
+	cred, err := signaling.NewTURNCredential(c.GetString("user_id"))
+	if errors.Is(err, signaling.ErrTURNNotConfigured) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "TURN is not configured"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue TURN credentials"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cred)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/analytics"
+	"user-service/internal/database"
+	"user-service/internal/leaderboard"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLeaderboard returns the cached ranking for the current week or month,
+// along with the caller's own standing
+func GetLeaderboard(c *gin.Context) {
+	userID := c.GetString("user_id")
+	period := c.DefaultQuery("period", leaderboard.PeriodWeek)
+	metric := c.DefaultQuery("metric", leaderboard.MetricPracticeMinutes)
+
+	if !contains(leaderboard.Periods, period) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be week or month"})
+		return
+	}
+	if !contains(leaderboard.Metrics, metric) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "metric must be practice_minutes or songs_completed"})
+		return
+	}
+	if scope := c.DefaultQuery("scope", "global"); scope != "global" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only the global scope is available"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must not be negative"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	start := leaderboard.PeriodStart(period, time.Now())
+
+	entries, err := leaderboard.Top(ctx, period, metric, start, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Leaderboard unavailable"})
+		return
+	}
+
+	// Usernames are read live so renames and opt-outs apply before the next recompute
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.UserID)
+	}
+	usernames := map[string]string{}
+	if len(ids) > 0 {
+		rows, err := database.GetReadDB().Query(`
+			SELECT id, username FROM users
+			WHERE id = ANY($1::uuid[]) AND is_active = true AND leaderboard_opt_out = false
+			  AND shadow_banned_at IS NULL`,
+			pq.Array(ids),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get leaderboard"})
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id, username string
+			if err := rows.Scan(&id, &username); err == nil {
+				usernames[id] = username
+			}
+		}
+	}
+
+	ranked := make([]leaderboard.Entry, 0, len(entries))
+	for _, e := range entries {
+		if username, ok := usernames[e.UserID]; ok {
+			e.Username = username
+			ranked = append(ranked, e)
+		}
+	}
+
+	me, err := leaderboard.Standing(ctx, period, metric, start, userID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Leaderboard unavailable"})
+		return
+	}
+
+	resp := gin.H{
+		"period":       period,
+		"metric":       metric,
+		"scope":        "global",
+		"period_start": start,
+		"period_end":   leaderboard.PeriodEnd(period, start),
+		"entries":      ranked,
+		"me":           me,
+	}
+	if computedAt := leaderboard.ComputedAt(ctx, period, metric, start); !computedAt.IsZero() {
+		resp["computed_at"] = computedAt
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPrivacySettings returns the current user's privacy settings
+func GetPrivacySettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var settings models.PrivacySettings
+	err := database.GetReadDBFor(userID).QueryRow(
+		"SELECT leaderboard_opt_out, analytics_opt_out FROM users WHERE id = $1", userID,
+	).Scan(&settings.LeaderboardOptOut, &settings.AnalyticsOptOut)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdatePrivacySettings updates the current user's privacy settings
+func UpdatePrivacySettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.PrivacySettingsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var settings models.PrivacySettings
+	err := db.QueryRow(`
+		UPDATE users SET
+			leaderboard_opt_out = COALESCE($1, leaderboard_opt_out),
+			analytics_opt_out = COALESCE($2, analytics_opt_out),
+			updated_at = NOW()
+		WHERE id = $3
+		RETURNING leaderboard_opt_out, analytics_opt_out`,
+		req.LeaderboardOptOut, req.AnalyticsOptOut, userID,
+	).Scan(&settings.LeaderboardOptOut, &settings.AnalyticsOptOut)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update privacy settings"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	// Events recorded before opting out are removed while they can still be matched
+	if req.AnalyticsOptOut != nil && *req.AnalyticsOptOut {
+		if err := analytics.Forget(c.Request.Context(), userID); err != nil {
+			log.Printf("Failed to remove analytics events for %s: %v", userID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
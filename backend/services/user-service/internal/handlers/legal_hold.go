@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListLegalHolds lists accounts under legal hold, most recent first (super admin)
+func ListLegalHolds(c *gin.Context) {
+	rows, err := database.GetDB().Query(`
+		SELECT id, username, is_active, legal_hold_reason, legal_hold_by, legal_hold_at
+		FROM users WHERE legal_hold_at IS NOT NULL
+		ORDER BY legal_hold_at DESC`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get legal holds"})
+		return
+	}
+	defer rows.Close()
+
+	holds := []models.LegalHold{}
+	for rows.Next() {
+		var h models.LegalHold
+		if err := rows.Scan(&h.UserID, &h.Username, &h.IsActive, &h.Reason, &h.PlacedBy, &h.PlacedAt); err != nil {
+			continue
+		}
+		holds = append(holds, h)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"legal_holds": holds})
+}
+
+// PlaceLegalHold blocks deletion and anonymization of an account until the
+// hold is released (super admin)
+func PlaceLegalHold(c *gin.Context) {
+	userID := c.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.LegalHoldCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE users SET legal_hold_at = NOW(), legal_hold_reason = $1, legal_hold_by = $2
+		WHERE id = $3 AND legal_hold_at IS NULL`,
+		req.Reason, c.GetString("user_id"), userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to place legal hold"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found or already under legal hold"})
+		return
+	}
+	audit.LogRequest(c, userID, audit.EventLegalHoldPlaced, models.JSONB{"reason": req.Reason})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Legal hold placed"})
+}
+
+// ReleaseLegalHold lifts a legal hold, recording why it was released (super admin)
+func ReleaseLegalHold(c *gin.Context) {
+	userID := c.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.LegalHoldRelease
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var heldSince string
+	err := db.QueryRow(`
+		UPDATE users u SET legal_hold_at = NULL, legal_hold_reason = NULL, legal_hold_by = NULL
+		FROM (SELECT id, legal_hold_at FROM users WHERE id = $1 FOR UPDATE) old
+		WHERE u.id = old.id AND old.legal_hold_at IS NOT NULL
+		RETURNING old.legal_hold_at::text`,
+		userID,
+	).Scan(&heldSince)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User is not under legal hold"})
+		return
+	}
+	audit.LogRequest(c, userID, audit.EventLegalHoldReleased, models.JSONB{"reason": req.Reason, "held_since": heldSince})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Legal hold released"})
+}
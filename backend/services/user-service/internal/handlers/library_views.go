@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxLibraryViews caps how many saved views a user can keep
+const maxLibraryViews = 50
+
+const libraryViewColumns = "id, name, filters, pinned, created_at, updated_at"
+
+func scanLibraryView(row rowScanner) (models.LibraryView, error) {
+	var v models.LibraryView
+	var filters []byte
+	if err := row.Scan(&v.ID, &v.Name, &filters, &v.Pinned, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return v, err
+	}
+	err := json.Unmarshal(filters, &v.Filters)
+	return v, err
+}
+
+// listLibraryViews returns the user's saved views, pinned first
+func listLibraryViews(db *sql.DB, userID string, pinnedOnly bool) ([]models.LibraryView, error) {
+	rows, err := db.Query(`
+		SELECT `+libraryViewColumns+` FROM score_library_views
+		WHERE user_id = $1 AND (pinned OR NOT $2)
+		ORDER BY pinned DESC, LOWER(name)`,
+		userID, pinnedOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := []models.LibraryView{}
+	for rows.Next() {
+		v, err := scanLibraryView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}
+
+// validLibraryViewInput rejects filters the library endpoint would reject
+func validLibraryViewInput(c *gin.Context, req models.LibraryViewInput) bool {
+	return validLibraryFilters(c, req.Filters)
+}
+
+// validLibraryFilters rejects filter combinations binding cannot catch
+func validLibraryFilters(c *gin.Context, f models.LibraryFilters) bool {
+	if f.DifficultyMin != nil && f.DifficultyMax != nil && *f.DifficultyMin > *f.DifficultyMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "difficulty_min must not exceed difficulty_max"})
+		return false
+	}
+	return true
+}
+
+// ListLibraryViews lists the current user's saved library views
+func ListLibraryViews(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	views, err := listLibraryViews(database.GetReadDBFor(userID), userID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get views"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"views": views})
+}
+
+// CreateLibraryView saves a named set of library filters
+func CreateLibraryView(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.LibraryViewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validLibraryViewInput(c, req) {
+		return
+	}
+	filters, err := json.Marshal(req.Filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filters"})
+		return
+	}
+
+	db := database.GetDB()
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM score_library_views WHERE user_id = $1", userID).Scan(&count); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save view"})
+		return
+	}
+	if count >= maxLibraryViews {
+		c.JSON(http.StatusConflict, gin.H{"error": "Saved view limit reached", "limit": maxLibraryViews})
+		return
+	}
+
+	v, err := scanLibraryView(db.QueryRow(`
+		INSERT INTO score_library_views (user_id, name, filters, pinned)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING `+libraryViewColumns,
+		userID, req.Name, filters, req.Pinned,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "A view with this name already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save view"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusCreated, v)
+}
+
+// UpdateLibraryView replaces a saved view's name, filters and pin
+func UpdateLibraryView(c *gin.Context) {
+	userID := c.GetString("user_id")
+	viewID := c.Param("id")
+	if _, err := uuid.Parse(viewID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid view ID"})
+		return
+	}
+
+	var req models.LibraryViewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validLibraryViewInput(c, req) {
+		return
+	}
+	filters, err := json.Marshal(req.Filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filters"})
+		return
+	}
+
+	db := database.GetDB()
+	var taken bool
+	err = db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM score_library_views WHERE user_id = $1 AND name = $2 AND id <> $3)",
+		userID, req.Name, viewID,
+	).Scan(&taken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update view"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A view with this name already exists"})
+		return
+	}
+
+	v, err := scanLibraryView(db.QueryRow(`
+		UPDATE score_library_views SET name = $1, filters = $2, pinned = $3
+		WHERE id = $4 AND user_id = $5
+		RETURNING `+libraryViewColumns,
+		req.Name, filters, req.Pinned, viewID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update view"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, v)
+}
+
+// DeleteLibraryView removes a saved view
+func DeleteLibraryView(c *gin.Context) {
+	userID := c.GetString("user_id")
+	viewID := c.Param("id")
+	if _, err := uuid.Parse(viewID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid view ID"})
+		return
+	}
+
+	result, err := database.GetDB().Exec(
+		"DELETE FROM score_library_views WHERE id = $1 AND user_id = $2", viewID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete view"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "View deleted"})
+}
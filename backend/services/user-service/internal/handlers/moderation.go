@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListShadowBannedUsers lists shadow-banned users, most recent first (admin)
+func ListShadowBannedUsers(c *gin.Context) {
+	rows, err := database.GetReadDB().Query(`
+		SELECT id, username, shadow_ban_reason, shadow_banned_by, shadow_banned_at
+		FROM users WHERE shadow_banned_at IS NOT NULL
+		ORDER BY shadow_banned_at DESC
+		LIMIT 200`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shadow bans"})
+		return
+	}
+	defer rows.Close()
+
+	bans := []models.ShadowBan{}
+	for rows.Next() {
+		var b models.ShadowBan
+		if err := rows.Scan(&b.UserID, &b.Username, &b.Reason, &b.BannedBy, &b.BannedAt); err != nil {
+			continue
+		}
+		bans = append(bans, b)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shadow_bans": bans})
+}
+
+// ShadowBanUser hides a user's public content, song requests and profile from
+// everyone but the user (admin)
+func ShadowBanUser(c *gin.Context) {
+	userID := c.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.ShadowBanCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE users SET shadow_banned_at = NOW(), shadow_ban_reason = $1, shadow_banned_by = $2
+		WHERE id = $3 AND shadow_banned_at IS NULL`,
+		req.Reason, c.GetString("user_id"), userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to shadow-ban user"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found or already shadow-banned"})
+		return
+	}
+	audit.LogRequest(c, userID, audit.EventShadowBanApplied, models.JSONB{"reason": req.Reason})
+
+	c.JSON(http.StatusOK, gin.H{"message": "User shadow-banned"})
+}
+
+// LiftShadowBan makes a shadow-banned user's content visible again (admin)
+func LiftShadowBan(c *gin.Context) {
+	userID := c.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE users SET shadow_banned_at = NULL, shadow_ban_reason = NULL, shadow_banned_by = NULL
+		WHERE id = $1 AND shadow_banned_at IS NOT NULL`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to lift shadow ban"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shadow-banned user not found"})
+		return
+	}
+	audit.LogRequest(c, userID, audit.EventShadowBanLifted, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shadow ban lifted"})
+}
+
+// SearchAllContent searches every user's scores by title, artist, album,
+// description and audio filename, and transcription jobs by input filename
+// or URL, regardless of visibility. A reason (e.g. a report or DMCA claim
+// reference) is required and every search is audit logged (admin).
+func SearchAllContent(c *gin.Context) {
+	adminID := c.GetString("user_id")
+
+	q := strings.TrimSpace(c.Query("q"))
+	if len(q) < 2 || len(q) > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be between 2 and 200 characters"})
+		return
+	}
+	reason := strings.TrimSpace(c.Query("reason"))
+	if reason == "" || len(reason) > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required (at most 500 characters)"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	// Treat LIKE wildcards in the query literally
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q) + "%"
+
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT s.id, s.title, s.artist, s.album, s.description, s.original_audio_url,
+			   COALESCE(s.is_public, false), COALESCE(s.is_draft, false), u.id, u.username, s.created_at
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.title ILIKE $1 OR s.artist ILIKE $1 OR s.album ILIKE $1
+		   OR s.description ILIKE $1 OR s.original_audio_url ILIKE $1
+		ORDER BY s.created_at DESC
+		LIMIT $2`,
+		pattern, limit,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.ContentSearchScore{}
+	for rows.Next() {
+		var s models.ContentSearchScore
+		if err := rows.Scan(&s.ID, &s.Title, &s.Artist, &s.Album, &s.Description, &s.OriginalAudioURL,
+			&s.IsPublic, &s.IsDraft, &s.OwnerID, &s.Owner, &s.CreatedAt); err != nil {
+			continue
+		}
+		scores = append(scores, s)
+	}
+
+	jobRows, err := db.Query(`
+		SELECT j.id, j.status, j.input_filename, j.input_url, j.score_id, u.id, u.username, j.created_at
+		FROM transcription_jobs j JOIN users u ON u.id = j.user_id
+		WHERE j.input_filename ILIKE $1 OR j.input_url ILIKE $1
+		ORDER BY j.created_at DESC
+		LIMIT $2`,
+		pattern, limit,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+	defer jobRows.Close()
+
+	transcriptions := []models.ContentSearchTranscription{}
+	for jobRows.Next() {
+		var t models.ContentSearchTranscription
+		if err := jobRows.Scan(&t.ID, &t.Status, &t.InputFilename, &t.InputURL, &t.ScoreID,
+			&t.OwnerID, &t.Owner, &t.CreatedAt); err != nil {
+			continue
+		}
+		transcriptions = append(transcriptions, t)
+	}
+
+	// Searches are recorded against the searching admin
+	audit.LogRequest(c, adminID, audit.EventContentSearched, models.JSONB{
+		"query":          q,
+		"reason":         reason,
+		"scores":         len(scores),
+		"transcriptions": len(transcriptions),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"scores": scores, "transcriptions": transcriptions})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"user-service/internal/database"
+	"user-service/internal/encryption"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhookSecretPrefix marks the secrets job notification webhooks are signed with
+const webhookSecretPrefix = "gmws_"
+
+// newWebhookSecret returns a random webhook signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// scanJobNotificationSettings reads job_notify_email, job_webhook_url and
+// job_webhook_secret, decrypting the secret
+func scanJobNotificationSettings(row rowScanner) (models.JobNotificationSettings, error) {
+	var settings models.JobNotificationSettings
+	var secret sql.NullString
+	if err := row.Scan(&settings.Email, &settings.WebhookURL, &secret); err != nil {
+		return settings, err
+	}
+	if secret.Valid {
+		plain, err := encryption.Decrypt(secret.String)
+		if err != nil {
+			return settings, err
+		}
+		settings.WebhookSecret = plain
+	}
+	return settings, nil
+}
+
+// GetJobNotificationSettings returns how the current user is told that a
+// transcription or score export finished
+func GetJobNotificationSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	settings, err := scanJobNotificationSettings(database.GetReadDBFor(userID).QueryRow(
+		"SELECT job_notify_email, job_webhook_url, job_webhook_secret FROM users WHERE id = $1", userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get notification settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateJobNotificationSettings updates the current user's job notification
+// settings. Setting the first webhook generates the secret deliveries are
+// signed with; removing the webhook discards it.
+func UpdateJobNotificationSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.JobNotificationSettingsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var removeWebhook bool
+	var webhookURL, newSecret interface{}
+	if req.WebhookURL != nil {
+		if *req.WebhookURL == "" {
+			removeWebhook = true
+		} else {
+			parsed, err := url.Parse(*req.WebhookURL)
+			if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "webhook_url must be an https URL"})
+				return
+			}
+			webhookURL = *req.WebhookURL
+
+			secret, err := newWebhookSecret()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification settings"})
+				return
+			}
+			if newSecret, err = encryption.Encrypt(secret); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification settings"})
+				return
+			}
+		}
+	}
+
+	db := database.GetDB()
+	settings, err := scanJobNotificationSettings(db.QueryRow(`
+		UPDATE users SET
+			job_notify_email = COALESCE($1, job_notify_email),
+			job_webhook_url = CASE WHEN $2 THEN NULL ELSE COALESCE($3, job_webhook_url) END,
+			job_webhook_secret = CASE WHEN $2 THEN NULL
+				WHEN $3::text IS NOT NULL THEN COALESCE(job_webhook_secret, $4) ELSE job_webhook_secret END,
+			updated_at = NOW()
+		WHERE id = $5
+		RETURNING job_notify_email, job_webhook_url, job_webhook_secret`,
+		req.Email, removeWebhook, webhookURL, newSecret, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification settings"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	oauthCodeTTL         = 10 * time.Minute
+	oauthAccessTokenTTL  = time.Hour
+	oauthRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// oauthError writes an RFC 6749 error response
+func oauthError(c *gin.Context, status int, code, description string) {
+	c.JSON(status, gin.H{"error": code, "error_description": description})
+}
+
+// resolveAuthorizeRequest checks the client, redirect URI and requested scopes
+// of an authorization request. Requesting no scope grants all registered scopes.
+func resolveAuthorizeRequest(req models.OAuthAuthorizeRequest) (*models.OAuthClient, []string, string) {
+	db := database.GetReadDB()
+	app, err := scanOAuthClient(db.QueryRow(`
+		SELECT `+oauthClientColumns+` FROM oauth_clients
+		WHERE client_id = $1 AND deleted_at IS NULL`,
+		req.ClientID,
+	))
+	if err != nil {
+		return nil, nil, "Unknown client"
+	}
+
+	registered := false
+	for _, uri := range app.RedirectURIs {
+		if uri == req.RedirectURI {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return nil, nil, "Redirect URI is not registered for this client"
+	}
+
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		scopes = app.Scopes
+	}
+	for _, s := range scopes {
+		allowed := false
+		for _, r := range app.Scopes {
+			if s == r {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, nil, "Scope not allowed for this client: " + s
+		}
+	}
+
+	return &app, scopes, ""
+}
+
+// AuthorizeConsent returns what the consent screen should show for an
+// authorization request: the application and the scopes it asks for
+func AuthorizeConsent(c *gin.Context) {
+	var req models.OAuthAuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	app, scopes, problem := resolveAuthorizeRequest(req)
+	if app == nil {
+		oauthError(c, http.StatusBadRequest, "invalid_request", problem)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"application":  gin.H{"name": app.Name, "client_id": app.ClientID},
+		"scopes":       scopes,
+		"redirect_uri": req.RedirectURI,
+	})
+}
+
+// AuthorizeDecision records the user's consent decision and returns the URL to
+// send the browser to, carrying either an authorization code or an error
+func AuthorizeDecision(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.OAuthConsentDecision
+	if err := c.ShouldBindJSON(&req); err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	app, scopes, problem := resolveAuthorizeRequest(req.OAuthAuthorizeRequest)
+	if app == nil {
+		// Never redirect to an unverified URI
+		oauthError(c, http.StatusBadRequest, "invalid_request", problem)
+		return
+	}
+
+	redirect, _ := url.Parse(req.RedirectURI)
+	query := redirect.Query()
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+
+	if !req.Approve {
+		query.Set("error", "access_denied")
+		redirect.RawQuery = query.Encode()
+		c.JSON(http.StatusOK, gin.H{"redirect_to": redirect.String()})
+		return
+	}
+
+	code, codeHash, err := utils.GenerateOpaqueToken(utils.OAuthCodePrefix)
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "Failed to issue code")
+		return
+	}
+
+	db := database.GetDB()
+	_, err = db.Exec(`
+		INSERT INTO oauth_authorization_codes
+			(code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		codeHash, app.ID, userID, req.RedirectURI, pq.Array(scopes), req.CodeChallenge,
+		time.Now().Add(oauthCodeTTL),
+	)
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "Failed to issue code")
+		return
+	}
+
+	audit.LogRequest(c, userID, audit.EventAppAuthorized, models.JSONB{"client_id": app.ClientID, "scopes": scopes})
+
+	query.Set("code", code)
+	redirect.RawQuery = query.Encode()
+	c.JSON(http.StatusOK, gin.H{"redirect_to": redirect.String()})
+}
+
+// authenticateClient verifies client credentials from HTTP Basic auth or the
+// form body. A rotated-out secret is accepted until its grace period ends.
+func authenticateClient(c *gin.Context) (*models.OAuthClient, bool) {
+	clientID, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID = c.PostForm("client_id")
+		secret = c.PostForm("client_secret")
+	}
+	if clientID == "" || secret == "" {
+		return nil, false
+	}
+
+	db := database.GetDB()
+	var app models.OAuthClient
+	var secretHash string
+	var previousHash sql.NullString
+	var previousExpires sql.NullTime
+	err := db.QueryRow(`
+		SELECT id, client_id, scopes, client_secret_hash, previous_secret_hash, previous_secret_expires_at
+		FROM oauth_clients WHERE client_id = $1 AND deleted_at IS NULL`,
+		clientID,
+	).Scan(&app.ID, &app.ClientID, pq.Array(&app.Scopes), &secretHash, &previousHash, &previousExpires)
+	if err != nil {
+		return nil, false
+	}
+
+	hash := utils.HashAPIKey(secret)
+	if hash == secretHash {
+		return &app, true
+	}
+	if previousHash.Valid && hash == previousHash.String && previousExpires.Valid && time.Now().Before(previousExpires.Time) {
+		return &app, true
+	}
+	return nil, false
+}
+
+// Token implements the token endpoint for the authorization_code and refresh_token grants
+func Token(c *gin.Context) {
+	app, ok := authenticateClient(c)
+	if !ok {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		oauthError(c, http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+		return
+	}
+
+	switch c.PostForm("grant_type") {
+	case "authorization_code":
+		exchangeAuthorizationCode(c, app)
+	case "refresh_token":
+		exchangeRefreshToken(c, app)
+	default:
+		oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "Supported grants: authorization_code, refresh_token")
+	}
+}
+
+func exchangeAuthorizationCode(c *gin.Context, app *models.OAuthClient) {
+	code := c.PostForm("code")
+	if code == "" {
+		oauthError(c, http.StatusBadRequest, "invalid_request", "code is required")
+		return
+	}
+
+	grant, err := consumeAuthorizationCode(database.GetDB(), code, app)
+	if err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "Invalid or expired authorization code")
+		return
+	}
+
+	if c.PostForm("redirect_uri") != grant.redirectURI {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "redirect_uri does not match")
+		return
+	}
+	if !utils.VerifyPKCE(c.PostForm("code_verifier"), grant.challenge) {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "PKCE verification failed")
+		return
+	}
+
+	issueOAuthTokens(c, app, grant.userID, grant.scopes)
+}
+
+// authorizationCodeGrant is what an authorization code was issued for
+type authorizationCodeGrant struct {
+	userID      string
+	redirectURI string
+	challenge   string
+	scopes      []string
+}
+
+// consumeAuthorizationCode marks a code used atomically so it can only be exchanged once
+func consumeAuthorizationCode(db *sql.DB, code string, app *models.OAuthClient) (*authorizationCodeGrant, error) {
+	var grant authorizationCodeGrant
+	err := db.QueryRow(`
+		UPDATE oauth_authorization_codes SET used_at = NOW()
+		WHERE code_hash = $1 AND client_id = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id, redirect_uri, scopes, code_challenge`,
+		utils.HashAPIKey(code), app.ID,
+	).Scan(&grant.userID, &grant.redirectURI, pq.Array(&grant.scopes), &grant.challenge)
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+func exchangeRefreshToken(c *gin.Context, app *models.OAuthClient) {
+	refreshToken := c.PostForm("refresh_token")
+	if refreshToken == "" {
+		oauthError(c, http.StatusBadRequest, "invalid_request", "refresh_token is required")
+		return
+	}
+
+	db := database.GetDB()
+
+	// Rotate: the old token pair is revoked as the new one is issued
+	var userID string
+	var scopes []string
+	err := db.QueryRow(`
+		UPDATE oauth_tokens SET revoked_at = NOW()
+		WHERE refresh_token_hash = $1 AND client_id = $2
+		  AND revoked_at IS NULL AND refresh_expires_at > NOW()
+		RETURNING user_id, scopes`,
+		utils.HashAPIKey(refreshToken), app.ID,
+	).Scan(&userID, pq.Array(&scopes))
+	if err != nil {
+		oauthError(c, http.StatusBadRequest, "invalid_grant", "Invalid or expired refresh token")
+		return
+	}
+
+	// A narrower scope may be requested on refresh, never a wider one
+	if requested := strings.Fields(c.PostForm("scope")); len(requested) > 0 {
+		for _, s := range requested {
+			granted := false
+			for _, g := range scopes {
+				if s == g {
+					granted = true
+					break
+				}
+			}
+			if !granted {
+				oauthError(c, http.StatusBadRequest, "invalid_scope", "Scope exceeds the original grant: "+s)
+				return
+			}
+		}
+		scopes = requested
+	}
+
+	issueOAuthTokens(c, app, userID, scopes)
+}
+
+func issueOAuthTokens(c *gin.Context, app *models.OAuthClient, userID string, scopes []string) {
+	accessToken, accessHash, err := utils.GenerateOpaqueToken(utils.OAuthAccessTokenPrefix)
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "Failed to issue token")
+		return
+	}
+	refreshToken, refreshHash, err := utils.GenerateOpaqueToken(utils.OAuthRefreshTokenPrefix)
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "Failed to issue token")
+		return
+	}
+
+	db := database.GetDB()
+	now := time.Now()
+	_, err = db.Exec(`
+		INSERT INTO oauth_tokens
+			(access_token_hash, refresh_token_hash, client_id, user_id, scopes, expires_at, refresh_expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		accessHash, refreshHash, app.ID, userID, pq.Array(scopes),
+		now.Add(oauthAccessTokenTTL), now.Add(oauthRefreshTokenTTL),
+	)
+	if err != nil {
+		oauthError(c, http.StatusInternalServerError, "server_error", "Failed to issue token")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, models.OAuthTokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(oauthAccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        strings.Join(scopes, " "),
+	})
+}
+
+// Introspect implements RFC 7662 token introspection for the client's own tokens
+func Introspect(c *gin.Context) {
+	app, ok := authenticateClient(c)
+	if !ok {
+		oauthError(c, http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+		return
+	}
+
+	token := c.PostForm("token")
+	hash := utils.HashAPIKey(token)
+
+	db := database.GetDB()
+	var userID string
+	var scopes []string
+	var expiresAt time.Time
+	var issuedAt time.Time
+	err := db.QueryRow(`
+		SELECT user_id, scopes, expires_at, created_at FROM oauth_tokens
+		WHERE access_token_hash = $1 AND client_id = $2
+		  AND revoked_at IS NULL AND expires_at > NOW()`,
+		hash, app.ID,
+	).Scan(&userID, pq.Array(&scopes), &expiresAt, &issuedAt)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"active": false})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"active":     true,
+		"scope":      strings.Join(scopes, " "),
+		"client_id":  app.ClientID,
+		"sub":        userID,
+		"token_type": "Bearer",
+		"exp":        expiresAt.Unix(),
+		"iat":        issuedAt.Unix(),
+	})
+}
+
+// Revoke implements RFC 7009 token revocation. Revoking either token of a pair
+// revokes both. The response is the same whether or not the token existed.
+func Revoke(c *gin.Context) {
+	app, ok := authenticateClient(c)
+	if !ok {
+		oauthError(c, http.StatusUnauthorized, "invalid_client", "Client authentication failed")
+		return
+	}
+
+	hash := utils.HashAPIKey(c.PostForm("token"))
+	db := database.GetDB()
+	_, err := db.Exec(`
+		UPDATE oauth_tokens SET revoked_at = NOW()
+		WHERE (access_token_hash = $1 OR refresh_token_hash = $1)
+		  AND client_id = $2 AND revoked_at IS NULL`,
+		hash, app.ID,
+	)
+	if err != nil {
+		oauthError(c, http.StatusServiceUnavailable, "temporarily_unavailable", "Failed to revoke token")
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
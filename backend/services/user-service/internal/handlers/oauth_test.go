@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// codeStore stands in for oauth_authorization_codes: it honours the
+// used_at IS NULL guard of the consuming UPDATE and nothing else
+type codeStore struct {
+	mu    sync.Mutex
+	codes map[string]*storedCode
+}
+
+type storedCode struct {
+	clientID string
+	used     bool
+}
+
+func (s *codeStore) Open(string) (driver.Conn, error) { return &codeConn{store: s}, nil }
+
+type codeConn struct{ store *codeStore }
+
+func (c *codeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *codeConn) Close() error                        { return nil }
+func (c *codeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *codeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "used_at IS NULL") {
+		return nil, errors.New("code consumption is not guarded on used_at")
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	code, ok := c.store.codes[args[0].Value.(string)]
+	if !ok || code.used || code.clientID != args[1].Value.(string) {
+		return &codeRows{}, nil
+	}
+	code.used = true
+	return &codeRows{row: []driver.Value{"user-1", "https://app.example/cb", []byte("{scores:read}"), "challenge"}}, nil
+}
+
+type codeRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *codeRows) Columns() []string {
+	return []string{"user_id", "redirect_uri", "scopes", "code_challenge"}
+}
+func (r *codeRows) Close() error { return nil }
+func (r *codeRows) Next(dest []driver.Value) error {
+	if r.row == nil || r.done {
+		return io.EOF
+	}
+	copy(dest, r.row)
+	r.done = true
+	return nil
+}
+
+func openCodeStore(t *testing.T, store *codeStore) *sql.DB {
+	t.Helper()
+	name := "oauth-codes-" + t.Name()
+	sql.Register(name, store)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestConsumeAuthorizationCode(t *testing.T) {
+	app := &models.OAuthClient{ID: uuid.New()}
+	other := &models.OAuthClient{ID: uuid.New()}
+
+	tests := []struct {
+		name     string
+		code     string
+		client   *models.OAuthClient
+		seedUsed bool
+		exchange int
+		wantOK   []bool
+	}{
+		{"fresh code exchanges once", "gmac_fresh", app, false, 1, []bool{true}},
+		{"reused code is rejected", "gmac_reused", app, false, 2, []bool{true, false}},
+		{"already used code is rejected", "gmac_used", app, true, 1, []bool{false}},
+		{"code for another client is rejected", "gmac_other", other, false, 1, []bool{false}},
+		{"unknown code is rejected", "gmac_unknown", app, false, 1, []bool{false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &codeStore{codes: map[string]*storedCode{}}
+			if tt.code != "gmac_unknown" {
+				store.codes[utils.HashAPIKey(tt.code)] = &storedCode{clientID: app.ID.String(), used: tt.seedUsed}
+			}
+			db := openCodeStore(t, store)
+
+			for i := 0; i < tt.exchange; i++ {
+				grant, err := consumeAuthorizationCode(db, tt.code, tt.client)
+				if ok := err == nil; ok != tt.wantOK[i] {
+					t.Fatalf("exchange %d: ok = %v, want %v (err %v)", i+1, ok, tt.wantOK[i], err)
+				}
+				if err == nil && (grant.userID != "user-1" || len(grant.scopes) != 1 || grant.scopes[0] != "scores:read") {
+					t.Errorf("exchange %d: unexpected grant %+v", i+1, grant)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/analytics"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/transcription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const publicScoreColumns = `
+	s.id, s.title, s.artist, s.artist_id, s.album, s.genre, s.year, s.difficulty_level,
+	s.key_signature, s.time_signature, s.tempo, s.tuning, s.tags, u.username, s.description, s.created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPublicScore(row rowScanner) (models.PublicScore, error) {
+	var s models.PublicScore
+	err := row.Scan(&s.ID, &s.Title, &s.Artist, &s.ArtistID, &s.Album, &s.Genre, &s.Year, &s.DifficultyLevel,
+		&s.KeySignature, &s.TimeSignature, &s.Tempo, &s.Tuning, pq.Array(&s.Tags), &s.Owner, &s.Description, &s.CreatedAt)
+	if s.Tags == nil {
+		s.Tags = []string{}
+	}
+	return s, err
+}
+
+// ListPublicScores lists public scores, optionally filtered by artist or tag
+func ListPublicScores(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT `+publicScoreColumns+`
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.is_public = true AND s.is_draft = false AND s.deleted_at IS NULL AND u.is_active = true
+		  AND (u.shadow_banned_at IS NULL OR u.id::text = $5)
+		  AND ($1 = '' OR s.artist ILIKE $1)
+		  AND ($2 = '' OR $2 = ANY(s.tags))
+		ORDER BY s.created_at DESC
+		LIMIT $3 OFFSET $4`,
+		c.Query("artist"), c.Query("tag"), limit, offset, c.GetString("user_id"),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.PublicScore{}
+	for rows.Next() {
+		s, err := scanPublicScore(rows)
+		if err != nil {
+			continue
+		}
+		scores = append(scores, s)
+	}
+	if err := translateScores(c, db, scores); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scores": scores, "limit": limit, "offset": offset})
+}
+
+// GetPublicScore returns a single public score
+func GetPublicScore(c *gin.Context) {
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	db := database.GetReadDB()
+	score, err := scanPublicScore(db.QueryRow(`
+		SELECT `+publicScoreColumns+`
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.is_public = true AND s.is_draft = false AND s.deleted_at IS NULL AND u.is_active = true
+		  AND (u.shadow_banned_at IS NULL OR u.id::text = $2)`,
+		scoreID, c.GetString("user_id"),
+	))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	scores := []models.PublicScore{score}
+	if err := translateScores(c, db, scores); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score"})
+		return
+	}
+	if scores[0].Locale != "" {
+		c.Header("Content-Language", scores[0].Locale)
+	}
+
+	c.JSON(http.StatusOK, scores[0])
+}
+
+// SubmitTranscription queues a transcription job on behalf of the API key owner
+func SubmitTranscription(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.TranscriptionSubmit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var tier string
+	if err := db.QueryRow("SELECT subscription_tier FROM users WHERE id = $1", userID).Scan(&tier); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	var job models.TranscriptionJob
+	err := db.QueryRow(`
+		INSERT INTO transcription_jobs (user_id, input_type, input_url, transcription_settings, input_metadata, priority)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, progress, input_type, input_url, created_at`,
+		userID, req.InputType, req.InputURL, req.Settings,
+		models.JSONB{"source": "public_api", "api_key_id": c.GetString("api_key_id")},
+		models.GetTierCapabilities(tier).TranscriptionPriority,
+	).Scan(&job.ID, &job.Status, &job.Progress, &job.InputType, &job.InputURL, &job.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit transcription"})
+		return
+	}
+	analytics.Track(c.Request.Context(), analytics.Event{
+		Name:       analytics.EventTranscriptionSubmitted,
+		UserID:     userID,
+		Properties: models.JSONB{"source": "public_api", "input_type": req.InputType},
+	})
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetTranscription returns the status of one of the API key owner's
+// transcription jobs: its place in the queue while it waits, then the stage
+// it is in
+func GetTranscription(c *gin.Context) {
+	userID := c.GetString("user_id")
+	jobID := c.Param("id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	db := database.GetDB()
+	job, err := transcription.ScanJob(db.QueryRow(
+		"SELECT "+transcription.JobColumns+" FROM transcription_jobs WHERE id = $1 AND user_id = $2",
+		jobID, userID,
+	))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.Status == models.TranscriptionPending {
+		position, err := transcriptionQueuePosition(db, jobID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+			return
+		}
+		job.QueuePosition = &position
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchPublicContent searches public scores by title or artist and profiles
+// by username prefix
+func SearchPublicContent(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if len(q) < 2 || len(q) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be between 2 and 100 characters"})
+		return
+	}
+	// Treat LIKE wildcards in the query literally
+	pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q)
+
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT `+publicScoreColumns+`
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.is_public = true AND s.is_draft = false AND s.deleted_at IS NULL AND u.is_active = true
+		  AND u.shadow_banned_at IS NULL
+		  AND (s.title ILIKE $1 OR s.artist ILIKE $1
+			   OR EXISTS (SELECT 1 FROM score_translations t WHERE t.score_id = s.id AND t.title ILIKE $1))
+		ORDER BY s.view_count DESC, s.created_at DESC
+		LIMIT 20`,
+		"%"+pattern+"%",
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.PublicScore{}
+	for rows.Next() {
+		s, err := scanPublicScore(rows)
+		if err != nil {
+			continue
+		}
+		scores = append(scores, s)
+	}
+	if err := translateScores(c, db, scores); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+
+	userRows, err := db.Query(`
+		SELECT id, username, first_name, last_name, avatar_url, bio, subscription_tier,
+			   verified, verified_badge, created_at
+		FROM users WHERE username ILIKE $1 AND is_active = true AND shadow_banned_at IS NULL
+		ORDER BY verified DESC, username
+		LIMIT 10`,
+		pattern+"%",
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Search failed"})
+		return
+	}
+	defer userRows.Close()
+
+	profiles := []*models.UserProfile{}
+	for userRows.Next() {
+		var user models.User
+		if err := userRows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName,
+			&user.AvatarURL, &user.Bio, &user.SubscriptionTier,
+			&user.Verified, &user.VerifiedBadge, &user.CreatedAt); err != nil {
+			continue
+		}
+		profiles = append(profiles, user.ToProfile())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scores": scores, "profiles": profiles})
+}
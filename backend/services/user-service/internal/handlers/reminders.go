@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const reminderColumns = `
+	id, label, days_of_week, time_of_day, timezone, duration_minutes, channels,
+	enabled, next_fire_at, snoozed_until, skip_until, last_sent_at, created_at`
+
+func scanReminder(row rowScanner) (models.PracticeReminder, error) {
+	var r models.PracticeReminder
+	err := row.Scan(&r.ID, &r.Label, pq.Array(&r.DaysOfWeek), &r.TimeOfDay, &r.Timezone, &r.DurationMinutes,
+		pq.Array(&r.Channels), &r.Enabled, &r.NextFireAt, &r.SnoozedUntil, &r.SkipUntil, &r.LastSentAt, &r.CreatedAt)
+	return r, err
+}
+
+// bindReminder validates a reminder request, returning its schedule and next occurrence
+func bindReminder(c *gin.Context) (*models.PracticeReminderInput, *models.ReminderSchedule, bool) {
+	var req models.PracticeReminderInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+
+	schedule, err := models.ParseReminderSchedule(req.DaysOfWeek, req.TimeOfDay, req.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+
+	if req.DurationMinutes == 0 {
+		req.DurationMinutes = 30
+	}
+	if len(req.Channels) == 0 {
+		req.Channels = []string{models.ChannelPush}
+	}
+	return &req, schedule, true
+}
+
+// ListReminders lists the current user's practice reminders
+func ListReminders(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	rows, err := db.Query(
+		"SELECT "+reminderColumns+" FROM practice_reminders WHERE user_id = $1 ORDER BY created_at",
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reminders"})
+		return
+	}
+	defer rows.Close()
+
+	reminders := []models.PracticeReminder{}
+	for rows.Next() {
+		r, err := scanReminder(rows)
+		if err != nil {
+			continue
+		}
+		reminders = append(reminders, r)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reminders": reminders})
+}
+
+// CreateReminder schedules a recurring practice reminder
+func CreateReminder(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	req, schedule, ok := bindReminder(c)
+	if !ok {
+		return
+	}
+	enabled := req.Enabled == nil || *req.Enabled
+
+	var nextFire interface{}
+	if enabled {
+		nextFire = schedule.Next(time.Now())
+	}
+
+	db := database.GetDB()
+	r, err := scanReminder(db.QueryRow(`
+		INSERT INTO practice_reminders
+			(user_id, label, days_of_week, time_of_day, timezone, duration_minutes, channels, enabled, next_fire_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+reminderColumns,
+		userID, req.Label, pq.Array(req.DaysOfWeek), req.TimeOfDay, req.Timezone, req.DurationMinutes,
+		pq.Array(req.Channels), enabled, nextFire,
+	))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reminder"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusCreated, r)
+}
+
+// UpdateReminder replaces a reminder's schedule, clearing any snooze or skip
+func UpdateReminder(c *gin.Context) {
+	userID := c.GetString("user_id")
+	reminderID := c.Param("id")
+	if _, err := uuid.Parse(reminderID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder ID"})
+		return
+	}
+
+	req, schedule, ok := bindReminder(c)
+	if !ok {
+		return
+	}
+	enabled := req.Enabled == nil || *req.Enabled
+
+	var nextFire interface{}
+	if enabled {
+		nextFire = schedule.Next(time.Now())
+	}
+
+	db := database.GetDB()
+	r, err := scanReminder(db.QueryRow(`
+		UPDATE practice_reminders
+		SET label = $1, days_of_week = $2, time_of_day = $3, timezone = $4, duration_minutes = $5,
+			channels = $6, enabled = $7, next_fire_at = $8, snoozed_until = NULL, skip_until = NULL
+		WHERE id = $9 AND user_id = $10
+		RETURNING `+reminderColumns,
+		req.Label, pq.Array(req.DaysOfWeek), req.TimeOfDay, req.Timezone, req.DurationMinutes,
+		pq.Array(req.Channels), enabled, nextFire, reminderID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reminder"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, r)
+}
+
+// DeleteReminder deletes a practice reminder
+func DeleteReminder(c *gin.Context) {
+	userID := c.GetString("user_id")
+	reminderID := c.Param("id")
+	if _, err := uuid.Parse(reminderID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder ID"})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec("DELETE FROM practice_reminders WHERE id = $1 AND user_id = $2", reminderID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete reminder"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder deleted"})
+}
+
+// SnoozeReminder postpones the next reminder by the given number of minutes.
+// A snooze that ends inside a skipped week resumes after the skip instead.
+func SnoozeReminder(c *gin.Context) {
+	userID := c.GetString("user_id")
+	reminderID := c.Param("id")
+	if _, err := uuid.Parse(reminderID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder ID"})
+		return
+	}
+
+	var req models.ReminderSnooze
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	until := time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+
+	db := database.GetDB()
+	current, err := scanReminder(db.QueryRow(
+		"SELECT "+reminderColumns+" FROM practice_reminders WHERE id = $1 AND user_id = $2 AND enabled = true",
+		reminderID, userID,
+	))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+
+	nextFire := until
+	if current.SkipUntil != nil && current.SkipUntil.After(until) {
+		schedule, err := models.ParseReminderSchedule(current.DaysOfWeek, current.TimeOfDay, current.Timezone)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid stored schedule"})
+			return
+		}
+		nextFire = schedule.NextOutsideSkip(until, current.SkipUntil)
+	}
+
+	r, err := scanReminder(db.QueryRow(`
+		UPDATE practice_reminders SET snoozed_until = $1, next_fire_at = $2
+		WHERE id = $3 AND user_id = $4 AND enabled = true
+		RETURNING `+reminderColumns,
+		until, nextFire, reminderID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to snooze reminder"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, r)
+}
+
+// SkipReminderWeek skips the rest of this week's reminders (weeks start on Monday
+// in the reminder's timezone)
+func SkipReminderWeek(c *gin.Context) {
+	userID := c.GetString("user_id")
+	reminderID := c.Param("id")
+	if _, err := uuid.Parse(reminderID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reminder ID"})
+		return
+	}
+
+	db := database.GetDB()
+	current, err := scanReminder(db.QueryRow(
+		"SELECT "+reminderColumns+" FROM practice_reminders WHERE id = $1 AND user_id = $2 AND enabled = true",
+		reminderID, userID,
+	))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reminder not found"})
+		return
+	}
+
+	schedule, err := models.ParseReminderSchedule(current.DaysOfWeek, current.TimeOfDay, current.Timezone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid stored schedule"})
+		return
+	}
+	skipUntil := schedule.StartOfNextWeek(time.Now())
+	nextFire := schedule.Next(skipUntil.Add(-time.Second))
+
+	r, err := scanReminder(db.QueryRow(`
+		UPDATE practice_reminders SET skip_until = $1, next_fire_at = $2, snoozed_until = NULL
+		WHERE id = $3 AND user_id = $4
+		RETURNING `+reminderColumns,
+		skipUntil, nextFire, reminderID, userID,
+	))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to skip reminders"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// usernameReserved reports whether username is reserved and not granted to userID
+func usernameReserved(db *sql.DB, username, userID string) (bool, error) {
+	var except interface{}
+	if userID != "" {
+		except = userID
+	}
+
+	var reserved bool
+	err := db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM reserved_usernames
+					  WHERE username = LOWER($1) AND granted_to IS DISTINCT FROM $2::uuid)`,
+		username, except,
+	).Scan(&reserved)
+	return reserved, err
+}
+
+// ListReservedUsernames lists all reserved usernames (admin)
+func ListReservedUsernames(c *gin.Context) {
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT username, reason, granted_to, granted_at, created_by, created_at
+		FROM reserved_usernames ORDER BY username`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reserved usernames"})
+		return
+	}
+	defer rows.Close()
+
+	reserved := []models.ReservedUsername{}
+	for rows.Next() {
+		var r models.ReservedUsername
+		if err := rows.Scan(&r.Username, &r.Reason, &r.GrantedTo, &r.GrantedAt, &r.CreatedBy, &r.CreatedAt); err != nil {
+			continue
+		}
+		reserved = append(reserved, r)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reserved_usernames": reserved})
+}
+
+// ReserveUsername adds a username to the reserved list (admin). Names already
+// in use stay with their current owner.
+func ReserveUsername(c *gin.Context) {
+	var req models.ReservedUsernameCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var reason interface{}
+	if req.Reason != "" {
+		reason = req.Reason
+	}
+
+	db := database.GetDB()
+	var r models.ReservedUsername
+	err := db.QueryRow(`
+		INSERT INTO reserved_usernames (username, reason, created_by)
+		VALUES (LOWER($1), $2, $3)
+		ON CONFLICT (username) DO NOTHING
+		RETURNING username, reason, granted_to, granted_at, created_by, created_at`,
+		req.Username, reason, c.GetString("user_id"),
+	).Scan(&r.Username, &r.Reason, &r.GrantedTo, &r.GrantedAt, &r.CreatedBy, &r.CreatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username is already reserved"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reserve username"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, r)
+}
+
+// ReleaseUsername removes a username from the reserved list (admin)
+func ReleaseUsername(c *gin.Context) {
+	db := database.GetDB()
+	result, err := db.Exec("DELETE FROM reserved_usernames WHERE username = LOWER($1)", c.Param("username"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release username"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reserved username not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Username released"})
+}
+
+// GrantReservedUsername assigns a reserved username to a verified account and
+// renames the account to it (admin). The change bypasses the username cooldown.
+func GrantReservedUsername(c *gin.Context) {
+	username := strings.ToLower(c.Param("username"))
+
+	var req models.ReservedUsernameGrant
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := req.UserID.String()
+
+	db := database.GetDB()
+
+	var oldUsername string
+	var verified bool
+	err := db.QueryRow("SELECT username, verified FROM users WHERE id = $1 AND is_active = true", userID).
+		Scan(&oldUsername, &verified)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if !verified {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Reserved usernames can only be granted to verified accounts"})
+		return
+	}
+
+	taken, err := usernameUnavailable(db, username, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username is in use by another account"})
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE reserved_usernames SET granted_to = $1, granted_at = NOW()
+		WHERE username = $2`,
+		userID, username,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant username"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reserved username not found"})
+		return
+	}
+
+	if oldUsername != username {
+		if _, err := tx.Exec("UPDATE users SET username = $1, updated_at = NOW() WHERE id = $2", username, userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant username"})
+			return
+		}
+		if _, err := tx.Exec("INSERT INTO username_history (user_id, username) VALUES ($1, $2)", userID, oldUsername); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant username"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant username"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventUsernameChanged, models.JSONB{
+		"old_username": oldUsername,
+		"new_username": username,
+		"reserved":     true,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Username granted", "username": username})
+}
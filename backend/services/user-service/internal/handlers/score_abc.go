@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"user-service/internal/abc"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// abcContentType is the media type ABC tunes are served as
+const abcContentType = "text/vnd.abc; charset=utf-8"
+
+// ImportABCScore creates a private score from the first tune of an ABC
+// file. The tune's notes become the score's transcription, and the ABC is
+// kept as its notation.
+func ImportABCScore(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ABCImport
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tune, err := abc.Parse(req.ABC)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ABC: " + err.Error()})
+		return
+	}
+	title := strings.TrimSpace(tune.Title)
+	if title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ABC: the tune needs a title (T:)"})
+		return
+	}
+	if len(tune.Notes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ABC: the tune has no notes"})
+		return
+	}
+	if len([]rune(title)) > 255 {
+		title = string([]rune(title)[:255])
+	}
+	artist := req.Artist
+	if artist == nil && tune.Composer != "" {
+		composer := tune.Composer
+		if len([]rune(composer)) > 255 {
+			composer = string([]rune(composer)[:255])
+		}
+		artist = &composer
+	}
+
+	transcription, err := json.Marshal(map[string]interface{}{
+		"notes":          tune.Notes,
+		"tempo":          tune.Tempo,
+		"key":            tune.Key,
+		"time_signature": tune.Meter,
+		"source":         "abc",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import score"})
+		return
+	}
+
+	db := database.GetDB()
+	var scoreID string
+	err = db.QueryRow(`
+		INSERT INTO scores (user_id, title, artist, key_signature, time_signature, tempo,
+						   abc_notation, transcription_data, is_public)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, false)
+		RETURNING id`,
+		userID, title, artist, tune.Key, tune.Meter, tune.Tempo, req.ABC, transcription,
+	).Scan(&scoreID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import score"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	score, err := scanLibraryScore(db.QueryRow(`
+		SELECT `+libraryScoreColumns+`
+		FROM scores s
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id
+		WHERE s.id = $1`,
+		scoreID,
+	))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"score": score, "notes": len(tune.Notes)})
+}
+
+// GetScoreABC returns a score as ABC: its stored notation, or else its
+// transcribed notes written as a single-voice tune. Public scores can be read
+// by anyone signed in; private ones only by their owner.
+func GetScoreABC(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	var notation string
+	var notes []byte
+	tune := abc.Tune{}
+	err := database.GetReadDBFor(userID).QueryRow(`
+		SELECT s.title, COALESCE(s.artist, ''), COALESCE(s.key_signature, ''), COALESCE(s.time_signature, ''),
+			   COALESCE(s.tempo, 0), COALESCE(s.abc_notation, ''), COALESCE(s.transcription_data->'notes', '[]')
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL
+		  AND (s.user_id::text = $2 OR (s.is_public = true AND s.is_draft IS NOT TRUE
+			   AND u.is_active = true AND u.shadow_banned_at IS NULL))`,
+		scoreID, userID,
+	).Scan(&tune.Title, &tune.Composer, &tune.Key, &tune.Meter, &tune.Tempo, &notation, &notes)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score"})
+		return
+	}
+	if tune.Notes, err = abc.ParseNotes(notes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read score notes"})
+		return
+	}
+
+	text := abc.ScoreNotation(notation, &tune)
+	if text == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score has no notes to export"})
+		return
+	}
+	c.Data(http.StatusOK, abcContentType, []byte(text))
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const scoreDifficultyColumns = "difficulty_level, difficulty_estimated, difficulty_override, difficulty_factors, difficulty_estimated_at"
+
+func scanScoreDifficulty(row rowScanner) (models.ScoreDifficulty, error) {
+	var d models.ScoreDifficulty
+	var factors []byte
+	err := row.Scan(&d.DifficultyLevel, &d.DifficultyEstimated, &d.DifficultyOverride, &factors, &d.EstimatedAt)
+	d.Factors = factors
+	return d, err
+}
+
+// GetScoreDifficulty returns a score's difficulty with the estimate, the
+// creator's override and the factors estimated from. Public scores can be
+// read by anyone signed in; private ones only by their owner.
+func GetScoreDifficulty(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	d, err := scanScoreDifficulty(database.GetReadDBFor(userID).QueryRow(`
+		SELECT `+scoreDifficultyColumns+`
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL
+		  AND (s.user_id::text = $2 OR (s.is_public = true AND s.is_draft IS NOT TRUE
+			   AND u.is_active = true AND u.shadow_banned_at IS NULL))`,
+		scoreID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get difficulty"})
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
+
+// UpdateScoreDifficulty sets or clears the difficulty the creator gives their
+// score. While set it replaces the estimate in searches and recommendations.
+func UpdateScoreDifficulty(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	var req models.DifficultyOverrideUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	d, err := scanScoreDifficulty(database.GetDB().QueryRow(`
+		UPDATE scores SET difficulty_override = $1
+		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
+		RETURNING `+scoreDifficultyColumns,
+		req.DifficultyOverride, scoreID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update difficulty"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, d)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DownloadScoreAudio returns the audio behind a score: ?variant=original for
+// the untouched upload, which only its owner can fetch on plans that include
+// it, or ?variant=processed (default), which anyone signed in can fetch for a
+// shared score. Downloads of shared scores are audited and counted.
+func DownloadScoreAudio(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+	variant := c.DefaultQuery("variant", models.ScoreDownloadProcessed)
+	if variant != models.ScoreDownloadOriginal && variant != models.ScoreDownloadProcessed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "variant must be original or processed"})
+		return
+	}
+
+	db := database.GetDB()
+	var ownerID, tier string
+	var originalURL, processedURL sql.NullString
+	var shared bool
+	err := db.QueryRow(`
+		SELECT s.user_id, s.original_audio_url, s.processed_audio_url,
+			   COALESCE(s.is_public, false) AND s.is_draft IS NOT TRUE AND u.is_active AND u.shadow_banned_at IS NULL,
+			   COALESCE((SELECT subscription_tier FROM users WHERE id::text = $2), '')
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL`,
+		scoreID, userID,
+	).Scan(&ownerID, &originalURL, &processedURL, &shared, &tier)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	owner := ownerID == userID
+	if !owner && !shared {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+
+	url := processedURL
+	if variant == models.ScoreDownloadOriginal {
+		if !owner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner can download the original upload"})
+			return
+		}
+		if caps := models.GetTierCapabilities(tier); !caps.OriginalDownloads {
+			c.JSON(http.StatusForbidden, gin.H{"error": &models.QuotaError{
+				Code:    models.QuotaOriginal,
+				Message: fmt.Sprintf("Original downloads are not available on the %s plan", caps.Tier),
+				Tier:    caps.Tier,
+			}})
+			return
+		}
+		url = originalURL
+	}
+	if !url.Valid || url.String == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No " + variant + " audio for this score"})
+		return
+	}
+
+	if shared {
+		if !owner {
+			if _, err := db.Exec("UPDATE scores SET download_count = COALESCE(download_count, 0) + 1 WHERE id = $1", scoreID); err != nil {
+				log.Printf("Failed to count download of score %s: %v", scoreID, err)
+			}
+		}
+		audit.LogRequest(c, userID, audit.EventScoreDownloaded, models.JSONB{
+			"score_id": scoreID,
+			"owner_id": ownerID,
+			"variant":  variant,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"score_id": scoreID, "variant": variant, "url": url.String})
+}
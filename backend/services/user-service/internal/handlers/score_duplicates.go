@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"sort"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ListDuplicateScores groups the current user's scores that are likely the
+// same song: transcribed from the same source audio, or with the same title
+// and artist once case, punctuation and asides such as "(Live)" are ignored
+func ListDuplicateScores(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	rows, err := database.GetReadDBFor(userID).Query(`
+		SELECT `+libraryScoreColumns+`,
+			   COALESCE(s.original_audio_url, ''),
+			   ARRAY(SELECT DISTINCT j.input_url FROM transcription_jobs j
+					 WHERE j.score_id = s.id AND j.input_url IS NOT NULL)
+		FROM scores s
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id
+		WHERE s.user_id = $1 AND s.deleted_at IS NULL
+		ORDER BY s.created_at, s.id`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find duplicates"})
+		return
+	}
+	defer rows.Close()
+
+	var scores []models.LibraryScore
+	parent := []int{}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	// Scores sharing a key are joined; each join records why
+	owners := map[string]int{}
+	reasons := map[[2]int]string{}
+	link := func(key, reason string, i int) {
+		if key == "" {
+			return
+		}
+		if j, ok := owners[reason+"\x00"+key]; ok {
+			a, b := find(j), find(i)
+			if a != b {
+				parent[b] = a
+			}
+			reasons[[2]int{j, i}] = reason
+			return
+		}
+		owners[reason+"\x00"+key] = i
+	}
+
+	for rows.Next() {
+		var audioURL string
+		var jobURLs []string
+		s, err := scanLibraryScore(rows, &audioURL, pq.Array(&jobURLs))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find duplicates"})
+			return
+		}
+		i := len(scores)
+		scores = append(scores, s)
+		parent = append(parent, i)
+
+		artist := ""
+		if s.Artist != nil {
+			artist = *s.Artist
+		}
+		link(models.ScoreTitleKey(s.Title, artist), models.DuplicateSimilarTitle, i)
+		for _, source := range append(jobURLs, audioURL) {
+			link(models.ScoreSourceKey(source), models.DuplicateSameSource, i)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find duplicates"})
+		return
+	}
+
+	byRoot := map[int]*models.DuplicateGroup{}
+	roots := []int{}
+	for i, s := range scores {
+		root := find(i)
+		group, ok := byRoot[root]
+		if !ok {
+			group = &models.DuplicateGroup{Reasons: []string{}}
+			byRoot[root] = group
+			roots = append(roots, root)
+		}
+		group.Scores = append(group.Scores, s)
+	}
+	for pair, reason := range reasons {
+		group := byRoot[find(pair[0])]
+		if !containsString(group.Reasons, reason) {
+			group.Reasons = append(group.Reasons, reason)
+		}
+	}
+
+	groups := []models.DuplicateGroup{}
+	for _, root := range roots {
+		group := byRoot[root]
+		if len(group.Scores) < 2 {
+			continue
+		}
+		sort.Strings(group.Reasons)
+		// Scores are oldest first, so the first maximum is the oldest on ties
+		canonical := group.Scores[0]
+		for _, s := range group.Scores[1:] {
+			if s.PracticeMinutes > canonical.PracticeMinutes {
+				canonical = s
+			}
+		}
+		group.SuggestedCanonicalID = canonical.ID
+		groups = append(groups, *group)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+// MergeScores folds duplicate scores into the canonical score in the URL and
+// deletes them. Everyone's practice history on the duplicates is combined
+// into the canonical score, tags, counters and daily stats are added up,
+// translations fill in locales the canonical score lacks, and transcription
+// jobs, challenges, song requests and derived scores are repointed. All
+// scores must belong to the current user, who must not be under legal hold.
+func MergeScores(c *gin.Context) {
+	userID := c.GetString("user_id")
+	canonicalID := c.Param("id")
+	if _, err := uuid.Parse(canonicalID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	var req models.ScoreMerge
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	duplicates := []string{}
+	for _, id := range req.DuplicateIDs {
+		if id.String() == canonicalID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge a score into itself"})
+			return
+		}
+		if !containsString(duplicates, id.String()) {
+			duplicates = append(duplicates, id.String())
+		}
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	// Holding the user row keeps a legal hold from being placed mid-merge
+	var held bool
+	if err := tx.QueryRow("SELECT legal_hold_at IS NOT NULL FROM users WHERE id = $1 FOR SHARE", userID).Scan(&held); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if held {
+		c.JSON(http.StatusConflict, gin.H{"error": "User is under legal hold"})
+		return
+	}
+
+	var locked int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM (
+			SELECT id FROM scores WHERE id = ANY($1::uuid[]) AND user_id = $2 AND deleted_at IS NULL ORDER BY id FOR UPDATE
+		) s`,
+		pq.Array(append([]string{canonicalID}, duplicates...)), userID,
+	).Scan(&locked)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if locked != len(duplicates)+1 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+
+	dups := pq.Array(duplicates)
+	steps := []struct {
+		query string
+		args  []interface{}
+	}{
+		// Practice history: one row per user, adding up time and keeping the
+		// furthest progress. LEAST and GREATEST ignore NULLs.
+		{`
+			INSERT INTO learning_progress (user_id, score_id, started_at, last_practiced_at, completed_at,
+				total_practice_time_minutes, completion_percentage, best_streak_days, notes)
+			SELECT user_id, $1, MIN(started_at), MAX(last_practiced_at), MIN(completed_at),
+				   SUM(COALESCE(total_practice_time_minutes, 0)), MAX(completion_percentage),
+				   MAX(best_streak_days), string_agg(notes, E'\n\n')
+			FROM learning_progress WHERE score_id = ANY($2::uuid[])
+			GROUP BY user_id
+			ON CONFLICT (user_id, score_id) DO UPDATE SET
+				started_at = LEAST(learning_progress.started_at, EXCLUDED.started_at),
+				last_practiced_at = GREATEST(learning_progress.last_practiced_at, EXCLUDED.last_practiced_at),
+				completed_at = LEAST(learning_progress.completed_at, EXCLUDED.completed_at),
+				total_practice_time_minutes = COALESCE(learning_progress.total_practice_time_minutes, 0)
+					+ EXCLUDED.total_practice_time_minutes,
+				completion_percentage = GREATEST(learning_progress.completion_percentage, EXCLUDED.completion_percentage),
+				best_streak_days = GREATEST(learning_progress.best_streak_days, EXCLUDED.best_streak_days),
+				notes = COALESCE(learning_progress.notes || E'\n\n' || EXCLUDED.notes,
+					learning_progress.notes, EXCLUDED.notes)`,
+			[]interface{}{canonicalID, dups}},
+		{`
+			UPDATE scores c SET
+				tags = ARRAY(SELECT DISTINCT t FROM scores d, unnest(d.tags) t
+							 WHERE d.id = c.id OR d.id = ANY($2::uuid[])),
+				view_count = COALESCE(c.view_count, 0) + m.views,
+				like_count = COALESCE(c.like_count, 0) + m.likes,
+				download_count = COALESCE(c.download_count, 0) + m.downloads
+			FROM (SELECT COALESCE(SUM(view_count), 0) AS views, COALESCE(SUM(like_count), 0) AS likes,
+						 COALESCE(SUM(download_count), 0) AS downloads
+				  FROM scores WHERE id = ANY($2::uuid[])) m
+			WHERE c.id = $1`,
+			[]interface{}{canonicalID, dups}},
+		// Translations the canonical score lacks come from the most recently
+		// updated duplicate
+		{`
+			INSERT INTO score_translations (score_id, locale, title, description)
+			SELECT DISTINCT ON (locale) $1, locale, title, description
+			FROM score_translations WHERE score_id = ANY($2::uuid[])
+			ORDER BY locale, updated_at DESC
+			ON CONFLICT (score_id, locale) DO NOTHING`,
+			[]interface{}{canonicalID, dups}},
+		// Daily snapshots are running totals, so they add up like the counters
+		{`
+			INSERT INTO score_daily_stats (score_id, day, views, favorites, downloads, learners)
+			SELECT $1, day, SUM(views), SUM(favorites), SUM(downloads), SUM(learners)
+			FROM score_daily_stats WHERE score_id = ANY($2::uuid[])
+			GROUP BY day
+			ON CONFLICT (score_id, day) DO UPDATE SET
+				views = score_daily_stats.views + EXCLUDED.views,
+				favorites = score_daily_stats.favorites + EXCLUDED.favorites,
+				downloads = score_daily_stats.downloads + EXCLUDED.downloads,
+				learners = score_daily_stats.learners + EXCLUDED.learners`,
+			[]interface{}{canonicalID, dups}},
+		{"UPDATE transcription_jobs SET score_id = $1 WHERE score_id = ANY($2::uuid[])", []interface{}{canonicalID, dups}},
+		{"UPDATE challenges SET score_id = $1 WHERE score_id = ANY($2::uuid[])", []interface{}{canonicalID, dups}},
+		{"UPDATE song_requests SET score_id = $1 WHERE score_id = ANY($2::uuid[])", []interface{}{canonicalID, dups}},
+		{"UPDATE scores SET parent_score_id = NULL WHERE id = $1 AND parent_score_id = ANY($2::uuid[])", []interface{}{canonicalID, dups}},
+		{"UPDATE scores SET parent_score_id = $1 WHERE parent_score_id = ANY($2::uuid[])", []interface{}{canonicalID, dups}},
+		{"DELETE FROM scores WHERE id = ANY($1::uuid[])", []interface{}{dups}},
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, step.args...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge scores"})
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge scores"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventScoresMerged, models.JSONB{"canonical_id": canonicalID, "merged_ids": duplicates})
+
+	score, err := scanLibraryScore(db.QueryRow(`
+		SELECT `+libraryScoreColumns+`
+		FROM scores s
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id
+		WHERE s.id = $1`,
+		canonicalID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"score": score, "merged": len(duplicates)})
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// maxActiveExports caps how many exports a user can have waiting or building
+const maxActiveExports = 3
+
+const scoreExportColumns = "id, status, score_ids, formats, size_bytes, skipped, error_message, created_at, completed_at, expires_at"
+
+func scanScoreExport(row rowScanner) (models.ScoreExport, error) {
+	var e models.ScoreExport
+	var skipped []byte
+	err := row.Scan(&e.ID, &e.Status, pq.Array(&e.ScoreIDs), pq.Array(&e.Formats), &e.SizeBytes,
+		&skipped, &e.ErrorMessage, &e.CreatedAt, &e.CompletedAt, &e.ExpiresAt)
+	if err != nil {
+		return e, err
+	}
+	if err := json.Unmarshal(skipped, &e.Skipped); err != nil {
+		return e, err
+	}
+	if e.Skipped == nil {
+		e.Skipped = []models.ExportSkip{}
+	}
+
+	// Completed exports carry a link signed until the archive expires
+	if e.Status == models.ExportCompleted && e.ExpiresAt != nil {
+		expires := e.ExpiresAt.Unix()
+		e.DownloadPath = "/api/v1/exports/" + e.ID.String() + "/download?expires=" + strconv.FormatInt(expires, 10) +
+			"&sig=" + utils.SignExportDownload(e.ID.String(), expires)
+		if base := os.Getenv("PUBLIC_BASE_URL"); base != "" {
+			e.DownloadURL = strings.TrimRight(base, "/") + e.DownloadPath
+		}
+	}
+	return e, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateScoreExport queues a ZIP export of the current user's selected scores
+// in the chosen formats. The archive is built in the background; poll
+// GetScoreExport for the download link.
+func CreateScoreExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.ScoreExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scoreIDs := []string{}
+	for _, id := range req.ScoreIDs {
+		if !containsString(scoreIDs, id.String()) {
+			scoreIDs = append(scoreIDs, id.String())
+		}
+	}
+	formats := []string{}
+	for _, format := range req.Formats {
+		if !containsString(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+
+	db := database.GetDB()
+	var owned, active int
+	err := db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM scores WHERE id = ANY($1::uuid[]) AND user_id = $2 AND deleted_at IS NULL),
+			   (SELECT COUNT(*) FROM score_exports WHERE user_id = $2 AND status IN ($3, $4))`,
+		pq.Array(scoreIDs), userID, models.ExportPending, models.ExportProcessing,
+	).Scan(&owned, &active)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create export"})
+		return
+	}
+	if owned != len(scoreIDs) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if active >= maxActiveExports {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many exports in progress", "limit": maxActiveExports})
+		return
+	}
+
+	e, err := scanScoreExport(db.QueryRow(`
+		INSERT INTO score_exports (user_id, score_ids, formats)
+		VALUES ($1, $2::uuid[], $3)
+		RETURNING `+scoreExportColumns,
+		userID, pq.Array(scoreIDs), pq.Array(formats),
+	))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create export"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusAccepted, e)
+}
+
+// GetScoreExport returns the status of one of the current user's exports and,
+// once built, its signed download link
+func GetScoreExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exportID := c.Param("id")
+	if _, err := uuid.Parse(exportID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return
+	}
+
+	e, err := scanScoreExport(database.GetReadDBFor(userID).QueryRow(
+		"SELECT "+scoreExportColumns+" FROM score_exports WHERE id = $1 AND user_id = $2", exportID, userID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get export"})
+		return
+	}
+
+	c.JSON(http.StatusOK, e)
+}
+
+// DownloadScoreExport serves an export archive to anyone holding its signed
+// link, until the link expires
+func DownloadScoreExport(c *gin.Context) {
+	exportID := c.Param("id")
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if _, idErr := uuid.Parse(exportID); idErr != nil || err != nil ||
+		!utils.VerifyExportDownload(exportID, expires, c.Query("sig")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid download link"})
+		return
+	}
+	if time.Now().Unix() >= expires {
+		c.JSON(http.StatusGone, gin.H{"error": "Download link has expired"})
+		return
+	}
+
+	var archive []byte
+	err = database.GetDB().QueryRow(`
+		SELECT archive FROM score_exports
+		WHERE id = $1 AND status = $2 AND expires_at > NOW() AND archive IS NOT NULL`,
+		exportID, models.ExportCompleted,
+	).Scan(&archive)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusGone, gin.H{"error": "Export is no longer available"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get export"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="scores-`+exportID[:8]+`.zip"`)
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
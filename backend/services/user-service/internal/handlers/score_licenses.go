@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const scoreLicenseColumns = `
+	s.license_type, s.license_composer, s.license_publisher, s.license_reference,
+	s.license_notes, s.license_updated_at`
+
+// licenseUnlicensed selects public scores without license information in the
+// licensing report
+const licenseUnlicensed = "unlicensed"
+
+// scanScoreLicense reads scoreLicenseColumns; scores without a license type
+// have no license
+func scanScoreLicense(row rowScanner, extra ...interface{}) (*models.ScoreLicense, error) {
+	var l models.ScoreLicense
+	var licenseType sql.NullString
+	dest := []interface{}{&licenseType, &l.Composer, &l.Publisher, &l.Reference, &l.Notes, &l.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if !licenseType.Valid {
+		return nil, nil
+	}
+	l.Type = licenseType.String
+	return &l, nil
+}
+
+// blank reports whether an optional text field is missing or empty
+func blank(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
+
+// GetScoreLicense returns a score's license information and visibility.
+// Public scores can be read by anyone signed in; private ones only by their
+// owner.
+func GetScoreLicense(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	var isPublic bool
+	license, err := scanScoreLicense(database.GetReadDBFor(userID).QueryRow(`
+		SELECT `+scoreLicenseColumns+`, COALESCE(s.is_public, false)
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.deleted_at IS NULL
+		  AND (s.user_id::text = $2 OR (s.is_public = true AND s.is_draft IS NOT TRUE
+			   AND u.is_active = true AND u.shadow_banned_at IS NULL))`,
+		scoreID, userID,
+	), &isPublic)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get license"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"license": license, "is_public": isPublic})
+}
+
+// UpdateScoreLicense replaces the license information of the current user's
+// score. A license can be changed but not removed.
+func UpdateScoreLicense(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	var req models.ScoreLicense
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch {
+	case req.Type == models.LicenseCover && blank(req.Composer):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A cover must name the original composer"})
+		return
+	case req.Type == models.LicenseLicensed && (blank(req.Publisher) || blank(req.Reference)):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A licensed score must name the publisher and the license reference"})
+		return
+	}
+
+	var isPublic bool
+	license, err := scanScoreLicense(database.GetDB().QueryRow(`
+		UPDATE scores s SET license_type = $1, license_composer = $2, license_publisher = $3,
+			license_reference = $4, license_notes = $5, license_updated_at = NOW()
+		WHERE s.id = $6 AND s.user_id = $7 AND s.deleted_at IS NULL
+		RETURNING `+scoreLicenseColumns+`, COALESCE(s.is_public, false)`,
+		req.Type, req.Composer, req.Publisher, req.Reference, req.Notes, scoreID, userID,
+	), &isPublic)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update license"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventScoreLicenseSet, models.JSONB{"score_id": scoreID, "type": req.Type})
+
+	c.JSON(http.StatusOK, gin.H{"license": license, "is_public": isPublic})
+}
+
+// UpdateScoreVisibility publishes or unpublishes the current user's score.
+// Publishing requires license information.
+func UpdateScoreVisibility(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	var req models.ScoreVisibility
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE scores SET is_public = $1
+		WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL AND (NOT $1 OR license_type IS NOT NULL)`,
+		*req.IsPublic, scoreID, userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visibility"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		var exists bool
+		if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM scores WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL)", scoreID, userID).Scan(&exists); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update visibility"})
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "Add license information before making the score public"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventScoreVisibility, models.JSONB{"score_id": scoreID, "is_public": *req.IsPublic})
+
+	c.JSON(http.StatusOK, gin.H{"is_public": *req.IsPublic})
+}
+
+// GetLicensingReport summarizes the license information of public scores and
+// lists those of one license type, by default the unlicensed ones made public
+// before licenses were required (admin)
+func GetLicensingReport(c *gin.Context) {
+	license := c.DefaultQuery("license", licenseUnlicensed)
+	switch license {
+	case licenseUnlicensed, models.LicenseOriginal, models.LicenseCover, models.LicensePublicDomain, models.LicenseLicensed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid license type"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	db := database.GetReadDB()
+	summary := map[string]int{licenseUnlicensed: 0, models.LicenseOriginal: 0, models.LicenseCover: 0,
+		models.LicensePublicDomain: 0, models.LicenseLicensed: 0}
+	rows, err := db.Query(`
+		SELECT COALESCE(license_type, $1), COUNT(*)
+		FROM scores WHERE is_public = true AND deleted_at IS NULL
+		GROUP BY 1`,
+		licenseUnlicensed,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get licensing report"})
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var licenseType string
+		var count int
+		if err := rows.Scan(&licenseType, &count); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get licensing report"})
+			return
+		}
+		summary[licenseType] = count
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get licensing report"})
+		return
+	}
+
+	scoreRows, err := db.Query(`
+		SELECT `+scoreLicenseColumns+`, s.id, s.title, s.artist, u.username, s.created_at
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.is_public = true AND s.deleted_at IS NULL AND COALESCE(s.license_type, $1) = $2
+		ORDER BY s.created_at DESC, s.id
+		LIMIT $3 OFFSET $4`,
+		licenseUnlicensed, license, limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get licensing report"})
+		return
+	}
+	defer scoreRows.Close()
+
+	scores := []models.LicensingReportScore{}
+	for scoreRows.Next() {
+		var s models.LicensingReportScore
+		license, err := scanScoreLicense(scoreRows, &s.ID, &s.Title, &s.Artist, &s.Owner, &s.CreatedAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get licensing report"})
+			return
+		}
+		s.License = license
+		scores = append(scores, s)
+	}
+	if err := scoreRows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get licensing report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary": summary,
+		"license": license,
+		"scores":  scores,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
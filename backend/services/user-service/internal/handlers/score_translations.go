@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const scoreTranslationColumns = "score_id, locale, title, description, created_at, updated_at"
+
+func scanScoreTranslation(row rowScanner) (models.ScoreTranslation, error) {
+	var t models.ScoreTranslation
+	err := row.Scan(&t.ScoreID, &t.Locale, &t.Title, &t.Description, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// requestLocales returns the locales to show content in, most preferred
+// first: ?locale= followed by the Accept-Language header
+func requestLocales(c *gin.Context) []string {
+	return models.RequestLocales(c.Query("locale") + "," + c.GetHeader("Accept-Language"))
+}
+
+// translateScores replaces the titles and descriptions of public scores with
+// the best translation for the request's locales. Scores without one keep
+// the original.
+func translateScores(c *gin.Context, db *sql.DB, scores []models.PublicScore) error {
+	c.Header("Vary", "Accept-Language")
+	locales := requestLocales(c)
+	if len(locales) == 0 || len(scores) == 0 {
+		return nil
+	}
+
+	index := make(map[uuid.UUID][]int, len(scores))
+	ids := make([]string, 0, len(scores))
+	for i, s := range scores {
+		if _, ok := index[s.ID]; !ok {
+			ids = append(ids, s.ID.String())
+		}
+		index[s.ID] = append(index[s.ID], i)
+	}
+
+	rows, err := db.Query(`
+		SELECT DISTINCT ON (score_id) score_id, locale, title, description
+		FROM score_translations
+		WHERE score_id = ANY($1::uuid[]) AND locale = ANY($2::text[])
+		ORDER BY score_id, array_position($2::text[], locale::text)`,
+		pq.Array(ids), pq.Array(locales),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var locale, title string
+		var description *string
+		if err := rows.Scan(&id, &locale, &title, &description); err != nil {
+			return err
+		}
+		for _, i := range index[id] {
+			scores[i].Title = title
+			scores[i].Locale = locale
+			if description != nil {
+				scores[i].Description = description
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// firstPartyAdmin reports whether the caller is an admin on an unrestricted
+// session
+func firstPartyAdmin(c *gin.Context) bool {
+	_, restricted := c.Get("scopes")
+	role := c.GetString("role")
+	return !restricted && (role == models.RoleAdmin || role == models.RoleSuperAdmin)
+}
+
+// translatableScore checks that the score in the URL exists and that the
+// caller owns it or is an admin, writing the error response otherwise
+func translatableScore(c *gin.Context) (string, bool) {
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return "", false
+	}
+
+	var ownerID string
+	err := database.GetDB().QueryRow("SELECT user_id FROM scores WHERE id = $1 AND deleted_at IS NULL", scoreID).Scan(&ownerID)
+	if err == sql.ErrNoRows || (err == nil && ownerID != c.GetString("user_id") && !firstPartyAdmin(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return "", false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get score"})
+		return "", false
+	}
+	return scoreID, true
+}
+
+// ListScoreTranslations lists a score's translations (owner or admin)
+func ListScoreTranslations(c *gin.Context) {
+	scoreID, ok := translatableScore(c)
+	if !ok {
+		return
+	}
+
+	rows, err := database.GetDB().Query(
+		"SELECT "+scoreTranslationColumns+" FROM score_translations WHERE score_id = $1 ORDER BY locale", scoreID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get translations"})
+		return
+	}
+	defer rows.Close()
+
+	translations := []models.ScoreTranslation{}
+	for rows.Next() {
+		t, err := scanScoreTranslation(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get translations"})
+			return
+		}
+		translations = append(translations, t)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get translations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"translations": translations})
+}
+
+// PutScoreTranslation creates or replaces a score's translation for the
+// locale in the URL (owner or admin)
+func PutScoreTranslation(c *gin.Context) {
+	locale, ok := models.NormalizeLocale(c.Param("locale"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale"})
+		return
+	}
+	var req models.ScoreTranslationInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scoreID, ok := translatableScore(c)
+	if !ok {
+		return
+	}
+
+	t, err := scanScoreTranslation(database.GetDB().QueryRow(`
+		INSERT INTO score_translations (score_id, locale, title, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (score_id, locale) DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description
+		RETURNING `+scoreTranslationColumns,
+		scoreID, locale, req.Title, req.Description,
+	))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+		return
+	}
+	database.MarkWrite(c.GetString("user_id"))
+
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteScoreTranslation removes a score's translation (owner or admin)
+func DeleteScoreTranslation(c *gin.Context) {
+	locale, ok := models.NormalizeLocale(c.Param("locale"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid locale"})
+		return
+	}
+	scoreID, ok := translatableScore(c)
+	if !ok {
+		return
+	}
+
+	result, err := database.GetDB().Exec(
+		"DELETE FROM score_translations WHERE score_id = $1 AND locale = $2", scoreID, locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+		return
+	}
+	database.MarkWrite(c.GetString("user_id"))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted"})
+}
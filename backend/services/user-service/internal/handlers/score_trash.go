@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// trashPurgeAt is when a score deleted at deletedAt leaves the trash for good
+func trashPurgeAt(deletedAt time.Time, tier string) time.Time {
+	return deletedAt.AddDate(0, 0, models.GetTierCapabilities(tier).TrashRetentionDays)
+}
+
+// DeleteScore moves one of the current user's scores to the trash, where it
+// can be restored until its tier's retention period has passed
+func DeleteScore(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	var deletedAt time.Time
+	var tier string
+	err := database.GetDB().QueryRow(`
+		UPDATE scores s SET deleted_at = NOW()
+		FROM users u
+		WHERE s.id = $1 AND s.user_id = $2 AND u.id = s.user_id AND s.deleted_at IS NULL
+		RETURNING s.deleted_at, u.subscription_tier`,
+		scoreID, userID,
+	).Scan(&deletedAt, &tier)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete score"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventScoreDeleted, models.JSONB{"score_id": scoreID})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Score moved to trash", "purge_at": trashPurgeAt(deletedAt, tier)})
+}
+
+// ListScoreTrash lists the current user's deleted scores, most recently
+// deleted first, with when each will be purged
+func ListScoreTrash(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	rows, err := database.GetReadDBFor(userID).Query(`
+		SELECT `+libraryScoreColumns+`, s.deleted_at, u.subscription_tier
+		FROM scores s
+		JOIN users u ON u.id = s.user_id
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id
+		WHERE s.user_id = $1 AND s.deleted_at IS NOT NULL
+		ORDER BY s.deleted_at DESC, s.id
+		LIMIT $2 OFFSET $3`,
+		userID, limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trash"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.TrashedScore{}
+	for rows.Next() {
+		var t models.TrashedScore
+		var tier string
+		t.LibraryScore, err = scanLibraryScore(rows, &t.DeletedAt, &tier)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trash"})
+			return
+		}
+		t.PurgeAt = trashPurgeAt(t.DeletedAt, tier)
+		scores = append(scores, t)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scores": scores})
+}
+
+// RestoreScore takes one of the current user's scores out of the trash. Scores
+// past their retention period return 410 until the purge job removes them.
+func RestoreScore(c *gin.Context) {
+	userID := c.GetString("user_id")
+	scoreID := c.Param("id")
+	if _, err := uuid.Parse(scoreID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid score ID"})
+		return
+	}
+
+	db := database.GetDB()
+	var deletedAt sql.NullTime
+	var tier string
+	err := db.QueryRow(`
+		SELECT s.deleted_at, u.subscription_tier
+		FROM scores s JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1 AND s.user_id = $2`,
+		scoreID, userID,
+	).Scan(&deletedAt, &tier)
+	if err == sql.ErrNoRows || (err == nil && !deletedAt.Valid) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore score"})
+		return
+	}
+	if !time.Now().Before(trashPurgeAt(deletedAt.Time, tier)) {
+		c.JSON(http.StatusGone, gin.H{"error": "The restore window for this score has passed"})
+		return
+	}
+
+	// The purge job locks the rows it deletes, so a score it already took is
+	// no longer found here
+	score, err := scanLibraryScore(db.QueryRow(`
+		WITH restored AS (
+			UPDATE scores SET deleted_at = NULL
+			WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+			RETURNING *
+		)
+		SELECT `+libraryScoreColumns+`
+		FROM restored s
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id`,
+		scoreID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Score not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore score"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventScoreRestored, models.JSONB{"score_id": scoreID})
+
+	c.JSON(http.StatusOK, score)
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const libraryScoreColumns = `
+	s.id, s.title, s.artist, s.album, (SELECT at.album_id FROM album_tracks at WHERE at.score_id = s.id),
+	s.genre, s.difficulty_level, s.key_signature, s.tempo,
+	s.tuning, s.tags, COALESCE(s.is_public, false), COALESCE(s.is_draft, false),
+	COALESCE(lp.total_practice_time_minutes, 0), lp.last_practiced_at, s.created_at, s.updated_at`
+
+// librarySort describes one library order: the expression rows are ordered
+// by (ties broken by ID), its SQL type, and the direction
+type librarySort struct {
+	expr string
+	cast string
+	desc bool
+}
+
+var librarySorts = map[string]librarySort{
+	models.LibrarySortRecent:        {expr: "s.created_at", cast: "timestamptz", desc: true},
+	models.LibrarySortMostPracticed: {expr: "COALESCE(lp.total_practice_time_minutes, 0)", cast: "integer", desc: true},
+	models.LibrarySortAlphabetical:  {expr: "LOWER(s.title)", cast: "text"},
+}
+
+// libraryCursor is the position after the last score of a page. It carries
+// the sort so a cursor cannot be replayed against a different order.
+type libraryCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeLibraryCursor(cur libraryCursor) string {
+	payload, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeLibraryCursor(encoded, sort string) (libraryCursor, bool) {
+	var cur libraryCursor
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(payload, &cur) != nil || cur.Sort != sort {
+		return cur, false
+	}
+	if _, err := uuid.Parse(cur.ID); err != nil {
+		return cur, false
+	}
+	return cur, true
+}
+
+func scanLibraryScore(row rowScanner, extra ...interface{}) (models.LibraryScore, error) {
+	var s models.LibraryScore
+	dest := []interface{}{&s.ID, &s.Title, &s.Artist, &s.Album, &s.AlbumID, &s.Genre, &s.DifficultyLevel,
+		&s.KeySignature, &s.Tempo, &s.Tuning, pq.Array(&s.Tags), &s.IsPublic, &s.IsDraft,
+		&s.PracticeMinutes, &s.LastPracticedAt, &s.CreatedAt, &s.UpdatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if s.Tags == nil {
+		s.Tags = []string{}
+	}
+	return s, err
+}
+
+// libraryWhere turns library filters into SQL conditions on scores s, adding
+// their values to args
+func libraryWhere(f models.LibraryFilters, args *[]interface{}) string {
+	arg := func(v interface{}) string {
+		*args = append(*args, v)
+		return "$" + strconv.Itoa(len(*args))
+	}
+
+	var where strings.Builder
+	if len(f.Tags) > 0 {
+		where.WriteString(" AND s.tags @> " + arg(pq.Array(f.Tags)) + "::text[]")
+	}
+	if f.Artist != "" {
+		where.WriteString(" AND LOWER(s.artist) = LOWER(" + arg(f.Artist) + ")")
+	}
+	if f.AlbumID != "" {
+		where.WriteString(" AND EXISTS (SELECT 1 FROM album_tracks at WHERE at.score_id = s.id AND at.album_id = " + arg(f.AlbumID) + ")")
+	}
+	if f.Genre != "" {
+		where.WriteString(" AND LOWER(s.genre) = LOWER(" + arg(f.Genre) + ")")
+	}
+	if f.Tuning != "" {
+		where.WriteString(" AND LOWER(s.tuning) = LOWER(" + arg(f.Tuning) + ")")
+	}
+	if f.DifficultyMin != nil {
+		where.WriteString(" AND s.difficulty_level >= " + arg(*f.DifficultyMin))
+	}
+	if f.DifficultyMax != nil {
+		where.WriteString(" AND s.difficulty_level <= " + arg(*f.DifficultyMax))
+	}
+	if f.TempoMin != nil {
+		where.WriteString(" AND s.tempo >= " + arg(*f.TempoMin))
+	}
+	if f.TempoMax != nil {
+		where.WriteString(" AND s.tempo <= " + arg(*f.TempoMax))
+	}
+	switch f.Visibility {
+	case "public":
+		where.WriteString(" AND s.is_public = true AND s.is_draft IS NOT TRUE")
+	case "private":
+		where.WriteString(" AND s.is_public IS NOT TRUE AND s.is_draft IS NOT TRUE")
+	case "draft":
+		where.WriteString(" AND s.is_draft = true")
+	}
+	if f.CreatedAfter != nil {
+		where.WriteString(" AND s.created_at >= " + arg(*f.CreatedAfter))
+	}
+	if f.CreatedBefore != nil {
+		where.WriteString(" AND s.created_at < " + arg(*f.CreatedBefore))
+	}
+	if f.AddedWithin != nil {
+		where.WriteString(" AND s.created_at >= NOW() - make_interval(days => " + arg(*f.AddedWithin) + ")")
+	}
+	return where.String()
+}
+
+// ListLibraryScores lists the current user's scores, drafts included, with
+// filters and keyset pagination. ?cursor= continues from next_cursor of the
+// previous page; the order stays stable as scores are added. ?view= starts
+// from a saved view's filters, which query parameters override. The first
+// page also carries the user's pinned views.
+func ListLibraryScores(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	db := database.GetReadDBFor(userID)
+	var filters models.LibraryFilters
+	if viewID := c.Query("view"); viewID != "" {
+		if _, err := uuid.Parse(viewID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid view ID"})
+			return
+		}
+		view, err := scanLibraryView(db.QueryRow(
+			"SELECT "+libraryViewColumns+" FROM score_library_views WHERE id = $1 AND user_id = $2",
+			viewID, userID,
+		))
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+			return
+		}
+		filters = view.Filters
+	}
+	// Binding only sets parameters present in the query, so a view's other
+	// filters are kept
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filters.DifficultyMin != nil && filters.DifficultyMax != nil && *filters.DifficultyMin > *filters.DifficultyMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "difficulty_min must not exceed difficulty_max"})
+		return
+	}
+	if filters.Sort == "" {
+		filters.Sort = models.LibrarySortRecent
+	}
+	order := librarySorts[filters.Sort]
+
+	args := []interface{}{userID}
+	where := libraryWhere(filters, &args)
+
+	if encoded := c.Query("cursor"); encoded != "" {
+		cur, ok := decodeLibraryCursor(encoded, filters.Sort)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		args = append(args, cur.Value, cur.ID)
+		cmp := ">"
+		if order.desc {
+			cmp = "<"
+		}
+		where += " AND (" + order.expr + ", s.id) " + cmp + " ($" + strconv.Itoa(len(args)-1) + "::" + order.cast +
+			", $" + strconv.Itoa(len(args)) + "::uuid)"
+	}
+
+	direction := " ASC"
+	if order.desc {
+		direction = " DESC"
+	}
+	args = append(args, limit+1)
+
+	rows, err := db.Query(`
+		SELECT `+libraryScoreColumns+`, (`+order.expr+`)::text
+		FROM scores s
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id
+		WHERE s.user_id = $1 AND s.deleted_at IS NULL`+where+`
+		ORDER BY `+order.expr+direction+`, s.id`+direction+`
+		LIMIT $`+strconv.Itoa(len(args)),
+		args...,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+		return
+	}
+	defer rows.Close()
+
+	scores := []models.LibraryScore{}
+	var last libraryCursor
+	more := false
+	for rows.Next() {
+		var sortValue string
+		s, err := scanLibraryScore(rows, &sortValue)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+			return
+		}
+		if len(scores) == limit {
+			more = true // the extra row only signals another page
+			break
+		}
+		scores = append(scores, s)
+		last = libraryCursor{Sort: filters.Sort, Value: sortValue, ID: s.ID.String()}
+	}
+
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+		return
+	}
+
+	resp := gin.H{"scores": scores, "sort": filters.Sort}
+	if more {
+		resp["next_cursor"] = encodeLibraryCursor(last)
+	}
+	if c.Query("cursor") == "" {
+		pinned, err := listLibraryViews(db, userID, true)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scores"})
+			return
+		}
+		resp["pinned_views"] = pinned
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// refreshCookiePath limits the refresh cookie to the auth endpoints
+const refreshCookiePath = "/api/v1/auth"
+
+// useCookieSession reports whether the client asked for cookie session mode,
+// in which tokens are set as HttpOnly cookies instead of returned in the body
+func useCookieSession(c *gin.Context) bool {
+	return c.GetHeader("X-Session-Mode") == "cookie" || c.GetBool("cookie_session")
+}
+
+// respondWithTokens writes a token response, moving the tokens into cookies
+// when the client uses cookie session mode
+func respondWithTokens(c *gin.Context, status int, resp models.TokenResponse) {
+	if useCookieSession(c) {
+		setSessionCookies(c, resp.AccessToken, resp.RefreshToken, resp.ExpiresIn)
+		resp.AccessToken = ""
+		resp.RefreshToken = ""
+		resp.TokenType = "Cookie"
+	}
+	c.JSON(status, resp)
+}
+
+func setSessionCookies(c *gin.Context, accessToken, refreshToken string, expiresIn int) {
+	secure := os.Getenv("COOKIE_SECURE") != "false"
+	domain := os.Getenv("COOKIE_DOMAIN")
+
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(utils.AccessTokenCookie, accessToken, expiresIn, "/", domain, secure, true)
+	c.SetCookie(utils.RefreshTokenCookie, refreshToken, 7*24*60*60, refreshCookiePath, domain, secure, true)
+}
+
+func clearSessionCookies(c *gin.Context) {
+	if _, err := c.Cookie(utils.AccessTokenCookie); err != nil {
+		if _, err := c.Cookie(utils.RefreshTokenCookie); err != nil {
+			return
+		}
+	}
+
+	secure := os.Getenv("COOKIE_SECURE") != "false"
+	domain := os.Getenv("COOKIE_DOMAIN")
+
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(utils.AccessTokenCookie, "", -1, "/", domain, secure, true)
+	c.SetCookie(utils.RefreshTokenCookie, "", -1, refreshCookiePath, domain, secure, true)
+}
+
+// SessionRefresh silently rotates the session cookies using the refresh cookie
+func SessionRefresh(c *gin.Context) {
+	if c.GetHeader("X-Requested-With") == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "X-Requested-With header required for cookie sessions"})
+		return
+	}
+
+	refreshToken, err := c.Cookie(utils.RefreshTokenCookie)
+	if err != nil || refreshToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "No session"})
+		return
+	}
+
+	c.Set("cookie_session", true)
+	refreshSession(c, refreshToken)
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxSmartPlaylists caps how many smart playlists a user can keep
+const maxSmartPlaylists = 50
+
+// defaultPlaylistScores is how many scores a playlist lists unless set
+const defaultPlaylistScores = 100
+
+// smartPlaylistCacheTTL is how long an evaluated playlist is served from
+// Redis. Editing the playlist takes effect at once; library changes can take
+// this long to show.
+const smartPlaylistCacheTTL = 2 * time.Minute
+
+const smartPlaylistColumns = "id, name, description, rules, max_scores, created_at, updated_at"
+
+// cachedPlaylist is an evaluated smart playlist
+type cachedPlaylist struct {
+	Scores      []models.LibraryScore `json:"scores"`
+	EvaluatedAt time.Time             `json:"evaluated_at"`
+}
+
+func scanSmartPlaylist(row rowScanner) (models.SmartPlaylist, error) {
+	var p models.SmartPlaylist
+	var rules []byte
+	if err := row.Scan(&p.ID, &p.Name, &p.Description, &rules, &p.MaxScores, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return p, err
+	}
+	err := json.Unmarshal(rules, &p.Rules)
+	return p, err
+}
+
+// smartPlaylistCacheKey changes whenever the playlist is edited
+func smartPlaylistCacheKey(p models.SmartPlaylist) string {
+	return "smart_playlist:" + p.ID.String() + ":" + strconv.FormatInt(p.UpdatedAt.UnixNano(), 10)
+}
+
+// evaluateSmartPlaylist lists the user's scores matching a playlist's rules
+func evaluateSmartPlaylist(db *sql.DB, userID string, p models.SmartPlaylist) ([]models.LibraryScore, error) {
+	sort := p.Rules.Sort
+	if sort == "" {
+		sort = models.LibrarySortRecent
+	}
+	order := librarySorts[sort]
+	direction := " ASC"
+	if order.desc {
+		direction = " DESC"
+	}
+
+	args := []interface{}{userID}
+	where := libraryWhere(p.Rules, &args)
+	args = append(args, p.MaxScores)
+
+	rows, err := db.Query(`
+		SELECT `+libraryScoreColumns+`
+		FROM scores s
+		LEFT JOIN learning_progress lp ON lp.score_id = s.id AND lp.user_id = s.user_id
+		WHERE s.user_id = $1 AND s.deleted_at IS NULL`+where+`
+		ORDER BY `+order.expr+direction+`, s.id`+direction+`
+		LIMIT $`+strconv.Itoa(len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := []models.LibraryScore{}
+	for rows.Next() {
+		s, err := scanLibraryScore(rows)
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, s)
+	}
+	return scores, rows.Err()
+}
+
+// ListSmartPlaylists lists the current user's smart playlists by name
+func ListSmartPlaylists(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	rows, err := database.GetReadDBFor(userID).Query(
+		"SELECT "+smartPlaylistColumns+" FROM smart_playlists WHERE user_id = $1 ORDER BY LOWER(name)",
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get playlists"})
+		return
+	}
+	defer rows.Close()
+
+	playlists := []models.SmartPlaylist{}
+	for rows.Next() {
+		p, err := scanSmartPlaylist(rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get playlists"})
+			return
+		}
+		playlists = append(playlists, p)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"playlists": playlists})
+}
+
+// GetSmartPlaylist evaluates a smart playlist's rules against the library.
+// Results are cached briefly per playlist version.
+func GetSmartPlaylist(c *gin.Context) {
+	userID := c.GetString("user_id")
+	playlistID := c.Param("id")
+	if _, err := uuid.Parse(playlistID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	db := database.GetReadDBFor(userID)
+	p, err := scanSmartPlaylist(db.QueryRow(
+		"SELECT "+smartPlaylistColumns+" FROM smart_playlists WHERE id = $1 AND user_id = $2",
+		playlistID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get playlist"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	key := smartPlaylistCacheKey(p)
+	var cached cachedPlaylist
+	payload, err := database.GetRedis().Get(ctx, key).Bytes()
+	if err != nil || json.Unmarshal(payload, &cached) != nil {
+		scores, err := evaluateSmartPlaylist(db, userID, p)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate playlist"})
+			return
+		}
+		cached = cachedPlaylist{Scores: scores, EvaluatedAt: time.Now().UTC()}
+		if payload, err := json.Marshal(cached); err == nil {
+			if err := database.GetRedis().Set(ctx, key, payload, smartPlaylistCacheTTL).Err(); err != nil {
+				log.Printf("Failed to cache smart playlist %s: %v", p.ID, err)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"playlist": p, "scores": cached.Scores, "evaluated_at": cached.EvaluatedAt})
+}
+
+// CreateSmartPlaylist saves a named set of library rules
+func CreateSmartPlaylist(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.SmartPlaylistInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validLibraryFilters(c, req.Rules) {
+		return
+	}
+	if req.MaxScores == 0 {
+		req.MaxScores = defaultPlaylistScores
+	}
+	rules, err := json.Marshal(req.Rules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rules"})
+		return
+	}
+
+	db := database.GetDB()
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM smart_playlists WHERE user_id = $1", userID).Scan(&count); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save playlist"})
+		return
+	}
+	if count >= maxSmartPlaylists {
+		c.JSON(http.StatusConflict, gin.H{"error": "Smart playlist limit reached"})
+		return
+	}
+
+	p, err := scanSmartPlaylist(db.QueryRow(`
+		INSERT INTO smart_playlists (user_id, name, description, rules, max_scores)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, name) DO NOTHING
+		RETURNING `+smartPlaylistColumns,
+		userID, req.Name, req.Description, rules, req.MaxScores,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "A playlist with this name already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save playlist"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusCreated, p)
+}
+
+// UpdateSmartPlaylist replaces a smart playlist's name, description and rules
+func UpdateSmartPlaylist(c *gin.Context) {
+	userID := c.GetString("user_id")
+	playlistID := c.Param("id")
+	if _, err := uuid.Parse(playlistID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	var req models.SmartPlaylistInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validLibraryFilters(c, req.Rules) {
+		return
+	}
+	if req.MaxScores == 0 {
+		req.MaxScores = defaultPlaylistScores
+	}
+	rules, err := json.Marshal(req.Rules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rules"})
+		return
+	}
+
+	db := database.GetDB()
+	var taken bool
+	err = db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM smart_playlists WHERE user_id = $1 AND name = $2 AND id <> $3)",
+		userID, req.Name, playlistID,
+	).Scan(&taken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update playlist"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A playlist with this name already exists"})
+		return
+	}
+
+	p, err := scanSmartPlaylist(db.QueryRow(`
+		UPDATE smart_playlists SET name = $1, description = $2, rules = $3, max_scores = $4
+		WHERE id = $5 AND user_id = $6
+		RETURNING `+smartPlaylistColumns,
+		req.Name, req.Description, rules, req.MaxScores, playlistID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update playlist"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, p)
+}
+
+// DeleteSmartPlaylist removes a smart playlist
+func DeleteSmartPlaylist(c *gin.Context) {
+	userID := c.GetString("user_id")
+	playlistID := c.Param("id")
+	if _, err := uuid.Parse(playlistID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	result, err := database.GetDB().Exec(
+		"DELETE FROM smart_playlists WHERE id = $1 AND user_id = $2", playlistID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete playlist"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Playlist deleted"})
+}
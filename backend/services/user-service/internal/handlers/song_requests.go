@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// songRequestSelect selects song requests with whether the user in $1 voted
+const songRequestSelect = `
+	SELECT r.id, r.title, r.artist, r.notes, r.status, u.username, r.merged_into,
+		r.score_id, r.vote_count, v.user_id IS NOT NULL, r.created_at, r.updated_at
+	FROM song_requests r
+	LEFT JOIN users u ON u.id = r.user_id
+	LEFT JOIN song_request_votes v ON v.request_id = r.id AND v.user_id = $1`
+
+func scanSongRequest(row rowScanner) (models.SongRequest, error) {
+	var r models.SongRequest
+	err := row.Scan(&r.ID, &r.Title, &r.Artist, &r.Notes, &r.Status, &r.RequestedBy, &r.MergedInto,
+		&r.ScoreID, &r.VoteCount, &r.Voted, &r.CreatedAt, &r.UpdatedAt)
+	return r, err
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// refreshVoteCount recomputes the denormalized vote count of a song request
+func refreshVoteCount(db execer, requestID string) error {
+	_, err := db.Exec(`
+		UPDATE song_requests
+		SET vote_count = (SELECT COUNT(*) FROM song_request_votes WHERE request_id = $1)
+		WHERE id = $1`,
+		requestID,
+	)
+	return err
+}
+
+// respondSongRequest writes a single song request as seen by userID
+func respondSongRequest(c *gin.Context, db *sql.DB, status int, userID, requestID string) {
+	r, err := scanSongRequest(db.QueryRow(songRequestSelect+" WHERE r.id = $2", userID, requestID))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Song request not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get song request"})
+		return
+	}
+	c.JSON(status, r)
+}
+
+// ListSongRequests lists the requests board. Merged duplicates are hidden;
+// ?status= filters, ?q= searches title and artist, ?sort=new orders by age
+// instead of votes.
+func ListSongRequests(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.SongRequestRequested, models.SongRequestInProgress, models.SongRequestPublished:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be requested, in_progress or published"})
+		return
+	}
+
+	order := "r.vote_count DESC, r.created_at DESC"
+	if c.Query("sort") == "new" {
+		order = "r.created_at DESC"
+	}
+
+	var search string
+	if q := c.Query("q"); q != "" {
+		search = "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q) + "%"
+	}
+
+	db := database.GetReadDBFor(userID)
+	rows, err := db.Query(songRequestSelect+`
+		WHERE r.status <> 'merged'
+		  AND (u.shadow_banned_at IS NULL OR r.user_id = $1)
+		  AND ($2 = '' OR r.status = $2)
+		  AND ($3 = '' OR r.title ILIKE $3 OR r.artist ILIKE $3)
+		ORDER BY `+order+`
+		LIMIT $4 OFFSET $5`,
+		userID, status, search, limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get song requests"})
+		return
+	}
+	defer rows.Close()
+
+	requests := []models.SongRequest{}
+	for rows.Next() {
+		r, err := scanSongRequest(rows)
+		if err != nil {
+			continue
+		}
+		requests = append(requests, r)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"song_requests": requests, "limit": limit, "offset": offset})
+}
+
+// GetSongRequest returns a song request. Merged requests carry merged_into so
+// clients can follow them to the surviving request.
+func GetSongRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+	requestID := c.Param("id")
+	if _, err := uuid.Parse(requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	respondSongRequest(c, database.GetReadDBFor(userID), http.StatusOK, userID, requestID)
+}
+
+// CreateSongRequest submits a song to the board with the requester's vote.
+// A song that is already on the board is rejected with the existing request ID.
+func CreateSongRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.SongRequestCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var notes interface{}
+	if req.Notes != "" {
+		notes = req.Notes
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(`
+		INSERT INTO song_requests (user_id, title, artist, notes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+		RETURNING id`,
+		userID, req.Title, req.Artist, notes,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		var existing string
+		err = tx.QueryRow(`
+			SELECT id FROM song_requests
+			WHERE LOWER(title) = LOWER($1) AND LOWER(artist) = LOWER($2) AND status <> 'merged'`,
+			req.Title, req.Artist,
+		).Scan(&existing)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create song request"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "This song has already been requested", "request_id": existing})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create song request"})
+		return
+	}
+
+	if _, err := tx.Exec("INSERT INTO song_request_votes (request_id, user_id) VALUES ($1, $2)", id, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create song request"})
+		return
+	}
+	if err := refreshVoteCount(tx, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create song request"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create song request"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondSongRequest(c, db, http.StatusCreated, userID, id)
+}
+
+// VoteSongRequest upvotes an open song request. Voting twice is a no-op.
+func VoteSongRequest(c *gin.Context) {
+	setSongRequestVote(c, true)
+}
+
+// UnvoteSongRequest withdraws the current user's vote
+func UnvoteSongRequest(c *gin.Context) {
+	setSongRequestVote(c, false)
+}
+
+func setSongRequestVote(c *gin.Context, vote bool) {
+	userID := c.GetString("user_id")
+	requestID := c.Param("id")
+	if _, err := uuid.Parse(requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	db := database.GetDB()
+	var status string
+	err := db.QueryRow("SELECT status FROM song_requests WHERE id = $1", requestID).Scan(&status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Song request not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if status != models.SongRequestRequested && status != models.SongRequestInProgress {
+		c.JSON(http.StatusConflict, gin.H{"error": "Song request is closed for voting"})
+		return
+	}
+
+	if vote {
+		_, err = db.Exec(`
+			INSERT INTO song_request_votes (request_id, user_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`,
+			requestID, userID,
+		)
+	} else {
+		_, err = db.Exec("DELETE FROM song_request_votes WHERE request_id = $1 AND user_id = $2", requestID, userID)
+	}
+	if err == nil {
+		err = refreshVoteCount(db, requestID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vote"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondSongRequest(c, db, http.StatusOK, userID, requestID)
+}
+
+// UpdateSongRequestStatus moves a request through its workflow (admin).
+// Publishing requires the resulting score and notifies everyone who voted.
+func UpdateSongRequestStatus(c *gin.Context) {
+	requestID := c.Param("id")
+	if _, err := uuid.Parse(requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	var req models.SongRequestStatusUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Status == models.SongRequestPublished && req.ScoreID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "score_id is required to publish a request"})
+		return
+	}
+
+	db := database.GetDB()
+	var previous, title, artist string
+	err := db.QueryRow(`
+		UPDATE song_requests r
+		SET status = $1, score_id = COALESCE($2, r.score_id)
+		FROM (SELECT status FROM song_requests WHERE id = $3 FOR UPDATE) old
+		WHERE r.id = $3 AND r.status <> 'merged'
+		RETURNING old.status, r.title, r.artist`,
+		req.Status, req.ScoreID, requestID,
+	).Scan(&previous, &title, &artist)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Song request not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update song request"})
+		return
+	}
+
+	if req.Status == models.SongRequestPublished && previous != models.SongRequestPublished {
+		notifySongRequestVoters(c, requestID, title, artist, req.ScoreID.String())
+	}
+
+	respondSongRequest(c, db, http.StatusOK, c.GetString("user_id"), requestID)
+}
+
+// notifySongRequestVoters tells every voter that a requested song was published
+func notifySongRequestVoters(c *gin.Context, requestID, title, artist, scoreID string) {
+	rows, err := database.GetDB().Query("SELECT user_id FROM song_request_votes WHERE request_id = $1", requestID)
+	if err != nil {
+		log.Printf("Failed to load voters for song request %s: %v", requestID, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var voterID string
+		if err := rows.Scan(&voterID); err != nil {
+			continue
+		}
+		err := notify.Enqueue(c.Request.Context(), notify.Notification{
+			Type:     notify.TypeSongRequestPublished,
+			UserID:   voterID,
+			Channels: []string{models.ChannelPush, models.ChannelEmail},
+			Data: map[string]interface{}{
+				"request_id": requestID,
+				"title":      title,
+				"artist":     artist,
+				"score_id":   scoreID,
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to enqueue song request notification for %s: %v", voterID, err)
+		}
+	}
+}
+
+// MergeSongRequest closes a duplicate request into another (admin). Votes
+// move to the surviving request and earlier merges are re-pointed at it.
+func MergeSongRequest(c *gin.Context) {
+	requestID := c.Param("id")
+	if _, err := uuid.Parse(requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	var req models.SongRequestMerge
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	into := req.Into.String()
+	if into == requestID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge a request into itself"})
+		return
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		"SELECT id, status FROM song_requests WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", requestID, into)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	statuses := map[string]string{}
+	for rows.Next() {
+		var id, status string
+		if err := rows.Scan(&id, &status); err == nil {
+			statuses[id] = status
+		}
+	}
+	rows.Close()
+
+	if len(statuses) != 2 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Song request not found"})
+		return
+	}
+	if statuses[requestID] == models.SongRequestMerged || statuses[requestID] == models.SongRequestPublished {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only open requests can be merged"})
+		return
+	}
+	if statuses[into] == models.SongRequestMerged {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot merge into a merged request"})
+		return
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO song_request_votes (request_id, user_id, created_at)
+		SELECT $2, user_id, created_at FROM song_request_votes WHERE request_id = $1
+		ON CONFLICT DO NOTHING`,
+		requestID, into,
+	)
+	if err == nil {
+		_, err = tx.Exec("DELETE FROM song_request_votes WHERE request_id = $1", requestID)
+	}
+	if err == nil {
+		_, err = tx.Exec(`
+			UPDATE song_requests SET status = 'merged', merged_into = $2, vote_count = 0 WHERE id = $1`,
+			requestID, into,
+		)
+	}
+	if err == nil {
+		_, err = tx.Exec("UPDATE song_requests SET merged_into = $2 WHERE merged_into = $1", requestID, into)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge song requests"})
+		return
+	}
+	if err := refreshVoteCount(tx, into); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge song requests"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge song requests"})
+		return
+	}
+
+	respondSongRequest(c, db, http.StatusOK, c.GetString("user_id"), into)
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// queryIncidents loads incidents matched by where, newest first, with their updates
+func queryIncidents(db *sql.DB, where string, args ...interface{}) ([]models.Incident, error) {
+	rows, err := db.Query(`
+		SELECT id, title, impact, status, components, started_at, resolved_at
+		FROM status_incidents
+		WHERE `+where+`
+		ORDER BY started_at DESC
+		LIMIT 100`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	incidents := []models.Incident{}
+	index := map[uuid.UUID]int{}
+	ids := []string{}
+	for rows.Next() {
+		var inc models.Incident
+		if err := rows.Scan(&inc.ID, &inc.Title, &inc.Impact, &inc.Status, pq.Array(&inc.Components),
+			&inc.StartedAt, &inc.ResolvedAt); err != nil {
+			return nil, err
+		}
+		inc.Updates = []models.IncidentUpdate{}
+		index[inc.ID] = len(incidents)
+		ids = append(ids, inc.ID.String())
+		incidents = append(incidents, inc)
+	}
+	if len(incidents) == 0 {
+		return incidents, nil
+	}
+
+	updates, err := db.Query(`
+		SELECT incident_id, status, message, created_at
+		FROM status_incident_updates WHERE incident_id = ANY($1)
+		ORDER BY created_at DESC`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer updates.Close()
+
+	for updates.Next() {
+		var incidentID uuid.UUID
+		var u models.IncidentUpdate
+		if err := updates.Scan(&incidentID, &u.Status, &u.Message, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		i := index[incidentID]
+		incidents[i].Updates = append(incidents[i].Updates, u)
+	}
+	return incidents, nil
+}
+
+// GetServiceStatus returns the status of each component and the active
+// incidents, for the public status page and in-app degradation banners
+func GetServiceStatus(c *gin.Context) {
+	incidents, err := queryIncidents(database.GetReadDB(), "resolved_at IS NULL")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get status"})
+		return
+	}
+
+	statuses := map[string]string{}
+	for _, inc := range incidents {
+		for _, name := range inc.Components {
+			statuses[name] = models.WorseComponentStatus(statuses[name], models.ComponentStatusForImpact(inc.Impact))
+		}
+	}
+
+	overall := models.ComponentOperational
+	components := make([]models.ComponentStatus, 0, len(models.StatusComponents))
+	for _, name := range models.StatusComponents {
+		status := models.WorseComponentStatus(models.ComponentOperational, statuses[name])
+		overall = models.WorseComponentStatus(overall, status)
+		components = append(components, models.ComponentStatus{Name: name, Status: status})
+	}
+
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, gin.H{
+		"status":           overall,
+		"components":       components,
+		"active_incidents": incidents,
+		"checked_at":       time.Now().Unix(),
+	})
+}
+
+// GetIncidentHistory returns incidents that started in the last ?days= days (default 90)
+func GetIncidentHistory(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "90"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return
+	}
+
+	incidents, err := queryIncidents(database.GetReadDB(), "started_at > $1", time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get incident history"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, gin.H{"incidents": incidents, "days": days})
+}
+
+// respondIncident writes a single incident with its updates
+func respondIncident(c *gin.Context, status int, incidentID string) {
+	incidents, err := queryIncidents(database.GetDB(), "id = $1", incidentID)
+	if err != nil || len(incidents) == 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get incident"})
+		return
+	}
+	c.JSON(status, incidents[0])
+}
+
+// CreateIncident opens an incident with its first update (admin)
+func CreateIncident(c *gin.Context) {
+	var req models.IncidentCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(`
+		INSERT INTO status_incidents (title, impact, components)
+		VALUES ($1, $2, $3)
+		RETURNING id`,
+		req.Title, req.Impact, pq.Array(req.Components),
+	).Scan(&id)
+	if err == nil {
+		_, err = tx.Exec(`
+			INSERT INTO status_incident_updates (incident_id, status, message, created_by)
+			VALUES ($1, 'investigating', $2, $3)`,
+			id, req.Message, c.GetString("user_id"),
+		)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create incident"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create incident"})
+		return
+	}
+
+	respondIncident(c, http.StatusCreated, id)
+}
+
+// PostIncidentUpdate adds an update to an open incident, moving it to the
+// update's status and resolving it when the status is "resolved" (admin)
+func PostIncidentUpdate(c *gin.Context) {
+	incidentID := c.Param("id")
+	if _, err := uuid.Parse(incidentID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+		return
+	}
+
+	var req models.IncidentUpdateCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var components interface{}
+	if len(req.Components) > 0 {
+		components = pq.Array(req.Components)
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE status_incidents SET
+			status = $1,
+			impact = COALESCE($2, impact),
+			components = COALESCE($3, components),
+			resolved_at = CASE WHEN $1 = 'resolved' THEN NOW() END
+		WHERE id = $4 AND resolved_at IS NULL`,
+		req.Status, nullIfEmpty(req.Impact), components, incidentID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Open incident not found"})
+		return
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO status_incident_updates (incident_id, status, message, created_by)
+		VALUES ($1, $2, $3, $4)`,
+		incidentID, req.Status, req.Message, c.GetString("user_id"),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update incident"})
+		return
+	}
+
+	respondIncident(c, http.StatusOK, incidentID)
+}
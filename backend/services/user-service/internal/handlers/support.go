@@ -0,0 +1,456 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const supportTicketColumns = `
+	t.id, t.user_id, u.username, t.subject, t.category, t.status, t.priority, t.tier,
+	t.assignee_id, t.first_response_due_at, t.resolution_due_at, t.first_responded_at,
+	t.resolved_at, t.created_at, t.updated_at`
+
+// supportPriorityOrder sorts the staff queue with the most urgent tickets first
+const supportPriorityOrder = `
+	CASE t.priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END`
+
+func scanSupportTicket(row rowScanner) (models.SupportTicket, error) {
+	var t models.SupportTicket
+	err := row.Scan(&t.ID, &t.UserID, &t.Username, &t.Subject, &t.Category, &t.Status, &t.Priority, &t.Tier,
+		&t.AssigneeID, &t.FirstResponseDueAt, &t.ResolutionDueAt, &t.FirstRespondedAt,
+		&t.ResolvedAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return t, err
+	}
+
+	// A timer is breached if it ran out before the event, or is still running past its deadline
+	now := time.Now()
+	if t.FirstRespondedAt != nil {
+		t.FirstResponseOverdue = t.FirstRespondedAt.After(t.FirstResponseDueAt)
+	} else {
+		t.FirstResponseOverdue = now.After(t.FirstResponseDueAt)
+	}
+	if t.ResolvedAt != nil {
+		t.ResolutionOverdue = t.ResolvedAt.After(t.ResolutionDueAt)
+	} else {
+		t.ResolutionOverdue = now.After(t.ResolutionDueAt)
+	}
+	return t, nil
+}
+
+// respondSupportTicket writes a ticket with its message thread. A non-empty
+// ownerID restricts the lookup to that user's tickets.
+func respondSupportTicket(c *gin.Context, db *sql.DB, status int, ticketID, ownerID string) {
+	t, err := scanSupportTicket(db.QueryRow(`
+		SELECT `+supportTicketColumns+`
+		FROM support_tickets t JOIN users u ON u.id = t.user_id
+		WHERE t.id = $1 AND ($2 = '' OR t.user_id::text = $2)`,
+		ticketID, ownerID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get ticket"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT id, is_staff, body, attachment_urls, created_at
+		FROM support_ticket_messages WHERE ticket_id = $1
+		ORDER BY created_at`,
+		ticketID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get ticket"})
+		return
+	}
+	defer rows.Close()
+
+	t.Messages = []models.SupportTicketMessage{}
+	for rows.Next() {
+		var m models.SupportTicketMessage
+		if err := rows.Scan(&m.ID, &m.IsStaff, &m.Body, pq.Array(&m.AttachmentURLs), &m.CreatedAt); err != nil {
+			continue
+		}
+		t.Messages = append(t.Messages, m)
+	}
+
+	c.JSON(status, t)
+}
+
+// listSupportTickets writes the tickets matched by query, without messages
+func listSupportTickets(c *gin.Context, db *sql.DB, query string, args ...interface{}) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tickets"})
+		return
+	}
+	defer rows.Close()
+
+	tickets := []models.SupportTicket{}
+	for rows.Next() {
+		t, err := scanSupportTicket(rows)
+		if err != nil {
+			continue
+		}
+		tickets = append(tickets, t)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tickets": tickets})
+}
+
+// resolutionDeadline returns when a ticket of the given tier opened or
+// reopened now must be resolved
+func resolutionDeadline(tier string, from time.Time) time.Time {
+	return from.Add(time.Duration(models.GetTierCapabilities(tier).SupportResolutionHours) * time.Hour)
+}
+
+// CreateSupportTicket opens a ticket. Priority and SLA deadlines come from the
+// user's subscription tier at the time the ticket is opened.
+func CreateSupportTicket(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.SupportTicketCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.AttachmentURLs == nil {
+		req.AttachmentURLs = []string{}
+	}
+
+	db := database.GetDB()
+	var tier string
+	if err := db.QueryRow("SELECT subscription_tier FROM users WHERE id = $1", userID).Scan(&tier); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	caps := models.GetTierCapabilities(tier)
+	now := time.Now()
+
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var ticketID string
+	err = tx.QueryRow(`
+		INSERT INTO support_tickets (user_id, subject, category, priority, tier, first_response_due_at, resolution_due_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		userID, req.Subject, req.Category, caps.SupportPriority, caps.Tier,
+		now.Add(time.Duration(caps.SupportFirstResponseHours)*time.Hour),
+		resolutionDeadline(caps.Tier, now),
+	).Scan(&ticketID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ticket"})
+		return
+	}
+	_, err = tx.Exec(`
+		INSERT INTO support_ticket_messages (ticket_id, author_id, body, attachment_urls)
+		VALUES ($1, $2, $3, $4)`,
+		ticketID, userID, req.Body, pq.Array(req.AttachmentURLs),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ticket"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ticket"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondSupportTicket(c, db, http.StatusCreated, ticketID, userID)
+}
+
+// ListSupportTickets lists the current user's tickets, newest first
+func ListSupportTickets(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	listSupportTickets(c, database.GetReadDBFor(userID), `
+		SELECT `+supportTicketColumns+`
+		FROM support_tickets t JOIN users u ON u.id = t.user_id
+		WHERE t.user_id = $1
+		ORDER BY t.created_at DESC
+		LIMIT 50`,
+		userID,
+	)
+}
+
+// GetSupportTicket returns one of the current user's tickets with its messages
+func GetSupportTicket(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	respondSupportTicket(c, database.GetReadDBFor(userID), http.StatusOK, ticketID, userID)
+}
+
+// ReplySupportTicket adds the user's message to a ticket, reopening it if it
+// was waiting on the user or resolved. Reopening a resolved ticket restarts
+// its resolution SLA. Closed tickets cannot be reopened.
+func ReplySupportTicket(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req models.SupportTicketReply
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.AttachmentURLs == nil {
+		req.AttachmentURLs = []string{}
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var status, tier string
+	err = tx.QueryRow(
+		"SELECT status, tier FROM support_tickets WHERE id = $1 AND user_id = $2 FOR UPDATE", ticketID, userID,
+	).Scan(&status, &tier)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if status == models.TicketClosed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Ticket is closed; open a new ticket instead"})
+		return
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO support_ticket_messages (ticket_id, author_id, body, attachment_urls)
+		VALUES ($1, $2, $3, $4)`,
+		ticketID, userID, req.Body, pq.Array(req.AttachmentURLs),
+	)
+	if err == nil {
+		_, err = tx.Exec(`
+			UPDATE support_tickets SET status = 'open', resolved_at = NULL,
+				resolution_due_at = CASE WHEN resolved_at IS NOT NULL THEN $1 ELSE resolution_due_at END
+			WHERE id = $2`,
+			resolutionDeadline(tier, time.Now()), ticketID,
+		)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add reply"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add reply"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondSupportTicket(c, db, http.StatusOK, ticketID, userID)
+}
+
+// CloseSupportTicket lets the user close their own ticket
+func CloseSupportTicket(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE support_tickets SET status = 'closed', resolved_at = COALESCE(resolved_at, NOW())
+		WHERE id = $1 AND user_id = $2 AND status <> 'closed'`,
+		ticketID, userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close ticket"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Open ticket not found"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	respondSupportTicket(c, db, http.StatusOK, ticketID, userID)
+}
+
+// ListSupportQueue lists tickets for staff, most urgent and soonest due first.
+// ?status= filters (default open) and ?overdue=true keeps only breached SLAs.
+func ListSupportQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", models.TicketOpen)
+	overdue := c.Query("overdue") == "true"
+
+	listSupportTickets(c, database.GetReadDB(), `
+		SELECT `+supportTicketColumns+`
+		FROM support_tickets t JOIN users u ON u.id = t.user_id
+		WHERE t.status = $1
+		  AND (NOT $2 OR (t.first_responded_at IS NULL AND t.first_response_due_at < NOW())
+		           OR (t.resolved_at IS NULL AND t.resolution_due_at < NOW()))
+		ORDER BY `+supportPriorityOrder+`, LEAST(
+			CASE WHEN t.first_responded_at IS NULL THEN t.first_response_due_at END,
+			t.resolution_due_at)
+		LIMIT 100`,
+		status, overdue,
+	)
+}
+
+// GetSupportTicketAdmin returns any ticket with its messages (staff)
+func GetSupportTicketAdmin(c *gin.Context) {
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	respondSupportTicket(c, database.GetDB(), http.StatusOK, ticketID, "")
+}
+
+// ReplySupportTicketAdmin adds a staff response, stopping the first-response
+// timer, marking the ticket as waiting on the user and notifying them
+func ReplySupportTicketAdmin(c *gin.Context) {
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req models.SupportTicketReply
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.AttachmentURLs == nil {
+		req.AttachmentURLs = []string{}
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var userID, subject string
+	err = tx.QueryRow(`
+		UPDATE support_tickets
+		SET first_responded_at = COALESCE(first_responded_at, NOW()), status = 'waiting_on_user'
+		WHERE id = $1 AND status <> 'closed'
+		RETURNING user_id, subject`,
+		ticketID,
+	).Scan(&userID, &subject)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Open ticket not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add reply"})
+		return
+	}
+	_, err = tx.Exec(`
+		INSERT INTO support_ticket_messages (ticket_id, author_id, is_staff, body, attachment_urls)
+		VALUES ($1, $2, true, $3, $4)`,
+		ticketID, c.GetString("user_id"), req.Body, pq.Array(req.AttachmentURLs),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add reply"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add reply"})
+		return
+	}
+	database.MarkWrite(userID)
+
+	err = notify.Enqueue(c.Request.Context(), notify.Notification{
+		Type:     notify.TypeSupportReply,
+		UserID:   userID,
+		Channels: []string{models.ChannelEmail},
+		Data:     map[string]interface{}{"ticket_id": ticketID, "subject": subject},
+	})
+	if err != nil {
+		log.Printf("Failed to enqueue support reply notification for %s: %v", userID, err)
+	}
+
+	respondSupportTicket(c, db, http.StatusOK, ticketID, "")
+}
+
+// UpdateSupportTicket changes a ticket's status, priority or assignee (staff).
+// Resolving or closing stops the resolution timer; reopening restarts it.
+func UpdateSupportTicket(c *gin.Context) {
+	ticketID := c.Param("id")
+	if _, err := uuid.Parse(ticketID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	var req models.SupportTicketUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var tier string
+	err := db.QueryRow("SELECT tier FROM support_tickets WHERE id = $1", ticketID).Scan(&tier)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update ticket"})
+		return
+	}
+
+	// SET expressions see the row before the update, so resolution_due_at
+	// restarts only when a resolved or closed ticket is reopened
+	_, err = db.Exec(`
+		UPDATE support_tickets SET
+			status = COALESCE($1, status),
+			priority = COALESCE($2, priority),
+			assignee_id = COALESCE($3, assignee_id),
+			resolved_at = CASE
+				WHEN $1 IN ('resolved', 'closed') THEN COALESCE(resolved_at, NOW())
+				WHEN $1 IN ('open', 'waiting_on_user') THEN NULL
+				ELSE resolved_at END,
+			resolution_due_at = CASE
+				WHEN $1 IN ('open', 'waiting_on_user') AND resolved_at IS NOT NULL THEN $5
+				ELSE resolution_due_at END
+		WHERE id = $4`,
+		req.Status, req.Priority, req.AssigneeID, ticketID, resolutionDeadline(tier, time.Now()),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update ticket"})
+		return
+	}
+
+	respondSupportTicket(c, db, http.StatusOK, ticketID, "")
+}
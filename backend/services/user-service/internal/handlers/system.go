@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"user-service/internal/jobs"
+	"user-service/internal/lock"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLockStats returns distributed lock counters for this instance
+func GetLockStats(c *gin.Context) {
+	c.JSON(http.StatusOK, lock.GetStats())
+}
+
+// GetStorageReconciliation returns the latest storage reconciliation report
+func GetStorageReconciliation(c *gin.Context) {
+	report, err := jobs.LastReconciliationReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reconciliation report"})
+		return
+	}
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reconciliation has not run yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
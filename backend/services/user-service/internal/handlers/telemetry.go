@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"user-service/internal/analytics"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// telemetryMaxAge is how old a client event may be; older events (e.g. from a
+// device that was offline for days) are rejected
+const telemetryMaxAge = 24 * time.Hour
+
+// telemetrySampleRate returns the fraction of events of a type that are kept.
+// Playback stalls are rare and always kept; TELEMETRY_SAMPLE_RATE applies to
+// the high-volume types.
+func telemetrySampleRate(eventType string) float64 {
+	if eventType == models.TelemetryPlaybackStall {
+		return 1
+	}
+	if r, err := strconv.ParseFloat(os.Getenv("TELEMETRY_SAMPLE_RATE"), 64); err == nil && r >= 0 && r <= 1 {
+		return r
+	}
+	return 1
+}
+
+// IngestTelemetry accepts a batch of client telemetry events, samples them and
+// forwards the kept ones into the pseudonymized analytics pipeline. Invalid
+// events are reported back by index while the valid ones are kept.
+func IngestTelemetry(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.TelemetryBatch
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	rejected := []models.BatchItemError{}
+	var events []analytics.Event
+	sampledOut := 0
+	for i, e := range req.Events {
+		err := binding.Validator.ValidateStruct(&e)
+		if err == nil {
+			err = e.Validate()
+		}
+		if err != nil {
+			rejected = append(rejected, models.BatchItemError{Index: i, Error: err.Error()})
+			continue
+		}
+		if e.Timestamp.Before(now.Add(-telemetryMaxAge)) || e.Timestamp.After(now.Add(5*time.Minute)) {
+			rejected = append(rejected, models.BatchItemError{Index: i, Error: "timestamp must be within the last 24 hours"})
+			continue
+		}
+
+		rate := telemetrySampleRate(e.Type)
+		if rate < 1 && rand.Float64() >= rate {
+			sampledOut++
+			continue
+		}
+
+		// Client properties first so they cannot overwrite the validated fields
+		properties := models.JSONB{}
+		for k, v := range e.Properties {
+			properties[k] = v
+		}
+		properties["sample_rate"] = rate
+		if e.DurationMS != nil {
+			properties["duration_ms"] = *e.DurationMS
+		}
+		if e.ScoreID != "" {
+			properties["score_id"] = e.ScoreID
+		}
+		if e.Feature != "" {
+			properties["feature"] = e.Feature
+		}
+		if req.Release != "" {
+			properties["release"] = req.Release
+		}
+		if req.Platform != "" {
+			properties["platform"] = req.Platform
+		}
+		events = append(events, analytics.Event{Name: e.Type, Properties: properties, OccurredAt: e.Timestamp})
+	}
+	if len(rejected) == len(req.Events) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No valid events in batch", "rejected": rejected})
+		return
+	}
+
+	analytics.TrackMany(c.Request.Context(), userID, events)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"accepted":    len(events),
+		"sampled_out": sampledOut,
+		"rejected":    rejected,
+	})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"user-service/internal/analytics"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/transcription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// SubmitTranscriptionBatch queues several transcription jobs as one batch on
+// behalf of the API key owner. Batch size is limited by tier; invalid items
+// are reported back by index while the valid ones are queued.
+func SubmitTranscriptionBatch(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.TranscriptionBatchSubmit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var tier string
+	if err := db.QueryRow("SELECT subscription_tier FROM users WHERE id = $1", userID).Scan(&tier); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	caps := models.GetTierCapabilities(tier)
+	if len(req.Items) > caps.MaxBatchTranscriptions {
+		message := fmt.Sprintf("Batches on the %s plan are limited to %d sources", tier, caps.MaxBatchTranscriptions)
+		if caps.MaxBatchTranscriptions == 0 {
+			message = fmt.Sprintf("Batch submission is not available on the %s plan", tier)
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": &models.QuotaError{
+			Code:      models.QuotaBatchSize,
+			Message:   message,
+			Tier:      tier,
+			Limit:     caps.MaxBatchTranscriptions,
+			Requested: len(req.Items),
+		}})
+		return
+	}
+
+	type batchItem struct {
+		index int
+		item  models.TranscriptionSubmit
+	}
+	var valid []batchItem
+	rejected := []models.BatchItemError{}
+	for i, item := range req.Items {
+		if err := binding.Validator.ValidateStruct(&item); err != nil {
+			rejected = append(rejected, models.BatchItemError{Index: i, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, batchItem{index: i, item: item})
+	}
+	if len(valid) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No valid items in batch", "rejected": rejected})
+		return
+	}
+
+	var apiKeyID interface{}
+	if id := c.GetString("api_key_id"); id != "" {
+		apiKeyID = id
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	batch := models.TranscriptionBatch{
+		Status:   models.BatchPending,
+		Total:    len(valid),
+		Counts:   map[string]int{"pending": len(valid)},
+		Jobs:     []models.TranscriptionJob{},
+		Rejected: rejected,
+	}
+	err = tx.QueryRow(`
+		INSERT INTO transcription_batches (user_id, api_key_id, total)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`,
+		userID, apiKeyID, batch.Total,
+	).Scan(&batch.ID, &batch.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit batch"})
+		return
+	}
+
+	for _, v := range valid {
+		var job models.TranscriptionJob
+		err := tx.QueryRow(`
+			INSERT INTO transcription_jobs (user_id, batch_id, input_type, input_url, transcription_settings, input_metadata, priority)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, status, progress, input_type, input_url, created_at`,
+			userID, batch.ID, v.item.InputType, v.item.InputURL, v.item.Settings,
+			models.JSONB{"source": "public_api", "api_key_id": c.GetString("api_key_id"), "batch_index": v.index},
+			caps.TranscriptionPriority,
+		).Scan(&job.ID, &job.Status, &job.Progress, &job.InputType, &job.InputURL, &job.CreatedAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit batch"})
+			return
+		}
+		batch.Jobs = append(batch.Jobs, job)
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit batch"})
+		return
+	}
+	analytics.Track(c.Request.Context(), analytics.Event{
+		Name:       analytics.EventTranscriptionSubmitted,
+		UserID:     userID,
+		Properties: models.JSONB{"source": "public_api", "batch_size": len(batch.Jobs)},
+	})
+
+	c.JSON(http.StatusAccepted, batch)
+}
+
+// GetTranscriptionBatch returns a batch's aggregate status and its jobs,
+// including the error of each failed job
+func GetTranscriptionBatch(c *gin.Context) {
+	userID := c.GetString("user_id")
+	batchID := c.Param("id")
+	if _, err := uuid.Parse(batchID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	db := database.GetDB()
+	batch := models.TranscriptionBatch{Counts: map[string]int{}, Jobs: []models.TranscriptionJob{}}
+	err := db.QueryRow(
+		"SELECT id, total, created_at FROM transcription_batches WHERE id = $1 AND user_id = $2",
+		batchID, userID,
+	).Scan(&batch.ID, &batch.Total, &batch.CreatedAt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+		return
+	}
+
+	rows, err := db.Query(`
+		SELECT `+transcription.JobColumns+`
+		FROM transcription_jobs WHERE batch_id = $1
+		ORDER BY created_at, id`,
+		batchID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get batch"})
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		job, err := transcription.ScanJob(rows)
+		if err != nil {
+			continue
+		}
+		batch.Counts[job.Status]++
+		batch.Jobs = append(batch.Jobs, job)
+	}
+	batch.Status = models.BatchStatus(batch.Counts)
+
+	c.JSON(http.StatusOK, batch)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/transcription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListDeadLetteredTranscriptions lists jobs that failed for good, newest
+// first, with the number dead-lettered in each failure class (admin)
+func ListDeadLetteredTranscriptions(c *gin.Context) {
+	class := c.Query("failure_class")
+	switch class {
+	case "", transcription.FailureTransient, transcription.FailureWorkerCrash,
+		transcription.FailureBadAudio, transcription.FailureUnsupportedFormat:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid failure class"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 200"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	db := database.GetReadDB()
+	summary := map[string]int{transcription.FailureTransient: 0, transcription.FailureWorkerCrash: 0,
+		transcription.FailureBadAudio: 0, transcription.FailureUnsupportedFormat: 0}
+	rows, err := db.Query(`
+		SELECT failure_class, COUNT(*) FROM transcription_jobs
+		WHERE dead_lettered_at IS NOT NULL AND failure_class IS NOT NULL
+		GROUP BY failure_class`)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dead letters"})
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var failureClass string
+		var count int
+		if err := rows.Scan(&failureClass, &count); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dead letters"})
+			return
+		}
+		summary[failureClass] = count
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dead letters"})
+		return
+	}
+
+	jobRows, err := db.Query(`
+		SELECT j.id, j.user_id, u.username, j.batch_id, j.input_type, j.input_url, j.attempts,
+			   j.failure_class, j.error_message, j.error_details, j.created_at, j.dead_lettered_at
+		FROM transcription_jobs j JOIN users u ON u.id = j.user_id
+		WHERE j.dead_lettered_at IS NOT NULL AND ($1 = '' OR j.failure_class = $1)
+		ORDER BY j.dead_lettered_at DESC, j.id
+		LIMIT $2 OFFSET $3`,
+		class, limit, offset,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dead letters"})
+		return
+	}
+	defer jobRows.Close()
+
+	jobs := []models.DeadLetteredTranscriptionJob{}
+	for jobRows.Next() {
+		var j models.DeadLetteredTranscriptionJob
+		if err := jobRows.Scan(&j.ID, &j.UserID, &j.Username, &j.BatchID, &j.InputType, &j.InputURL, &j.Attempts,
+			&j.FailureClass, &j.ErrorMessage, &j.ErrorDetails, &j.CreatedAt, &j.DeadLetteredAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dead letters"})
+			return
+		}
+		jobs = append(jobs, j)
+	}
+	if err := jobRows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dead letters"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary": summary,
+		"jobs":    jobs,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// RequeueTranscriptionJob sends a dead-lettered job back to the queue with a
+// fresh set of attempts (admin)
+func RequeueTranscriptionJob(c *gin.Context) {
+	jobID := c.Param("id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	var ownerID, failureClass string
+	err := database.GetDB().QueryRow(`
+		UPDATE transcription_jobs j SET status = $2, progress = 0, stage = NULL, stage_progress = NULL,
+			attempts = 0, next_attempt_at = NULL,
+			failure_class = NULL, error_message = NULL, error_details = NULL,
+			started_at = NULL, completed_at = NULL, dead_lettered_at = NULL
+		FROM (SELECT id, failure_class FROM transcription_jobs WHERE id = $1 FOR UPDATE) old
+		WHERE j.id = old.id AND j.dead_lettered_at IS NOT NULL
+		RETURNING j.user_id, COALESCE(old.failure_class, '')`,
+		jobID, models.TranscriptionPending,
+	).Scan(&ownerID, &failureClass)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dead-lettered job not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to requeue job"})
+		return
+	}
+	transcription.Publish(c.Request.Context(), database.GetDB(), jobID)
+	audit.LogRequest(c, ownerID, audit.EventTranscriptionRequeued,
+		models.JSONB{"job_id": jobID, "failure_class": failureClass})
+
+	c.JSON(http.StatusOK, gin.H{"id": jobID, "status": models.TranscriptionPending})
+}
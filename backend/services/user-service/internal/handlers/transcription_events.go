@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/transcription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// transcriptionKeepalive is how often an idle event stream sends a comment so
+// proxies do not close it
+const transcriptionKeepalive = 15 * time.Second
+
+// StreamTranscriptionEvents streams one of the API key owner's transcription
+// jobs as server-sent events: its current state, then every status, stage or
+// progress change until the job finishes
+func StreamTranscriptionEvents(c *gin.Context) {
+	userID := c.GetString("user_id")
+	jobID := c.Param("id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	// Subscribe before reading the job so no update in between is missed
+	ctx := c.Request.Context()
+	sub := database.GetRedis().Subscribe(ctx, transcription.Channel(jobID))
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow job"})
+		return
+	}
+
+	job, err := transcription.ScanJob(database.GetDB().QueryRow(
+		"SELECT "+transcription.JobColumns+" FROM transcription_jobs WHERE id = $1 AND user_id = $2",
+		jobID, userID,
+	))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	send := func(job models.TranscriptionJob) bool {
+		c.SSEvent("job", job)
+		c.Writer.Flush()
+		return !transcription.Finished(job.Status)
+	}
+	if !send(job) {
+		return
+	}
+
+	keepalive := time.NewTicker(transcriptionKeepalive)
+	defer keepalive.Stop()
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepalive.C:
+			if _, err := c.Writer.WriteString(": keepalive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var update models.TranscriptionJob
+			if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+				continue
+			}
+			if !send(update) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/transcription"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// transcriptionClaimLock serializes claims so two workers cannot both start a
+// job for a user who has one concurrency slot left
+const transcriptionClaimLock = 0x7472616e73 // "trans"
+
+// tierTranscriptionCaps returns the tiers with their concurrency caps, as
+// parallel arrays for unnest
+func tierTranscriptionCaps() ([]string, []int64) {
+	var tiers []string
+	var caps []int64
+	for _, t := range models.AllTierCapabilities() {
+		tiers = append(tiers, t.Tier)
+		caps = append(caps, int64(t.ConcurrentTranscriptions))
+	}
+	return tiers, caps
+}
+
+// transcriptionQueuePosition returns a pending job's place in the queue,
+// counting the pending jobs workers will claim before it. Jobs held back by
+// their owner's concurrency cap still count, so the position is an upper
+// bound.
+func transcriptionQueuePosition(db *sql.DB, jobID string) (int, error) {
+	var position int
+	err := db.QueryRow(`
+		SELECT COUNT(q.id) + 1
+		FROM transcription_jobs j
+		LEFT JOIN transcription_jobs q ON q.status = $2
+			AND (q.priority > j.priority OR (q.priority = j.priority AND (q.created_at, q.id) < (j.created_at, j.id)))
+		WHERE j.id = $1`,
+		jobID, models.TranscriptionPending,
+	).Scan(&position)
+	return position, err
+}
+
+// ClaimTranscriptionJob hands the calling worker the next job to run, or 204
+// when none is ready. Jobs are taken by priority, which comes from the
+// owner's tier, then in submission order, skipping users already running as
+// many jobs as their tier allows and jobs waiting to be retried.
+func ClaimTranscriptionJob(c *gin.Context) {
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", transcriptionClaimLock); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim job"})
+		return
+	}
+
+	tiers, caps := tierTranscriptionCaps()
+	var jobID string
+	err = tx.QueryRow(`
+		SELECT j.id FROM transcription_jobs j
+		JOIN users u ON u.id = j.user_id
+		LEFT JOIN unnest($1::text[], $2::int[]) AS caps(tier, concurrent) ON caps.tier = u.subscription_tier
+		WHERE j.status = $3 AND (j.next_attempt_at IS NULL OR j.next_attempt_at <= NOW())
+		  AND (SELECT COUNT(*) FROM transcription_jobs r WHERE r.user_id = j.user_id AND r.status = $4)
+			  < COALESCE(caps.concurrent, $5)
+		ORDER BY j.priority DESC, j.created_at, j.id
+		LIMIT 1`,
+		pq.Array(tiers), pq.Array(caps), models.TranscriptionPending, models.TranscriptionProcessing,
+		models.GetTierCapabilities(models.TierFree).ConcurrentTranscriptions,
+	).Scan(&jobID)
+	if err == sql.ErrNoRows {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim job"})
+		return
+	}
+
+	var job models.ClaimedTranscriptionJob
+	err = tx.QueryRow(`
+		UPDATE transcription_jobs SET status = $2, progress = 0, stage = NULL, stage_progress = NULL,
+			attempts = attempts + 1, started_at = NOW(), heartbeat_at = NOW(), next_attempt_at = NULL
+		WHERE id = $1
+		RETURNING id, user_id, input_type, input_url, input_filename, input_metadata,
+				  transcription_settings, ai_model, priority, attempts, created_at, started_at`,
+		jobID, models.TranscriptionProcessing,
+	).Scan(&job.ID, &job.UserID, &job.InputType, &job.InputURL, &job.InputFilename, &job.InputMetadata,
+		&job.Settings, &job.AIModel, &job.Priority, &job.Attempt, &job.CreatedAt, &job.StartedAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim job"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim job"})
+		return
+	}
+	transcription.Publish(c.Request.Context(), db, jobID)
+
+	c.JSON(http.StatusOK, job)
+}
+
+// CompleteTranscriptionJob records that a worker finished a job it claimed,
+// freeing the owner's concurrency slot, and notifies the owner
+func CompleteTranscriptionJob(c *gin.Context) {
+	jobID := c.Param("id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	var req models.TranscriptionComplete
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var resultData interface{}
+	if req.ResultData != nil {
+		resultData = req.ResultData
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE transcription_jobs SET status = $2, progress = 100, stage = NULL, stage_progress = NULL,
+			score_id = $3, result_data = $4,
+			failure_class = NULL, error_message = NULL, error_details = NULL, completed_at = NOW(),
+			processing_time_ms = (EXTRACT(EPOCH FROM NOW() - started_at) * 1000)::int
+		WHERE id = $1 AND status = $5 AND attempts = $6`,
+		jobID, models.TranscriptionCompleted, req.ScoreID, resultData, models.TranscriptionProcessing, req.Attempt,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete job"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		notProcessing(c, db, jobID, req.Attempt, "Failed to complete job")
+		return
+	}
+	transcription.Publish(c.Request.Context(), db, jobID)
+	transcription.NotifyFinished(c.Request.Context(), db, jobID)
+
+	c.JSON(http.StatusOK, gin.H{"id": jobID, "status": models.TranscriptionCompleted})
+}
+
+// notProcessing answers a worker update that matched no processing attempt:
+// 404 if the job does not exist, 409 if it is no longer processing or is
+// processing a later attempt
+func notProcessing(c *gin.Context, db *sql.DB, jobID string, attempt int, failure string) {
+	var status string
+	var attempts int
+	err := db.QueryRow("SELECT status, attempts FROM transcription_jobs WHERE id = $1", jobID).Scan(&status, &attempts)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failure})
+		return
+	}
+	if status == models.TranscriptionProcessing && attempts != attempt {
+		c.JSON(http.StatusConflict, gin.H{"error": "Attempt is no longer current"})
+		return
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "Job is not processing"})
+}
+
+// HeartbeatTranscriptionJob tells the queue a worker is still running a job.
+// Jobs without a heartbeat or progress report for transcription.StallTimeout
+// are failed as worker crashes.
+func HeartbeatTranscriptionJob(c *gin.Context) {
+	jobID := c.Param("id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	var req models.TranscriptionHeartbeat
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(
+		"UPDATE transcription_jobs SET heartbeat_at = NOW() WHERE id = $1 AND status = $2 AND attempts = $3",
+		jobID, models.TranscriptionProcessing, req.Attempt,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record heartbeat"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		notProcessing(c, db, jobID, req.Attempt, "Failed to record heartbeat")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// FailTranscriptionJob records that a worker could not finish a job. Transient
+// failures are retried with backoff while attempts remain; the job is
+// dead-lettered otherwise.
+func FailTranscriptionJob(c *gin.Context) {
+	jobID := c.Param("id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	var req models.TranscriptionFailure
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	outcome, err := transcription.Fail(c.Request.Context(), db, jobID, req.Attempt, req.FailureClass, req.ErrorMessage, req.ErrorDetails)
+	if err == sql.ErrNoRows {
+		notProcessing(c, db, jobID, req.Attempt, "Failed to record failure")
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record failure"})
+		return
+	}
+	transcription.Publish(c.Request.Context(), db, jobID)
+
+	c.JSON(http.StatusOK, outcome)
+}
+
+// ReportTranscriptionProgress records the stage a worker has reached on a job
+// and how far through it it is, and pushes the update to clients following
+// the job. Reports also count as heartbeats.
+func ReportTranscriptionProgress(c *gin.Context) {
+	jobID := c.Param("id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	var req models.TranscriptionProgress
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Overall progress only moves forward within an attempt
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE transcription_jobs SET stage = $2, stage_progress = $3, progress = GREATEST(progress, $4),
+			heartbeat_at = NOW()
+		WHERE id = $1 AND status = $5 AND attempts = $6`,
+		jobID, req.Stage, *req.Percent, transcription.OverallProgress(req.Stage, *req.Percent),
+		models.TranscriptionProcessing, req.Attempt,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record progress"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		notProcessing(c, db, jobID, req.Attempt, "Failed to record progress")
+		return
+	}
+	transcription.Publish(c.Request.Context(), db, jobID)
+
+	c.Status(http.StatusNoContent)
+}
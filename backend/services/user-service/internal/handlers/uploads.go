@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// uploadTokenTTL is how long an upload credential stays valid
+const uploadTokenTTL = 15 * time.Minute
+
+func uploadTokenKey(hash string) string {
+	return "upload_token:" + hash
+}
+
+// IssueUploadCredential checks a declared upload against the user's tier and
+// returns a single-use token the browser presents to storage instead of its
+// session token
+func IssueUploadCredential(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.UploadCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !models.IsAllowedUploadContentType(req.ContentType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported content type"})
+		return
+	}
+
+	// Quota checks must see the latest usage
+	db := database.GetDB()
+	var tier string
+	var storageUsed int
+	err := db.QueryRow(`
+		SELECT subscription_tier, storage_used_mb FROM users
+		WHERE id = $1 AND is_active = true`,
+		userID,
+	).Scan(&tier, &storageUsed)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	caps := models.GetTierCapabilities(tier)
+	sizeMB := int((req.SizeBytes + 1<<20 - 1) >> 20)
+	if quotaErr := caps.CheckUpload(models.UploadRequest{SizeMB: sizeMB, DurationSeconds: req.DurationSeconds}, storageUsed); quotaErr != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": quotaErr})
+		return
+	}
+
+	token, hash, err := utils.GenerateOpaqueToken(utils.UploadTokenPrefix)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate upload token"})
+		return
+	}
+
+	grant := models.UploadGrant{
+		UploadID:    uuid.New(),
+		UserID:      uuid.MustParse(userID),
+		Filename:    req.Filename,
+		ContentType: req.ContentType,
+		SizeBytes:   req.SizeBytes,
+		ExpiresAt:   time.Now().Add(uploadTokenTTL),
+	}
+	payload, _ := json.Marshal(grant)
+	if err := database.GetRedis().Set(c.Request.Context(), uploadTokenKey(hash), payload, uploadTokenTTL).Err(); err != nil {
+		log.Printf("Failed to store upload token: %v", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to issue upload token"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.UploadCredential{
+		UploadToken: token,
+		UploadURL:   os.Getenv("MEDIA_UPLOAD_URL"),
+		ContentType: grant.ContentType,
+		SizeBytes:   grant.SizeBytes,
+		ExpiresAt:   grant.ExpiresAt,
+	})
+}
+
+// RedeemUploadCredential consumes an upload token for the storage service. The
+// token is deleted on first use, so a mismatched upload also burns it.
+func RedeemUploadCredential(c *gin.Context) {
+	var req models.UploadRedeemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !strings.HasPrefix(req.UploadToken, utils.UploadTokenPrefix) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired upload token"})
+		return
+	}
+
+	payload, err := database.GetRedis().GetDel(c.Request.Context(), uploadTokenKey(utils.HashAPIKey(req.UploadToken))).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired upload token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to redeem upload token"})
+		return
+	}
+
+	var grant models.UploadGrant
+	if err := json.Unmarshal(payload, &grant); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Corrupt upload token"})
+		return
+	}
+
+	if req.ContentType != grant.ContentType {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Content type does not match the declared upload"})
+		return
+	}
+	if req.SizeBytes > grant.SizeBytes {
+		c.JSON(http.StatusForbidden, gin.H{"error": "File is larger than the declared upload"})
+		return
+	}
+
+	c.JSON(http.StatusOK, grant)
+}
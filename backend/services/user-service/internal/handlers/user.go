@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/audit"
 	"user-service/internal/database"
 	"user-service/internal/models"
 	"user-service/internal/utils"
@@ -15,24 +19,27 @@ import (
 func GetProfile(c *gin.Context) {
 	userID := c.GetString("user_id")
 
-	db := database.GetDB()
+	db := database.GetReadDBFor(userID)
 	var user models.User
 
 	err := db.QueryRow(`
 		SELECT id, email, username, first_name, last_name, avatar_url, bio,
-			   subscription_tier, storage_used_mb, storage_limit_mb, created_at
+			   subscription_tier, storage_used_mb, verified, verified_badge,
+			   skill_level, created_at
 		FROM users WHERE id = $1`,
 		userID,
 	).Scan(
 		&user.ID, &user.Email, &user.Username, &user.FirstName, &user.LastName,
 		&user.AvatarURL, &user.Bio, &user.SubscriptionTier,
-		&user.StorageUsedMB, &user.StorageLimitMB, &user.CreatedAt,
+		&user.StorageUsedMB, &user.Verified, &user.VerifiedBadge,
+		&user.SkillLevel, &user.CreatedAt,
 	)
 
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
+	user.StorageLimitMB = models.GetTierCapabilities(user.SubscriptionTier).StorageLimitMB
 
 	c.JSON(http.StatusOK, user)
 }
@@ -54,15 +61,40 @@ func UpdateProfile(c *gin.Context) {
 	args := []interface{}{}
 	argCount := 1
 
-	if req.Username != nil {
-		// Check if username already exists
-		var exists bool
-		err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE username = $1 AND id != $2)", 
-			*req.Username, userID).Scan(&exists)
+	var oldUsername string
+	if err := db.QueryRow("SELECT username FROM users WHERE id = $1", userID).Scan(&oldUsername); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	usernameChanged := req.Username != nil && *req.Username != oldUsername
+	if usernameChanged {
+		// Check if username already exists or was recently released by someone else
+		exists, err := usernameUnavailable(db, *req.Username, userID)
 		if err != nil || exists {
 			c.JSON(http.StatusConflict, gin.H{"error": "Username already taken"})
 			return
 		}
+
+		reserved, err := usernameReserved(db, *req.Username, userID)
+		if err != nil || reserved {
+			c.JSON(http.StatusConflict, gin.H{"error": "Username is reserved"})
+			return
+		}
+
+		nextChange, err := checkUsernameCooldown(db, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if !nextChange.IsZero() {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":           "Username was changed too recently",
+				"next_change_at": nextChange,
+			})
+			return
+		}
+
 		query += ", username = $" + string(rune('0'+argCount))
 		args = append(args, *req.Username)
 		argCount++
@@ -95,11 +127,36 @@ func UpdateProfile(c *gin.Context) {
 	query += " WHERE id = $" + string(rune('0'+argCount))
 	args = append(args, userID)
 
-	_, err := db.Exec(query, args...)
+	// The old username is recorded with the rename so it stays reserved and the
+	// cooldown applies even if one of the writes fails
+	tx, err := db.Begin()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
 		return
 	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		return
+	}
+	if usernameChanged {
+		_, err = tx.Exec("INSERT INTO username_history (user_id, username) VALUES ($1, $2)", userID, oldUsername)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventProfileUpdated, nil)
+
+	if usernameChanged {
+		audit.LogRequest(c, userID, audit.EventUsernameChanged, models.JSONB{"old_username": oldUsername, "new_username": *req.Username})
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully"})
 }
@@ -110,12 +167,18 @@ func DeleteAccount(c *gin.Context) {
 
 	db := database.GetDB()
 	
-	// Soft delete - just mark as inactive
-	_, err := db.Exec("UPDATE users SET is_active = false WHERE id = $1", userID)
+	// Soft delete - mark as inactive and encrypt the email so it isn't kept in the clear
+	err := deactivateUser(db, userID)
+	if err == errLegalHold {
+		audit.LogRequest(c, userID, audit.EventDeletionBlocked, models.JSONB{"source": "self_service"})
+		c.JSON(http.StatusConflict, gin.H{"error": "This account cannot be deleted right now. Please contact support."})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account"})
 		return
 	}
+	audit.LogRequest(c, userID, audit.EventAccountDeleted, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
 }
@@ -159,6 +222,7 @@ func ChangePassword(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
 		return
 	}
+	audit.LogRequest(c, userID, audit.EventPasswordChanged, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
 }
@@ -167,28 +231,77 @@ func ChangePassword(c *gin.Context) {
 func GetSubscription(c *gin.Context) {
 	userID := c.GetString("user_id")
 
-	db := database.GetDB()
+	db := database.GetReadDBFor(userID)
 	var sub struct {
 		Tier         string         `json:"tier"`
 		ExpiresAt    sql.NullTime  `json:"expires_at"`
 		StorageUsed  int           `json:"storage_used_mb"`
 		StorageLimit int           `json:"storage_limit_mb"`
+		Capabilities models.TierCapabilities `json:"capabilities"`
+		Usage        []models.UsageMeter     `json:"usage"`
 	}
 
 	err := db.QueryRow(`
-		SELECT subscription_tier, subscription_expires_at, storage_used_mb, storage_limit_mb
+		SELECT subscription_tier, subscription_expires_at, storage_used_mb
 		FROM users WHERE id = $1`,
 		userID,
-	).Scan(&sub.Tier, &sub.ExpiresAt, &sub.StorageUsed, &sub.StorageLimit)
+	).Scan(&sub.Tier, &sub.ExpiresAt, &sub.StorageUsed)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get subscription"})
 		return
 	}
+	sub.Capabilities = models.GetTierCapabilities(sub.Tier)
+	sub.StorageLimit = sub.Capabilities.StorageLimitMB
+
+	// Transcription minutes are metered per calendar month
+	var minutesUsed int
+	err = db.QueryRow(`
+		SELECT COALESCE((SUM(s.audio_duration_seconds) + 59) / 60, 0)
+		FROM transcription_jobs j JOIN scores s ON s.id = j.score_id
+		WHERE j.user_id = $1 AND j.status = 'completed' AND j.completed_at >= date_trunc('month', NOW())`,
+		userID,
+	).Scan(&minutesUsed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get subscription"})
+		return
+	}
+	sub.Usage = []models.UsageMeter{
+		models.NewUsageMeter(models.UsageStorage, "mb", sub.StorageUsed, sub.StorageLimit),
+		models.NewUsageMeter(models.UsageTranscriptionMinutes, "minutes", minutesUsed, sub.Capabilities.TranscriptionMinutes),
+	}
 
 	c.JSON(http.StatusOK, sub)
 }
 
+// GetActivity lists the current user's recent security events
+func GetActivity(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	before := time.Now()
+	if b := c.Query("before"); b != "" {
+		before, err = time.Parse(time.RFC3339, b)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be an RFC3339 timestamp"})
+			return
+		}
+	}
+
+	activities, err := audit.ListActivity(c.Request.Context(), userID, limit, before)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"activity": activities})
+}
+
 // UpgradeSubscription upgrades the user's subscription (placeholder)
 func UpgradeSubscription(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Subscription upgrade not implemented yet"})
@@ -196,10 +309,10 @@ func UpgradeSubscription(c *gin.Context) {
 
 // Admin handlers
 func ListUsers(c *gin.Context) {
-	db := database.GetDB()
+	db := database.GetReadDB()
 	
 	rows, err := db.Query(`
-		SELECT id, email, username, subscription_tier, created_at, is_active
+		SELECT id, email, username, subscription_tier, created_at, is_active, verified, verified_badge
 		FROM users
 		ORDER BY created_at DESC
 		LIMIT 100
@@ -214,7 +327,7 @@ func ListUsers(c *gin.Context) {
 	for rows.Next() {
 		var user models.User
 		err := rows.Scan(&user.ID, &user.Email, &user.Username, 
-			&user.SubscriptionTier, &user.CreatedAt, &user.IsActive)
+			&user.SubscriptionTier, &user.CreatedAt, &user.IsActive, &user.Verified, &user.VerifiedBadge)
 		if err != nil {
 			continue
 		}
@@ -234,17 +347,18 @@ func GetUserByID(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
+	db := database.GetReadDB()
 	var user models.User
 
+	var originalEmail models.EncryptedString
 	err = db.QueryRow(`
 		SELECT id, email, username, first_name, last_name, 
-			   subscription_tier, created_at, is_active
+			   subscription_tier, created_at, is_active, email_encrypted
 		FROM users WHERE id = $1`,
 		userID,
 	).Scan(
 		&user.ID, &user.Email, &user.Username, &user.FirstName, &user.LastName,
-		&user.SubscriptionTier, &user.CreatedAt, &user.IsActive,
+		&user.SubscriptionTier, &user.CreatedAt, &user.IsActive, &originalEmail,
 	)
 
 	if err != nil {
@@ -252,6 +366,11 @@ func GetUserByID(c *gin.Context) {
 		return
 	}
 
+	// Show admins the original email of deactivated accounts
+	if originalEmail != "" {
+		user.Email = string(originalEmail)
+	}
+
 	c.JSON(http.StatusOK, user)
 }
 
@@ -270,17 +389,23 @@ func DeleteUserByID(c *gin.Context) {
 	}
 
 	db := database.GetDB()
-	_, err = db.Exec("UPDATE users SET is_active = false WHERE id = $1", userID)
+	err = deactivateUser(db, userID)
+	if err == errLegalHold {
+		audit.LogRequest(c, userID, audit.EventDeletionBlocked, models.JSONB{"source": "admin"})
+		c.JSON(http.StatusConflict, gin.H{"error": "User is under legal hold"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
 		return
 	}
+	audit.LogRequest(c, userID, audit.EventAccountDeleted, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
 
 func GetSystemStats(c *gin.Context) {
-	db := database.GetDB()
+	db := database.GetReadDB()
 	
 	var stats struct {
 		TotalUsers       int `json:"total_users"`
@@ -298,4 +423,38 @@ func GetSystemStats(c *gin.Context) {
 	db.QueryRow("SELECT COALESCE(SUM(storage_used_mb), 0) FROM users").Scan(&stats.TotalStorage)
 
 	c.JSON(http.StatusOK, stats)
-}
\ No newline at end of file
+}
+
+// errLegalHold is returned when deleting or anonymizing an account under legal hold
+var errLegalHold = errors.New("account is under legal hold")
+
+// deactivateUser soft-deletes a user, moving their email into the encrypted
+// column and freeing the plaintext one. Accounts under legal hold are refused.
+func deactivateUser(db *sql.DB, userID string) error {
+	var email string
+	var held bool
+	err := db.QueryRow("SELECT email, legal_hold_at IS NOT NULL FROM users WHERE id = $1", userID).Scan(&email, &held)
+	if err != nil {
+		return err
+	}
+	if held {
+		return errLegalHold
+	}
+
+	result, err := db.Exec(`
+		UPDATE users
+		SET is_active = false,
+			email_encrypted = COALESCE(email_encrypted, $1),
+			email = 'deactivated-' || id || '@deactivated.invalid'
+		WHERE id = $2 AND legal_hold_at IS NULL`,
+		models.EncryptedString(email), userID,
+	)
+	if err != nil {
+		return err
+	}
+	// A hold placed since the check above still wins
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errLegalHold
+	}
+	return nil
+}
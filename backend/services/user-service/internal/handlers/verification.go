@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"user-service/internal/audit"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const verificationRequestColumns = `
+	v.id, v.user_id, u.username, v.badge_type, v.evidence_urls, v.note,
+	v.status, v.review_note, v.created_at, v.reviewed_at`
+
+func scanVerificationRequest(row rowScanner) (models.VerificationRequest, error) {
+	var v models.VerificationRequest
+	err := row.Scan(&v.ID, &v.UserID, &v.Username, &v.BadgeType, pq.Array(&v.EvidenceURLs), &v.Note,
+		&v.Status, &v.ReviewNote, &v.CreatedAt, &v.ReviewedAt)
+	return v, err
+}
+
+// SubmitVerificationRequest files a request for a verified artist or instructor badge
+func SubmitVerificationRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req models.VerificationSubmit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var note interface{}
+	if req.Note != "" {
+		note = req.Note
+	}
+
+	db := database.GetDB()
+	var id uuid.UUID
+	err := db.QueryRow(`
+		INSERT INTO verification_requests (user_id, badge_type, evidence_urls, note)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) WHERE status = 'pending' DO NOTHING
+		RETURNING id`,
+		userID, req.BadgeType, pq.Array(req.EvidenceURLs), note,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "A verification request is already pending"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit verification request"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventVerificationRequested, models.JSONB{"request_id": id, "badge_type": req.BadgeType})
+
+	v, err := scanVerificationRequest(db.QueryRow(
+		"SELECT "+verificationRequestColumns+" FROM verification_requests v JOIN users u ON u.id = v.user_id WHERE v.id = $1", id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get verification request"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v)
+}
+
+// GetVerificationStatus returns the current user's badge and latest verification request
+func GetVerificationStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	db := database.GetReadDBFor(userID)
+	var status struct {
+		Verified      bool                        `json:"verified"`
+		VerifiedBadge *string                     `json:"verified_badge,omitempty"`
+		LatestRequest *models.VerificationRequest `json:"latest_request,omitempty"`
+	}
+	err := db.QueryRow("SELECT verified, verified_badge FROM users WHERE id = $1", userID).
+		Scan(&status.Verified, &status.VerifiedBadge)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	v, err := scanVerificationRequest(db.QueryRow(`
+		SELECT `+verificationRequestColumns+`
+		FROM verification_requests v JOIN users u ON u.id = v.user_id
+		WHERE v.user_id = $1
+		ORDER BY v.created_at DESC LIMIT 1`,
+		userID,
+	))
+	if err == nil {
+		status.LatestRequest = &v
+	} else if err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get verification status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// ListVerificationRequests lists verification requests for review, oldest first (admin)
+func ListVerificationRequests(c *gin.Context) {
+	status := c.DefaultQuery("status", models.VerificationPending)
+
+	db := database.GetReadDB()
+	rows, err := db.Query(`
+		SELECT `+verificationRequestColumns+`
+		FROM verification_requests v JOIN users u ON u.id = v.user_id
+		WHERE v.status = $1
+		ORDER BY v.created_at
+		LIMIT 100`,
+		status,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get verification requests"})
+		return
+	}
+	defer rows.Close()
+
+	requests := []models.VerificationRequest{}
+	for rows.Next() {
+		v, err := scanVerificationRequest(rows)
+		if err != nil {
+			continue
+		}
+		requests = append(requests, v)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"verification_requests": requests})
+}
+
+// ReviewVerificationRequest approves or rejects a pending request (admin).
+// Approval sets the verified flag and badge on the account.
+func ReviewVerificationRequest(c *gin.Context) {
+	requestID := c.Param("id")
+	if _, err := uuid.Parse(requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	var req models.VerificationReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := models.VerificationRejected
+	if req.Decision == "approve" {
+		status = models.VerificationApproved
+	}
+	var note interface{}
+	if req.Note != "" {
+		note = req.Note
+	}
+
+	db := database.GetDB()
+	tx, err := db.Begin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	defer tx.Rollback()
+
+	var userID, badgeType string
+	err = tx.QueryRow(`
+		UPDATE verification_requests
+		SET status = $1, review_note = $2, reviewer_id = $3, reviewed_at = NOW()
+		WHERE id = $4 AND status = 'pending'
+		RETURNING user_id, badge_type`,
+		status, note, c.GetString("user_id"), requestID,
+	).Scan(&userID, &badgeType)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending verification request not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review verification request"})
+		return
+	}
+
+	if status == models.VerificationApproved {
+		_, err = tx.Exec(
+			"UPDATE users SET verified = true, verified_badge = $1, updated_at = NOW() WHERE id = $2",
+			badgeType, userID,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review verification request"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review verification request"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventVerificationReviewed, models.JSONB{
+		"request_id": requestID,
+		"badge_type": badgeType,
+		"status":     status,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification request " + status, "status": status})
+}
+
+// RevokeVerification removes a user's verified badge (admin)
+func RevokeVerification(c *gin.Context) {
+	userID := c.Param("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	db := database.GetDB()
+	result, err := db.Exec(`
+		UPDATE users SET verified = false, verified_badge = NULL, updated_at = NOW()
+		WHERE id = $1 AND verified = true`,
+		userID,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke verification"})
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Verified user not found"})
+		return
+	}
+	database.MarkWrite(userID)
+	audit.LogRequest(c, userID, audit.EventVerificationReviewed, models.JSONB{"status": "revoked"})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification revoked"})
+}
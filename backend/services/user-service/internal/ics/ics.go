@@ -0,0 +1,87 @@
+package ics
+
+import (
+	"strings"
+	"time"
+)
+
+// Event is a calendar event. Recurring events set RRule and may exclude
+// individual occurrences with ExDates.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	Duration    time.Duration
+	RRule       string
+	ExDates     []time.Time
+}
+
+// Calendar renders events as an RFC 5545 VCALENDAR
+func Calendar(name string, events []Event) string {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//Genesis Music//Calendar//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escape(name))
+	writeLine(&b, "X-PUBLISHED-TTL:PT1H")
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	for _, e := range events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+e.UID)
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, "DTSTART"+dateTime(e.Start))
+		writeLine(&b, "DTEND"+dateTime(e.Start.Add(e.Duration)))
+		if e.RRule != "" {
+			writeLine(&b, "RRULE:"+e.RRule)
+		}
+		for _, ex := range e.ExDates {
+			writeLine(&b, "EXDATE"+dateTime(ex))
+		}
+		writeLine(&b, "SUMMARY:"+escape(e.Summary))
+		if e.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escape(e.Description))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// dateTime formats a property value in the time's own zone, so recurrences
+// keep their local wall time across daylight saving changes
+func dateTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return ":" + t.Format("20060102T150405Z")
+	}
+	return ";TZID=" + t.Location().String() + ":" + t.Format("20060102T150405")
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeLine writes a content line folded at 75 octets
+func writeLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8Start(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func utf8Start(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+	"user-service/internal/database"
+)
+
+func init() {
+	Register(Job{
+		Name:     "aggregate-analytics",
+		Interval: time.Hour,
+		Run:      aggregateAnalytics,
+	})
+}
+
+// analyticsRetention returns how long raw analytics events are kept
+func analyticsRetention() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("ANALYTICS_RETENTION")); err == nil && d > 0 {
+		return d
+	}
+	return 90 * 24 * time.Hour
+}
+
+// aggregateAnalytics recomputes the daily metrics for yesterday and today (UTC)
+// from raw events, then deletes raw events past the retention period. Only the
+// aggregates are read by dashboards.
+func aggregateAnalytics(ctx context.Context) error {
+	db := database.GetDB()
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+
+	_, err := db.ExecContext(ctx, `
+		WITH e AS (
+			SELECT (occurred_at AT TIME ZONE 'UTC')::date AS day, name, subject, properties
+			FROM analytics_events WHERE occurred_at >= $1
+		)
+		INSERT INTO analytics_daily_metrics (day, metric, dimension, value)
+		SELECT day, 'active_users', '', COUNT(DISTINCT subject) FROM e GROUP BY day
+		UNION ALL
+		SELECT day, 'events', name, COUNT(*) FROM e GROUP BY day, name
+		UNION ALL
+		SELECT day, 'event_users', name, COUNT(DISTINCT subject) FROM e GROUP BY day, name
+		UNION ALL
+		-- Keys are at most 100 characters and variant names 50, within the 160-character dimension
+		SELECT day, 'experiment_exposures', (properties->>'experiment') || ':' || (properties->>'variant'),
+			COUNT(DISTINCT subject)
+		FROM e WHERE name = 'experiment_exposure' AND properties ? 'experiment' AND properties ? 'variant'
+		GROUP BY day, properties->>'experiment', properties->>'variant'
+		ON CONFLICT (day, metric, dimension) DO UPDATE SET value = EXCLUDED.value`,
+		since,
+	)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx,
+		"DELETE FROM analytics_events WHERE occurred_at < $1", time.Now().Add(-analyticsRetention()))
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		log.Printf("Deleted %d analytics events past retention", n)
+	}
+	return nil
+}
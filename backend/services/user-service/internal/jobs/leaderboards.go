@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"context"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/leaderboard"
+)
+
+func init() {
+	Register(Job{
+		Name:     "compute-leaderboards",
+		Interval: 10 * time.Minute,
+		Run:      computeLeaderboards,
+	})
+}
+
+// computeLeaderboards recomputes and caches the current week's and month's
+// rankings. Opted-out and inactive users are never ranked.
+func computeLeaderboards(ctx context.Context) error {
+	now := time.Now()
+	for _, period := range leaderboard.Periods {
+		start := leaderboard.PeriodStart(period, now)
+		if err := openLeaderboardPeriod(ctx, period, start); err != nil {
+			return err
+		}
+
+		minutes, err := queryScores(ctx, `
+			SELECT lp.user_id, SUM(lp.total_practice_time_minutes) - COALESCE(MAX(b.practice_minutes), 0)
+			FROM learning_progress lp
+			JOIN users u ON u.id = lp.user_id
+			LEFT JOIN leaderboard_baselines b
+				ON b.user_id = lp.user_id AND b.period = $1 AND b.period_start = $2
+			WHERE u.is_active = true AND u.leaderboard_opt_out = false AND u.shadow_banned_at IS NULL
+			GROUP BY lp.user_id
+			HAVING SUM(lp.total_practice_time_minutes) - COALESCE(MAX(b.practice_minutes), 0) > 0`,
+			period, start,
+		)
+		if err != nil {
+			return err
+		}
+		if err := leaderboard.Store(ctx, period, leaderboard.MetricPracticeMinutes, start, minutes); err != nil {
+			return err
+		}
+
+		completed, err := queryScores(ctx, `
+			SELECT lp.user_id, COUNT(*)
+			FROM learning_progress lp JOIN users u ON u.id = lp.user_id
+			WHERE lp.completed_at >= $1 AND lp.completed_at < $2
+			  AND u.is_active = true AND u.leaderboard_opt_out = false AND u.shadow_banned_at IS NULL
+			GROUP BY lp.user_id`,
+			start, leaderboard.PeriodEnd(period, start),
+		)
+		if err != nil {
+			return err
+		}
+		if err := leaderboard.Store(ctx, period, leaderboard.MetricSongsCompleted, start, completed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openLeaderboardPeriod snapshots everyone's practice totals the first time a
+// period is seen, so later runs can rank minutes practiced within the period
+func openLeaderboardPeriod(ctx context.Context, period string, start time.Time) error {
+	tx, err := database.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO leaderboard_periods (period, period_start) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		period, start,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO leaderboard_baselines (period, period_start, user_id, practice_minutes)
+		SELECT $1, $2, user_id, SUM(total_practice_time_minutes)
+		FROM learning_progress WHERE user_id IS NOT NULL
+		GROUP BY user_id`,
+		period, start,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Baselines are only needed for the current and previous period
+	_, err = tx.ExecContext(ctx,
+		"DELETE FROM leaderboard_periods WHERE period = $1 AND period_start < $2",
+		period, start.AddDate(0, -2, 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func queryScores(ctx context.Context, query string, args ...interface{}) (map[string]int, error) {
+	rows, err := database.GetDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := map[string]int{}
+	for rows.Next() {
+		var userID string
+		var score int
+		if err := rows.Scan(&userID, &score); err != nil {
+			return nil, err
+		}
+		scores[userID] = score
+	}
+	return scores, rows.Err()
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-service/internal/database"
+)
+
+func init() {
+	Register(Job{
+		Name:     "purge-oauth-grants",
+		Interval: time.Hour,
+		Run:      purgeOAuthGrants,
+	})
+}
+
+// purgeOAuthGrants deletes spent authorization codes and dead OAuth tokens
+func purgeOAuthGrants(ctx context.Context) error {
+	db := database.GetDB()
+	cutoff := time.Now().Add(-24 * time.Hour)
+
+	codes, err := db.ExecContext(ctx,
+		"DELETE FROM oauth_authorization_codes WHERE expires_at < $1", cutoff)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := db.ExecContext(ctx, `
+		DELETE FROM oauth_tokens
+		WHERE refresh_expires_at < $1 OR revoked_at < $1`,
+		cutoff,
+	)
+	if err != nil {
+		return err
+	}
+
+	nCodes, _ := codes.RowsAffected()
+	nTokens, _ := tokens.RowsAffected()
+	if nCodes+nTokens > 0 {
+		log.Printf("Purged %d authorization codes and %d OAuth tokens", nCodes, nTokens)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/encryption"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	Register(Job{
+		Name:     "rotate-pii-keys",
+		Interval: 6 * time.Hour,
+		Run:      rotatePIIKeys,
+	})
+}
+
+// encryptedColumns lists every column holding encryption envelopes
+var encryptedColumns = []struct {
+	table  string
+	column string
+}{
+	{"refresh_tokens", "ip_address"},
+	{"users", "email_encrypted"},
+	{"audit_log", "ip_address"},
+	{"users", "job_webhook_secret"},
+	{"email_history", "email_encrypted"},
+}
+
+// rotatePIIKeys rewraps values encrypted under a retired key (and encrypts any
+// legacy plaintext) so old keys can be removed from the keyfile
+func rotatePIIKeys(ctx context.Context) error {
+	activeKeyID, err := encryption.ActiveKeyID()
+	if err != nil {
+		return err
+	}
+
+	for _, col := range encryptedColumns {
+		n, err := rotateColumn(ctx, col.table, col.column, activeKeyID)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			log.Printf("Rewrapped %d values in %s.%s", n, col.table, col.column)
+		}
+	}
+	return nil
+}
+
+// rotateColumn rewraps one column in batches. Values that fail to rewrap
+// (e.g. corrupt or under a key no longer loaded) are logged and skipped for
+// the rest of the run so they don't block the remaining rows.
+func rotateColumn(ctx context.Context, table, column, activeKeyID string) (int, error) {
+	db := database.GetDB()
+	total := 0
+	skipped := []string{}
+
+	for {
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, `+column+` FROM `+table+`
+			WHERE `+column+` IS NOT NULL AND `+column+` NOT LIKE $1
+			  AND NOT (id::text = ANY($2))
+			LIMIT 500`,
+			"enc:v1:"+activeKeyID+":%", pq.Array(skipped),
+		)
+		if err != nil {
+			return total, err
+		}
+
+		type pending struct {
+			id    string
+			value string
+		}
+		var batch []pending
+		for rows.Next() {
+			var p pending
+			if err := rows.Scan(&p.id, &p.value); err != nil {
+				rows.Close()
+				return total, err
+			}
+			batch = append(batch, p)
+		}
+		rows.Close()
+
+		if len(batch) == 0 {
+			if len(skipped) > 0 {
+				log.Printf("Skipped %d values in %s.%s that could not be rewrapped", len(skipped), table, column)
+			}
+			return total, nil
+		}
+
+		for _, p := range batch {
+			rewrapped, changed, err := encryption.Rewrap(p.value)
+			if err != nil {
+				log.Printf("Skipping %s.%s row %s: %v", table, column, p.id, err)
+				skipped = append(skipped, p.id)
+				continue
+			}
+			if !changed {
+				skipped = append(skipped, p.id)
+				continue
+			}
+			if _, err := db.ExecContext(ctx,
+				`UPDATE `+table+` SET `+column+` = $1 WHERE id = $2 AND `+column+` = $3`,
+				rewrapped, p.id, p.value,
+			); err != nil {
+				return total, err
+			}
+			total++
+		}
+
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
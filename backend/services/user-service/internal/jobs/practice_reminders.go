@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	Register(Job{
+		Name:     "send-practice-reminders",
+		Interval: time.Minute,
+		Run:      sendPracticeReminders,
+	})
+}
+
+// reminderMaxDelay is how late a reminder may still be sent; older occurrences
+// (e.g. after an outage) are skipped rather than delivered out of context
+const reminderMaxDelay = 30 * time.Minute
+
+// sendPracticeReminders queues due reminders for notification-service and
+// schedules each reminder's next occurrence
+func sendPracticeReminders(ctx context.Context) error {
+	db := database.GetDB()
+	now := time.Now()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT r.id, r.user_id, r.label, r.days_of_week, r.time_of_day, r.timezone,
+			   r.duration_minutes, r.channels, r.next_fire_at, r.skip_until
+		FROM practice_reminders r JOIN users u ON u.id = r.user_id
+		WHERE r.enabled = true AND r.next_fire_at <= $1 AND u.is_active = true
+		ORDER BY r.next_fire_at
+		LIMIT 500`,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+
+	type due struct {
+		id, userID, label, timeOfDay, timezone string
+		days                                   []int64
+		duration                               int
+		channels                               []string
+		fireAt                                 time.Time
+		skipUntil                              *time.Time
+	}
+	var batch []due
+	for rows.Next() {
+		var d due
+		if err := rows.Scan(&d.id, &d.userID, &d.label, pq.Array(&d.days), &d.timeOfDay, &d.timezone,
+			&d.duration, pq.Array(&d.channels), &d.fireAt, &d.skipUntil); err != nil {
+			rows.Close()
+			return err
+		}
+		batch = append(batch, d)
+	}
+	rows.Close()
+
+	sent := 0
+	for _, d := range batch {
+		schedule, err := models.ParseReminderSchedule(d.days, d.timeOfDay, d.timezone)
+		if err != nil {
+			log.Printf("Disabling reminder %s with invalid schedule: %v", d.id, err)
+			_, _ = db.ExecContext(ctx, "UPDATE practice_reminders SET enabled = false, next_fire_at = NULL WHERE id = $1", d.id)
+			continue
+		}
+
+		var lastSent interface{}
+		if now.Sub(d.fireAt) <= reminderMaxDelay {
+			err := notify.Enqueue(ctx, notify.Notification{
+				Type:     notify.TypePracticeReminder,
+				UserID:   d.userID,
+				Channels: d.channels,
+				Data: map[string]interface{}{
+					"reminder_id":      d.id,
+					"label":            d.label,
+					"duration_minutes": d.duration,
+					"scheduled_for":    d.fireAt,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			lastSent = now
+			sent++
+		}
+
+		_, err = db.ExecContext(ctx, `
+			UPDATE practice_reminders
+			SET next_fire_at = $1, snoozed_until = NULL, last_sent_at = COALESCE($2, last_sent_at)
+			WHERE id = $3`,
+			schedule.NextOutsideSkip(now, d.skipUntil), lastSent, d.id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("Queued %d practice reminders", sent)
+	}
+	return nil
+}
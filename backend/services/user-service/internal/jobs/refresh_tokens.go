@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-service/internal/database"
+)
+
+func init() {
+	Register(Job{
+		Name:     "purge-refresh-tokens",
+		Interval: time.Hour,
+		Run:      purgeRefreshTokens,
+	})
+}
+
+// purgeRefreshTokens deletes refresh tokens that expired or were revoked over a day ago
+func purgeRefreshTokens(ctx context.Context) error {
+	db := database.GetDB()
+
+	result, err := db.ExecContext(ctx, `
+		DELETE FROM refresh_tokens
+		WHERE expires_at < $1 OR (is_revoked = true AND revoked_at < $1)`,
+		time.Now().Add(-24*time.Hour),
+	)
+	if err != nil {
+		return err
+	}
+
+	if n, _ := result.RowsAffected(); n > 0 {
+		log.Printf("Purged %d refresh tokens", n)
+	}
+	return nil
+}
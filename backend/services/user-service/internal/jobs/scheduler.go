@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"user-service/internal/lock"
+)
+
+// Job is a periodic task that must run on exactly one instance at a time
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+var (
+	registry []Job
+	wg       sync.WaitGroup
+)
+
+// Register adds a job to the scheduler. Jobs must be registered before Start.
+func Register(job Job) {
+	registry = append(registry, job)
+}
+
+// Start launches every registered job. Each tick, instances race for the job's
+// lock and only the winner runs it; the job's context is cancelled if the lock
+// is lost mid-run. The winner keeps the lock for the rest of the interval so
+// instances whose tickers fire later skip the job until the next period.
+func Start(ctx context.Context) {
+	for _, job := range registry {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			schedule(ctx, job)
+		}(job)
+	}
+}
+
+// Wait blocks until all job loops have exited after the Start context is cancelled
+func Wait() {
+	wg.Wait()
+}
+
+func schedule(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runOnce(ctx, job)
+		}
+	}
+}
+
+func runOnce(ctx context.Context, job Job) {
+	// The TTL only matters if this instance dies mid-run; renewal keeps it alive otherwise
+	mutex := lock.NewMutex("job:"+job.Name, 30*time.Second)
+	if err := mutex.TryLock(ctx); err != nil {
+		if !errors.Is(err, lock.ErrNotAcquired) {
+			log.Printf("Job %s: failed to acquire lock: %v", job.Name, err)
+		}
+		return
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-mutex.Lost():
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(runCtx); err != nil {
+		log.Printf("Job %s failed after %s: %v", job.Name, time.Since(start), err)
+	}
+	cancel()
+
+	// Hold the lock until the interval has passed since this run started;
+	// releasing it now would let every other instance run the job on its own tick
+	if err := mutex.ExpireAfter(context.Background(), job.Interval-time.Since(start)); err != nil {
+		log.Printf("Job %s: failed to hold lock for the interval: %v", job.Name, err)
+	}
+}
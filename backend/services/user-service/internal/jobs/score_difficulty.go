@@ -0,0 +1,137 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/difficulty"
+	"user-service/internal/fingering"
+	"user-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// difficultyBatchSize is how many scores are estimated per query
+const difficultyBatchSize = 200
+
+func init() {
+	Register(Job{
+		Name:     "estimate-score-difficulty",
+		Interval: 5 * time.Minute,
+		Run:      estimateScoreDifficulty,
+	})
+}
+
+// staleScore is a score created or changed since its last difficulty estimate
+type staleScore struct {
+	id         string
+	updatedAt  *time.Time
+	tempo      int
+	capo       int
+	tuning     string
+	techniques []string
+	notes      []byte
+	fingerings []byte
+}
+
+// estimateScoreDifficulty estimates the difficulty of scores created or
+// edited since their last estimate. Stretch and position come from the saved
+// fingerings, with the rest suggested. A score edited while it is estimated
+// keeps its newer edit and is estimated again on the next run.
+func estimateScoreDifficulty(ctx context.Context) error {
+	db := database.GetDB()
+	estimated := 0
+	for {
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, updated_at, COALESCE(tempo, 0), COALESCE(capo_position, 0), COALESCE(tuning, 'standard'),
+				   techniques, COALESCE(transcription_data->'notes', '[]'), fingerings
+			FROM scores
+			WHERE (difficulty_estimated_at IS NULL OR difficulty_estimated_at < updated_at) AND deleted_at IS NULL
+			ORDER BY updated_at
+			LIMIT $1`,
+			difficultyBatchSize,
+		)
+		if err != nil {
+			return err
+		}
+		var batch []staleScore
+		for rows.Next() {
+			var s staleScore
+			if err := rows.Scan(&s.id, &s.updatedAt, &s.tempo, &s.capo, &s.tuning,
+				pq.Array(&s.techniques), &s.notes, &s.fingerings); err != nil {
+				rows.Close()
+				return err
+			}
+			batch = append(batch, s)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, s := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// Unreadable notes are stamped without an estimate so the score is
+			// not retried until it is edited
+			var level *int
+			var factors []byte
+			if f, ok := measureScore(s); ok {
+				estimate := difficulty.Estimate(f)
+				level = &estimate
+				factors, _ = json.Marshal(f)
+			} else {
+				log.Printf("Difficulty estimation skipped score %s: unreadable notes or fingerings", s.id)
+			}
+
+			result, err := db.ExecContext(ctx, `
+				UPDATE scores SET difficulty_estimated = $1, difficulty_factors = $2,
+					difficulty_estimated_at = CURRENT_TIMESTAMP
+				WHERE id = $3 AND updated_at IS NOT DISTINCT FROM $4`,
+				level, factors, s.id, s.updatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				estimated++
+			}
+		}
+		if len(batch) < difficultyBatchSize {
+			break
+		}
+	}
+
+	if estimated > 0 {
+		log.Printf("Estimated difficulty of %d scores", estimated)
+	}
+	return nil
+}
+
+// measureScore computes a score's difficulty factors
+func measureScore(s staleScore) (difficulty.Factors, bool) {
+	notes, err := fingering.ParseNotes(s.notes)
+	if err != nil {
+		return difficulty.Factors{}, false
+	}
+	var saved []models.Fingering
+	if err := json.Unmarshal(s.fingerings, &saved); err != nil {
+		return difficulty.Factors{}, false
+	}
+
+	// Scores in a tuning the suggester does not know are measured without
+	// stretch or position
+	positions := make([]*fingering.Position, len(notes))
+	if tuning, err := fingering.ParseTuning(s.tuning); err == nil {
+		fixed := make(map[int]fingering.Position, len(saved))
+		for _, f := range saved {
+			fixed[f.Note] = fingering.Position{String: f.String, Fret: f.Fret}
+		}
+		positions = fingering.Suggest(notes, tuning, s.capo, fixed)
+	}
+	return difficulty.Measure(notes, positions, s.capo, s.tempo, s.techniques), true
+}
@@ -0,0 +1,245 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"user-service/internal/abc"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// exportLinkTTL is how long a completed export can be downloaded
+	exportLinkTTL = 24 * time.Hour
+	// exportBatchSize is how many exports are built per run
+	exportBatchSize = 5
+	// maxExportBytes caps the size of an export archive
+	maxExportBytes = 200 << 20
+)
+
+var exportNameUnsafe = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// errExportTooLarge fails exports whose archive exceeds maxExportBytes
+var errExportTooLarge = fmt.Errorf("export is larger than %d MB; select fewer scores", maxExportBytes>>20)
+
+func init() {
+	Register(Job{
+		Name:     "build-score-exports",
+		Interval: time.Minute,
+		Run:      buildScoreExports,
+	})
+}
+
+// pendingExport is a requested export waiting to be built
+type pendingExport struct {
+	id, userID string
+	scoreIDs   []string
+	formats    []string
+}
+
+// buildScoreExports builds pending score exports into ZIP archives and
+// clears the archives of expired ones
+func buildScoreExports(ctx context.Context) error {
+	db := database.GetDB()
+
+	if _, err := db.ExecContext(ctx, `
+		UPDATE score_exports SET status = $1, archive = NULL
+		WHERE status = $2 AND expires_at <= NOW()`,
+		models.ExportExpired, models.ExportCompleted,
+	); err != nil {
+		return err
+	}
+	// The job lock means no other run is building; anything still processing
+	// was interrupted and starts over
+	if _, err := db.ExecContext(ctx, "UPDATE score_exports SET status = $1 WHERE status = $2",
+		models.ExportPending, models.ExportProcessing); err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, user_id, score_ids, formats FROM score_exports
+		WHERE status = $1
+		ORDER BY created_at
+		LIMIT $2`,
+		models.ExportPending, exportBatchSize,
+	)
+	if err != nil {
+		return err
+	}
+	var pending []pendingExport
+	for rows.Next() {
+		var e pendingExport
+		if err := rows.Scan(&e.id, &e.userID, pq.Array(&e.scoreIDs), pq.Array(&e.formats)); err != nil {
+			rows.Close()
+			return err
+		}
+		pending = append(pending, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, e := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := db.ExecContext(ctx, "UPDATE score_exports SET status = $1 WHERE id = $2", models.ExportProcessing, e.id); err != nil {
+			return err
+		}
+
+		archive, skipped, buildErr := buildExportArchive(ctx, db, e)
+		if buildErr != nil {
+			log.Printf("Score export %s failed: %v", e.id, buildErr)
+			message := "Failed to build export"
+			if errors.Is(buildErr, errExportTooLarge) {
+				message = buildErr.Error()
+			}
+			if _, err := db.ExecContext(ctx, `
+				UPDATE score_exports SET status = $1, error_message = $2, completed_at = NOW() WHERE id = $3`,
+				models.ExportFailed, message, e.id,
+			); err != nil {
+				return err
+			}
+			notifyExportFinished(ctx, db, e, models.ExportFailed)
+			continue
+		}
+
+		skippedJSON, err := json.Marshal(skipped)
+		if err != nil {
+			return err
+		}
+		if _, err := db.ExecContext(ctx, `
+			UPDATE score_exports SET status = $1, archive = $2, size_bytes = $3, skipped = $4,
+				completed_at = NOW(), expires_at = NOW() + $5 * INTERVAL '1 second'
+			WHERE id = $6`,
+			models.ExportCompleted, archive, len(archive), skippedJSON, int(exportLinkTTL.Seconds()), e.id,
+		); err != nil {
+			return err
+		}
+		notifyExportFinished(ctx, db, e, models.ExportCompleted)
+	}
+	return nil
+}
+
+// notifyExportFinished tells the owner of an export that it was built or
+// failed, linking to it in the web app
+func notifyExportFinished(ctx context.Context, db *sql.DB, e pendingExport, status string) {
+	err := notify.EnqueueJobFinished(ctx, db, notify.Notification{
+		Type:   notify.TypeScoreExportCompleted,
+		UserID: e.userID,
+		Data: map[string]interface{}{
+			"export_id": e.id,
+			"status":    status,
+			"url":       notify.Link("/exports/" + e.id),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to enqueue export completion for %s: %v", e.id, err)
+	}
+}
+
+// buildExportArchive zips the requested formats of an export's scores. Each
+// score and format with nothing stored, and scores deleted since the export
+// was requested, are reported as skipped.
+func buildExportArchive(ctx context.Context, db *sql.DB, e pendingExport) ([]byte, []models.ExportSkip, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, title, COALESCE(musicxml_data, ''), midi_data, COALESCE(abc_notation, ''),
+			   COALESCE(artist, ''), COALESCE(key_signature, ''), COALESCE(time_signature, ''),
+			   COALESCE(tempo, 0), COALESCE(transcription_data->'notes', '[]')
+		FROM scores WHERE id = ANY($1::uuid[]) AND user_id = $2 AND deleted_at IS NULL
+		ORDER BY LOWER(title), id`,
+		pq.Array(e.scoreIDs), e.userID,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	skipped := []models.ExportSkip{}
+	found := map[uuid.UUID]bool{}
+	for rows.Next() {
+		var id uuid.UUID
+		var title, musicXML, notation string
+		var midi, notes []byte
+		tune := abc.Tune{}
+		if err := rows.Scan(&id, &title, &musicXML, &midi, &notation,
+			&tune.Composer, &tune.Key, &tune.Meter, &tune.Tempo, &notes); err != nil {
+			return nil, nil, err
+		}
+		found[id] = true
+
+		base := exportFileName(title, id)
+		for _, format := range e.formats {
+			var name string
+			var data []byte
+			switch format {
+			case models.ExportMusicXML:
+				name, data = base+".musicxml", []byte(musicXML)
+			case models.ExportMIDI:
+				name, data = base+".mid", midi
+			case models.ExportABC:
+				tune.Title = title
+				tune.Notes, _ = abc.ParseNotes(notes)
+				name, data = base+".abc", []byte(abc.ScoreNotation(notation, &tune))
+			}
+			if len(data) == 0 {
+				skipped = append(skipped, models.ExportSkip{ScoreID: id, Format: format})
+				continue
+			}
+			w, err := archive.Create(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, err := w.Write(data); err != nil {
+				return nil, nil, err
+			}
+			if buf.Len() > maxExportBytes {
+				return nil, nil, errExportTooLarge
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	for _, raw := range e.scoreIDs {
+		if id, err := uuid.Parse(raw); err == nil && !found[id] {
+			for _, format := range e.formats {
+				skipped = append(skipped, models.ExportSkip{ScoreID: id, Format: format})
+			}
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), skipped, nil
+}
+
+// exportFileName names a score's files in an export: its title made safe for
+// file systems, with the start of its ID to keep same-titled scores apart
+func exportFileName(title string, id uuid.UUID) string {
+	name := strings.Trim(exportNameUnsafe.ReplaceAllString(title, "-"), "-")
+	if runes := []rune(name); len(runes) > 80 {
+		name = strings.TrimRight(string(runes[:80]), "-")
+	}
+	if name == "" {
+		name = "score"
+	}
+	return name + "-" + id.String()[:8]
+}
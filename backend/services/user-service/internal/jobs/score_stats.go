@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+	"user-service/internal/database"
+)
+
+func init() {
+	Register(Job{
+		Name:     "snapshot-score-stats",
+		Interval: time.Hour,
+		Run:      snapshotScoreStats,
+	})
+}
+
+// snapshotScoreStats records today's (UTC) running totals of every public
+// score. Later runs overwrite the day's snapshot, so each day keeps the
+// totals from its last run.
+func snapshotScoreStats(ctx context.Context) error {
+	_, err := database.GetDB().ExecContext(ctx, `
+		INSERT INTO score_daily_stats (score_id, day, views, favorites, downloads, learners)
+		SELECT s.id, $1, COALESCE(s.view_count, 0), COALESCE(s.like_count, 0), COALESCE(s.download_count, 0),
+			   (SELECT COUNT(*) FROM learning_progress lp WHERE lp.score_id = s.id AND lp.user_id <> s.user_id)
+		FROM scores s
+		WHERE s.is_public = true AND s.is_draft IS NOT TRUE AND s.deleted_at IS NULL
+		ON CONFLICT (score_id, day) DO UPDATE SET views = EXCLUDED.views, favorites = EXCLUDED.favorites,
+			downloads = EXCLUDED.downloads, learners = EXCLUDED.learners`,
+		time.Now().UTC().Truncate(24*time.Hour),
+	)
+	return err
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	Register(Job{
+		Name:     "purge-score-trash",
+		Interval: time.Hour,
+		Run:      purgeScoreTrash,
+	})
+}
+
+// scoreTrashBatch bounds the scores purged per run
+const scoreTrashBatch = 500
+
+// purgeScoreTrash permanently deletes trashed scores whose retention period,
+// taken from the owner's current tier, has passed. Scores of accounts under
+// legal hold are kept.
+func purgeScoreTrash(ctx context.Context) error {
+	var tiers []string
+	var days []int64
+	for _, t := range models.AllTierCapabilities() {
+		tiers = append(tiers, t.Tier)
+		days = append(days, int64(t.TrashRetentionDays))
+	}
+
+	tx, err := database.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var expired []string
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(array_agg(id), '{}') FROM (
+			SELECT s.id FROM scores s
+			JOIN users u ON u.id = s.user_id
+			LEFT JOIN unnest($1::text[], $2::int[]) AS caps(tier, retention_days) ON caps.tier = u.subscription_tier
+			WHERE s.deleted_at IS NOT NULL AND u.legal_hold_at IS NULL
+			  AND s.deleted_at < NOW() - make_interval(days => COALESCE(caps.retention_days, $3))
+			ORDER BY s.deleted_at
+			LIMIT $4
+			FOR UPDATE OF s
+		) purged`,
+		pq.Array(tiers), pq.Array(days), models.GetTierCapabilities(models.TierFree).TrashRetentionDays, scoreTrashBatch,
+	).Scan(pq.Array(&expired))
+	if err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	// Versions derived from a purged score lose their parent rather than
+	// blocking the delete
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE scores SET parent_score_id = NULL WHERE parent_score_id = ANY($1::uuid[])", pq.Array(expired)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM scores WHERE id = ANY($1::uuid[])", pq.Array(expired)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("Purged %d scores from the trash", len(expired))
+	return nil
+}
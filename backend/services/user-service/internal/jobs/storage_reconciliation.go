@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+	"user-service/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const reconciliationReportKey = "jobs:storage_reconciliation:last"
+
+func init() {
+	Register(Job{
+		Name:     "reconcile-storage",
+		Interval: 24 * time.Hour,
+		Run:      reconcileStorage,
+	})
+}
+
+// ReconciliationReport summarizes a storage reconciliation run. Only
+// undercounting can be detected, so there is no overall drift figure.
+type ReconciliationReport struct {
+	StartedAt         time.Time `json:"started_at"`
+	DurationMS        int64     `json:"duration_ms"`
+	UsersUnderCounted int       `json:"users_under_counted"`
+	UnderCountedMB    int       `json:"under_counted_mb"`
+	MaxUnderCountMB   int       `json:"max_under_count_mb"`
+}
+
+// reconcileStorage reports users whose storage_used_mb is below the size of
+// the score data held in the database, which is a lower bound on their real
+// usage. Uploaded media lives in object storage owned by media-service, which
+// this service cannot list, and no per-file sizes are recorded, so usage
+// above the database share cannot be checked: recorded usage that is higher
+// is not drift, and nothing is written back.
+func reconcileStorage(ctx context.Context) error {
+	report := ReconciliationReport{StartedAt: time.Now()}
+	db := database.GetDB()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT recorded, minimum FROM (
+			SELECT COALESCE(storage_used_mb, 0) AS recorded, calculate_user_storage(id) AS minimum
+			FROM users
+		) a
+		WHERE recorded < minimum`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recorded, minimum int
+		if err := rows.Scan(&recorded, &minimum); err != nil {
+			return err
+		}
+		missing := minimum - recorded
+		report.UnderCountedMB += missing
+		if missing > report.MaxUnderCountMB {
+			report.MaxUnderCountMB = missing
+		}
+		report.UsersUnderCounted++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	report.DurationMS = time.Since(report.StartedAt).Milliseconds()
+	if report.UsersUnderCounted > 0 {
+		log.Printf("Storage reconciliation found %d users counted below their database usage (total %d MB)",
+			report.UsersUnderCounted, report.UnderCountedMB)
+	}
+
+	// Store the report in Redis so every instance can serve it
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	return database.GetRedis().Set(ctx, reconciliationReportKey, data, 0).Err()
+}
+
+// LastReconciliationReport returns the most recent reconciliation report, or nil if none has run
+func LastReconciliationReport(ctx context.Context) (*ReconciliationReport, error) {
+	data, err := database.GetRedis().Get(ctx, reconciliationReportKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var report ReconciliationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
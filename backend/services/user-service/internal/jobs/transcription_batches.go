@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+)
+
+func init() {
+	Register(Job{
+		Name:     "notify-transcription-batches",
+		Interval: time.Minute,
+		Run:      notifyTranscriptionBatches,
+	})
+}
+
+// notifyTranscriptionBatches queues a single completion notification for each
+// batch whose jobs have all finished. Batches are claimed by setting
+// notified_at, so each is notified at most once.
+func notifyTranscriptionBatches(ctx context.Context) error {
+	db := database.GetDB()
+
+	rows, err := db.QueryContext(ctx, `
+		UPDATE transcription_batches b SET notified_at = NOW()
+		WHERE b.notified_at IS NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM transcription_jobs j
+			WHERE j.batch_id = b.id AND j.status IN ('pending', 'processing'))
+		RETURNING b.id, b.user_id`)
+	if err != nil {
+		return err
+	}
+	type finished struct{ id, userID string }
+	var batches []finished
+	for rows.Next() {
+		var b finished
+		if err := rows.Scan(&b.id, &b.userID); err != nil {
+			rows.Close()
+			return err
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+
+	for _, b := range batches {
+		counts := map[string]int{}
+		jobRows, err := db.QueryContext(ctx,
+			"SELECT status, COUNT(*) FROM transcription_jobs WHERE batch_id = $1 GROUP BY status", b.id)
+		if err != nil {
+			return err
+		}
+		for jobRows.Next() {
+			var status string
+			var n int
+			if err := jobRows.Scan(&status, &n); err == nil {
+				counts[status] = n
+			}
+		}
+		jobRows.Close()
+
+		err = notify.Enqueue(ctx, notify.Notification{
+			Type:     notify.TypeTranscriptionBatchCompleted,
+			UserID:   b.userID,
+			Channels: []string{models.ChannelEmail},
+			Data: map[string]interface{}{
+				"batch_id": b.id,
+				"status":   models.BatchStatus(counts),
+				"counts":   counts,
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to enqueue batch completion for %s: %v", b.id, err)
+		}
+	}
+	return nil
+}
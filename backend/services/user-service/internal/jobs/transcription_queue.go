@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	Register(Job{
+		Name:     "prioritize-transcription-jobs",
+		Interval: time.Minute,
+		Run:      prioritizeTranscriptionJobs,
+	})
+}
+
+// prioritizeTranscriptionJobs moves pending jobs to the queue of their owner's
+// current tier, so an upgrade or downgrade applies to jobs already waiting
+func prioritizeTranscriptionJobs(ctx context.Context) error {
+	var tiers []string
+	var priorities []int64
+	for _, t := range models.AllTierCapabilities() {
+		tiers = append(tiers, t.Tier)
+		priorities = append(priorities, int64(t.TranscriptionPriority))
+	}
+
+	result, err := database.GetDB().ExecContext(ctx, `
+		UPDATE transcription_jobs j SET priority = COALESCE(p.priority, $3)
+		FROM users u
+		LEFT JOIN unnest($1::text[], $2::int[]) AS p(tier, priority) ON p.tier = u.subscription_tier
+		WHERE u.id = j.user_id AND j.status = $4 AND j.priority <> COALESCE(p.priority, $3)`,
+		pq.Array(tiers), pq.Array(priorities),
+		models.GetTierCapabilities(models.TierFree).TranscriptionPriority, models.TranscriptionPending,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		log.Printf("Reprioritized %d pending transcription jobs", n)
+	}
+	return nil
+}
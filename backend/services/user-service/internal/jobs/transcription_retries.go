@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/transcription"
+)
+
+func init() {
+	Register(Job{
+		Name:     "recover-stalled-transcriptions",
+		Interval: time.Minute,
+		Run:      recoverStalledTranscriptions,
+	})
+}
+
+// recoverStalledTranscriptions fails processing jobs whose worker stopped
+// sending heartbeats as worker crashes, which retries them or dead-letters
+// those out of attempts
+func recoverStalledTranscriptions(ctx context.Context) error {
+	db := database.GetDB()
+	rows, err := db.QueryContext(ctx, `
+		SELECT id FROM transcription_jobs
+		WHERE status = $1 AND COALESCE(heartbeat_at, started_at, created_at) < NOW() - make_interval(secs => $2)`,
+		models.TranscriptionProcessing, transcription.StallTimeout.Seconds(),
+	)
+	if err != nil {
+		return err
+	}
+	var stalled []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		stalled = append(stalled, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range stalled {
+		// Jobs that finished or sent a heartbeat since the scan are left alone
+		outcome, err := transcription.FailStalled(ctx, db, id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		transcription.Publish(ctx, db, id)
+		log.Printf("Transcription job %s stalled on attempt %d: %s", id, outcome.Attempts, outcome.Status)
+	}
+	return nil
+}
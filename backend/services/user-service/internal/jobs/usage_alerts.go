@@ -0,0 +1,148 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	Register(Job{
+		Name:     "send-usage-alerts",
+		Interval: 15 * time.Minute,
+		Run:      sendUsageAlerts,
+	})
+}
+
+// usageCandidate is a user whose usage may have crossed an alert threshold
+type usageCandidate struct {
+	userID, tier string
+	used, limit  int
+	level        int
+}
+
+// sendUsageAlerts notifies users whose storage or monthly transcription
+// minutes reach 80%, 95% or 100% of their quota. Each threshold alerts once
+// until usage falls back below it (see models.NextUsageLevel).
+func sendUsageAlerts(ctx context.Context) error {
+	db := database.GetDB()
+
+	// Only users near a threshold or with an armed alert level need checking.
+	// Limits come from the tier table; unknown tiers get the free limit.
+	tiers, limits := tierStorageLimits()
+	storage, err := queryUsageCandidates(ctx, db, `
+		SELECT u.id, u.subscription_tier, COALESCE(u.storage_used_mb, 0), 0, COALESCE(a.level, 0)
+		FROM users u
+		LEFT JOIN unnest($2::text[], $3::int[]) AS caps(tier, storage_limit_mb) ON caps.tier = u.subscription_tier
+		LEFT JOIN usage_alerts a ON a.user_id = u.id AND a.metric = 'storage'
+		WHERE u.is_active = true
+		  AND (a.level > 0 OR COALESCE(u.storage_used_mb, 0) * 100 >= COALESCE(caps.storage_limit_mb, $4) * $1)`,
+		models.UsageThresholds[0]-models.UsageHysteresisPercent, pq.Array(tiers), pq.Array(limits),
+		models.GetTierCapabilities(models.TierFree).StorageLimitMB,
+	)
+	if err != nil {
+		return err
+	}
+	for _, u := range storage {
+		u.limit = models.GetTierCapabilities(u.tier).StorageLimitMB
+		evaluateUsage(ctx, db, models.UsageStorage, "mb", u)
+	}
+
+	minutes, err := queryUsageCandidates(ctx, db, `
+		SELECT u.id, u.subscription_tier, COALESCE(m.minutes, 0), 0, COALESCE(a.level, 0)
+		FROM users u
+		LEFT JOIN (
+			SELECT j.user_id, (SUM(s.audio_duration_seconds) + 59) / 60 AS minutes
+			FROM transcription_jobs j JOIN scores s ON s.id = j.score_id
+			WHERE j.status = 'completed' AND j.completed_at >= date_trunc('month', NOW())
+			GROUP BY j.user_id
+		) m ON m.user_id = u.id
+		LEFT JOIN usage_alerts a ON a.user_id = u.id AND a.metric = 'transcription_minutes'
+		WHERE u.is_active = true AND (m.minutes > 0 OR a.level > 0)`,
+	)
+	if err != nil {
+		return err
+	}
+	for _, u := range minutes {
+		u.limit = models.GetTierCapabilities(u.tier).TranscriptionMinutes
+		evaluateUsage(ctx, db, models.UsageTranscriptionMinutes, "minutes", u)
+	}
+	return nil
+}
+
+// tierStorageLimits returns the tiers with their storage limits, as parallel
+// arrays for unnest
+func tierStorageLimits() ([]string, []int64) {
+	var tiers []string
+	var limits []int64
+	for _, t := range models.AllTierCapabilities() {
+		tiers = append(tiers, t.Tier)
+		limits = append(limits, int64(t.StorageLimitMB))
+	}
+	return tiers, limits
+}
+
+func queryUsageCandidates(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]usageCandidate, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []usageCandidate
+	for rows.Next() {
+		var u usageCandidate
+		if err := rows.Scan(&u.userID, &u.tier, &u.used, &u.limit, &u.level); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, u)
+	}
+	return candidates, rows.Err()
+}
+
+// evaluateUsage stores the user's new alert level for a metric and queues a
+// notification when a higher threshold was reached. The level only moves from
+// the one read with the candidate, so an overlapping run that already moved it
+// changes nothing and sends no second alert.
+func evaluateUsage(ctx context.Context, db *sql.DB, metric, unit string, u usageCandidate) {
+	meter := models.NewUsageMeter(metric, unit, u.used, u.limit)
+	level, alert := models.NextUsageLevel(u.level, meter.Percent)
+	if level == u.level {
+		return
+	}
+
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO usage_alerts (user_id, metric, level) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, metric) DO UPDATE SET level = EXCLUDED.level
+		WHERE usage_alerts.level = $4`,
+		u.userID, metric, level, u.level,
+	)
+	if err != nil {
+		log.Printf("Failed to store usage alert level for %s: %v", u.userID, err)
+		return
+	}
+	if changed, err := result.RowsAffected(); err != nil || changed == 0 || !alert {
+		return
+	}
+
+	err = notify.Enqueue(ctx, notify.Notification{
+		Type:     notify.TypeUsageThreshold,
+		UserID:   u.userID,
+		Channels: []string{models.ChannelPush, models.ChannelEmail},
+		Data: map[string]interface{}{
+			"metric":    metric,
+			"threshold": level,
+			"usage":     meter,
+			"tier":      u.tier,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to enqueue usage alert for %s: %v", u.userID, err)
+	}
+}
@@ -0,0 +1,138 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+	"user-service/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Periods
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Metrics
+const (
+	MetricPracticeMinutes = "practice_minutes"
+	MetricSongsCompleted  = "songs_completed"
+)
+
+// Periods and Metrics list the supported values
+var (
+	Periods = []string{PeriodWeek, PeriodMonth}
+	Metrics = []string{MetricPracticeMinutes, MetricSongsCompleted}
+)
+
+// Entry is one ranked user
+type Entry struct {
+	Rank     int    `json:"rank"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username,omitempty"`
+	Score    int    `json:"score"`
+}
+
+// PeriodStart returns the start of the period containing t, in UTC. Weeks
+// start on Monday.
+func PeriodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if period == PeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// PeriodEnd returns the end of the period starting at start
+func PeriodEnd(period string, start time.Time) time.Time {
+	if period == PeriodMonth {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+func key(period, metric string, start time.Time) string {
+	return "leaderboard:" + period + ":" + metric + ":" + start.Format("20060102")
+}
+
+func computedAtKey(period, metric string, start time.Time) string {
+	return key(period, metric, start) + ":computed_at"
+}
+
+// Store replaces the cached ranking for a period and metric
+func Store(ctx context.Context, period, metric string, start time.Time, scores map[string]int) error {
+	rdb := database.GetRedis()
+	k := key(period, metric, start)
+	tmp := k + ":building"
+	ttl := time.Until(PeriodEnd(period, start)) + 7*24*time.Hour
+
+	pipe := rdb.TxPipeline()
+	pipe.Del(ctx, tmp)
+	if len(scores) > 0 {
+		members := make([]redis.Z, 0, len(scores))
+		for userID, score := range scores {
+			members = append(members, redis.Z{Score: float64(score), Member: userID})
+		}
+		pipe.ZAdd(ctx, tmp, members...)
+		pipe.Rename(ctx, tmp, k)
+		pipe.Expire(ctx, k, ttl)
+	} else {
+		pipe.Del(ctx, k)
+	}
+	pipe.Set(ctx, computedAtKey(period, metric, start), time.Now().Unix(), ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Top returns up to limit entries starting at offset, best first. Usernames are
+// filled in by the caller.
+func Top(ctx context.Context, period, metric string, start time.Time, offset, limit int) ([]Entry, error) {
+	members, err := database.GetRedis().ZRevRangeWithScores(ctx, key(period, metric, start),
+		int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(members))
+	for i, m := range members {
+		entries = append(entries, Entry{
+			Rank:   offset + i + 1,
+			UserID: m.Member.(string),
+			Score:  int(m.Score),
+		})
+	}
+	return entries, nil
+}
+
+// Standing returns a user's entry, or nil if they are not ranked
+func Standing(ctx context.Context, period, metric string, start time.Time, userID string) (*Entry, error) {
+	rdb := database.GetRedis()
+	k := key(period, metric, start)
+
+	rank, err := rdb.ZRevRank(ctx, k, userID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	score, err := rdb.ZScore(ctx, k, userID).Result()
+	if err != nil {
+		return nil, err
+	}
+	return &Entry{Rank: int(rank) + 1, UserID: userID, Score: int(score)}, nil
+}
+
+// ComputedAt returns when the ranking was last computed, or the zero time
+func ComputedAt(ctx context.Context, period, metric string, start time.Time) time.Time {
+	v, err := database.GetRedis().Get(ctx, computedAtKey(period, metric, start)).Result()
+	if err != nil {
+		return time.Time{}
+	}
+	sec, _ := strconv.ParseInt(v, 10, 64)
+	return time.Unix(sec, 0)
+}
@@ -0,0 +1,193 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"user-service/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotAcquired is returned when the lock is held by another instance
+var ErrNotAcquired = errors.New("lock not acquired")
+
+// ErrLockLost is returned when a held lock expired or was taken over
+var ErrLockLost = errors.New("lock lost")
+
+// releaseScript deletes the key only if it still holds our token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// extendScript resets the TTL only if the key still holds our token
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// Mutex is a Redis-backed distributed lock with automatic renewal
+type Mutex struct {
+	key   string
+	ttl   time.Duration
+	token string
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+	lost chan struct{}
+	held bool
+}
+
+// NewMutex creates a lock for the given name. The TTL bounds how long the lock
+// survives a crashed holder; it is renewed automatically while held.
+func NewMutex(name string, ttl time.Duration) *Mutex {
+	return &Mutex{
+		key: "lock:" + name,
+		ttl: ttl,
+	}
+}
+
+// TryLock attempts to acquire the lock once without waiting
+func (m *Mutex) TryLock(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.held {
+		return nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	ok, err := database.GetRedis().SetNX(ctx, m.key, token, m.ttl).Result()
+	if err != nil {
+		metrics.errors.Add(1)
+		return err
+	}
+	if !ok {
+		metrics.contended.Add(1)
+		return ErrNotAcquired
+	}
+
+	metrics.acquired.Add(1)
+	m.token = token
+	m.held = true
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	m.lost = make(chan struct{})
+	go m.renew()
+
+	return nil
+}
+
+// Lost returns a channel that is closed if the lock is lost while held
+func (m *Mutex) Lost() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lost
+}
+
+// Unlock stops renewal and releases the lock if we still hold it
+func (m *Mutex) Unlock(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.held {
+		m.mu.Unlock()
+		return nil
+	}
+	m.held = false
+	close(m.stop)
+	m.mu.Unlock()
+
+	<-m.done
+
+	n, err := releaseScript.Run(ctx, database.GetRedis(), []string{m.key}, m.token).Int()
+	if err != nil {
+		metrics.errors.Add(1)
+		return err
+	}
+	metrics.released.Add(1)
+	if n == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// ExpireAfter stops renewal and leaves the lock held until d has elapsed instead
+// of releasing it, so no other instance can acquire it in the meantime
+func (m *Mutex) ExpireAfter(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return m.Unlock(ctx)
+	}
+
+	m.mu.Lock()
+	if !m.held {
+		m.mu.Unlock()
+		return nil
+	}
+	m.held = false
+	close(m.stop)
+	m.mu.Unlock()
+
+	<-m.done
+
+	n, err := extendScript.Run(ctx, database.GetRedis(), []string{m.key}, m.token, d.Milliseconds()).Int()
+	if err != nil {
+		metrics.errors.Add(1)
+		return err
+	}
+	metrics.released.Add(1)
+	if n == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// renew extends the lock TTL at a third of its lifetime until stopped
+func (m *Mutex) renew() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), m.ttl/3)
+			n, err := extendScript.Run(ctx, database.GetRedis(), []string{m.key}, m.token, m.ttl.Milliseconds()).Int()
+			cancel()
+
+			if err != nil {
+				metrics.renewFailures.Add(1)
+				log.Printf("Failed to renew lock %s: %v", m.key, err)
+				continue
+			}
+			if n == 0 {
+				metrics.lost.Add(1)
+				log.Printf("Lock %s lost before renewal", m.key)
+				close(m.lost)
+				return
+			}
+			metrics.renewals.Add(1)
+		}
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,86 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/internal/redistest"
+)
+
+func TestUnlockByNonOwner(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// interfere runs while the owner holds the lock
+		interfere func(t *testing.T, srv *redistest.Server, key string)
+		wantErr   error
+		// wantValue is what the key should hold afterwards; empty means deleted
+		wantValue string
+	}{
+		{
+			name:      "owner releases its own lock",
+			interfere: func(*testing.T, *redistest.Server, string) {},
+		},
+		{
+			name: "contender cannot acquire or release a held lock",
+			interfere: func(t *testing.T, _ *redistest.Server, _ string) {
+				other := NewMutex("job", time.Minute)
+				if err := other.TryLock(ctx); !errors.Is(err, ErrNotAcquired) {
+					t.Fatalf("contender TryLock() = %v, want ErrNotAcquired", err)
+				}
+				if err := other.Unlock(ctx); err != nil {
+					t.Fatalf("contender Unlock() = %v, want nil", err)
+				}
+			},
+		},
+		{
+			name: "stale owner does not release a lock taken over after expiry",
+			interfere: func(_ *testing.T, srv *redistest.Server, key string) {
+				srv.Set(key, "other-instance-token")
+			},
+			wantErr:   ErrLockLost,
+			wantValue: "other-instance-token",
+		},
+		{
+			name: "stale owner reports an expired lock as lost",
+			interfere: func(_ *testing.T, srv *redistest.Server, key string) {
+				srv.Del(key)
+			},
+			wantErr: ErrLockLost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := redistest.Start(t)
+
+			owner := NewMutex("job", time.Minute)
+			if err := owner.TryLock(ctx); err != nil {
+				t.Fatalf("TryLock() = %v", err)
+			}
+			token, ok := srv.Get(owner.key)
+			if !ok || token != owner.token {
+				t.Fatalf("lock key holds %q, want the owner's token", token)
+			}
+
+			tt.interfere(t, srv, owner.key)
+
+			if err := owner.Unlock(ctx); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Unlock() = %v, want %v", err, tt.wantErr)
+			}
+			value, ok := srv.Get(owner.key)
+			if tt.wantValue == "" && ok {
+				t.Errorf("lock key still holds %q after release", value)
+			}
+			if tt.wantValue != "" && value != tt.wantValue {
+				t.Errorf("lock key holds %q, want %q", value, tt.wantValue)
+			}
+
+			// A second release is a no-op
+			if err := owner.Unlock(ctx); err != nil {
+				t.Errorf("second Unlock() = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package lock
+
+import "sync/atomic"
+
+var metrics struct {
+	acquired      atomic.Int64
+	contended     atomic.Int64
+	released      atomic.Int64
+	renewals      atomic.Int64
+	renewFailures atomic.Int64
+	lost          atomic.Int64
+	errors        atomic.Int64
+}
+
+// Stats is a snapshot of lock activity on this instance
+type Stats struct {
+	Acquired      int64 `json:"acquired"`
+	Contended     int64 `json:"contended"`
+	Released      int64 `json:"released"`
+	Renewals      int64 `json:"renewals"`
+	RenewFailures int64 `json:"renew_failures"`
+	Lost          int64 `json:"lost"`
+	Errors        int64 `json:"errors"`
+}
+
+// GetStats returns the current lock counters for this instance
+func GetStats() Stats {
+	return Stats{
+		Acquired:      metrics.acquired.Load(),
+		Contended:     metrics.contended.Load(),
+		Released:      metrics.released.Load(),
+		Renewals:      metrics.renewals.Load(),
+		RenewFailures: metrics.renewFailures.Load(),
+		Lost:          metrics.lost.Load(),
+		Errors:        metrics.errors.Load(),
+	}
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/encryption"
+	"user-service/internal/models"
+	"user-service/internal/ratelimit"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// APIKeyMiddleware authenticates public API requests by API key (X-API-Key
+// header or "Authorization: ApiKey <key>"), enforces the owner's per-minute
+// rate limit and monthly per-key quota, and meters usage per endpoint
+func APIKeyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			if parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2); len(parts) == 2 && parts[0] == "ApiKey" {
+				key = parts[1]
+			}
+		}
+		if !strings.HasPrefix(key, utils.APIKeyPrefix) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			c.Abort()
+			return
+		}
+
+		db := database.GetDB()
+		var keyID, userID, tier string
+		var isActive bool
+		var scopes []string
+		var signingSecret sql.NullString
+		err := db.QueryRow(`
+			SELECT k.id, k.user_id, u.subscription_tier, u.is_active, k.scopes, k.signing_secret
+			FROM api_keys k JOIN users u ON u.id = k.user_id
+			WHERE k.key_hash = $1 AND k.revoked_at IS NULL`,
+			utils.HashAPIKey(key),
+		).Scan(&keyID, &userID, &tier, &isActive, pq.Array(&scopes), &signingSecret)
+
+		if err != nil || !isActive {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+
+		caps := models.GetTierCapabilities(tier)
+		ctx := c.Request.Context()
+
+		rate, err := ratelimit.Allow(ctx, "apikey:"+keyID, caps.APIRateLimitPerMinute, time.Minute)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limiter unavailable"})
+			c.Abort()
+			return
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rate.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(rate.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(rate.ResetAt.Unix(), 10))
+		if !rate.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(rate.ResetAt).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": &models.QuotaError{
+				Code:    models.QuotaAPIRate,
+				Message: "Rate limit exceeded",
+				Tier:    tier,
+				Limit:   caps.APIRateLimitPerMinute,
+			}})
+			c.Abort()
+			return
+		}
+
+		quota, err := ratelimit.AllowMonthly(ctx, "apikey:"+keyID, caps.APIMonthlyQuota)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limiter unavailable"})
+			c.Abort()
+			return
+		}
+		c.Header("X-Quota-Limit", strconv.Itoa(quota.Limit))
+		c.Header("X-Quota-Remaining", strconv.Itoa(quota.Remaining))
+		if !quota.Allowed {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": &models.QuotaError{
+				Code:    models.QuotaAPIMonthly,
+				Message: "Monthly API quota exceeded",
+				Tier:    tier,
+				Limit:   caps.APIMonthlyQuota,
+			}})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("api_key_id", keyID)
+		c.Set("principal_type", "api_key")
+		if scopes == nil {
+			scopes = []string{}
+		}
+		c.Set("scopes", scopes)
+
+		// Replay-protected routes verify request signatures with this secret
+		if signingSecret.Valid {
+			if secret, err := encryption.Decrypt(signingSecret.String); err == nil {
+				c.Set("api_key_signing_secret", secret)
+			} else {
+				log.Printf("Failed to decrypt API key signing secret: %v", err)
+			}
+		}
+
+		c.Next()
+
+		// Meter the request for usage reports
+		_, err = db.Exec(`
+			INSERT INTO api_key_usage (api_key_id, day, endpoint, request_count)
+			VALUES ($1, CURRENT_DATE, $2, 1)
+			ON CONFLICT (api_key_id, day, endpoint)
+			DO UPDATE SET request_count = api_key_usage.request_count + 1`,
+			keyID, c.Request.Method+" "+c.FullPath(),
+		)
+		if err != nil {
+			log.Printf("Failed to record API usage: %v", err)
+		}
+		_, _ = db.Exec("UPDATE api_keys SET last_used_at = NOW() WHERE id = $1", keyID)
+	}
+}
@@ -3,9 +3,12 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"user-service/internal/database"
+	"user-service/internal/models"
 	"user-service/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
 // AuthMiddleware validates JWT tokens
@@ -14,9 +17,23 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
+			// Fall back to the cookie session
+			cookieToken, err := c.Cookie(utils.AccessTokenCookie)
+			if err != nil || cookieToken == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+				c.Abort()
+				return
+			}
+
+			// Cookies are sent automatically, so require a header a cross-site form can't set
+			if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead &&
+				c.GetHeader("X-Requested-With") == "" {
+				c.JSON(http.StatusForbidden, gin.H{"error": "X-Requested-With header required for cookie sessions"})
+				c.Abort()
+				return
+			}
+
+			authHeader = "Bearer " + cookieToken
 		}
 
 		// Check if it's a Bearer token
@@ -29,6 +46,17 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
+		// Opaque tokens issued to third-party applications
+		if strings.HasPrefix(tokenString, utils.OAuthAccessTokenPrefix) {
+			if !authenticateOAuthToken(c, tokenString) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
 		// Validate token
 		claims, err := utils.ValidateAccessToken(tokenString)
 		if err != nil {
@@ -42,20 +70,128 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("email", claims.Email)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("principal_type", "user")
+		if claims.Scopes != nil {
+			c.Set("scopes", claims.Scopes)
+		}
+		if claims.ImpersonatorID != "" {
+			c.Set("impersonator_id", claims.ImpersonatorID)
+		}
+
+		c.Next()
+	}
+}
+
+// authenticateOAuthToken resolves an opaque OAuth access token and sets the
+// user and granted scopes in the context
+func authenticateOAuthToken(c *gin.Context, token string) bool {
+	db := database.GetDB()
 
+	var userID, email, username, clientID string
+	var scopes []string
+	err := db.QueryRow(`
+		SELECT t.user_id, u.email, u.username, oc.client_id, t.scopes
+		FROM oauth_tokens t
+		JOIN users u ON u.id = t.user_id
+		JOIN oauth_clients oc ON oc.id = t.client_id
+		WHERE t.access_token_hash = $1 AND t.revoked_at IS NULL AND t.expires_at > NOW()
+		  AND u.is_active = true AND oc.deleted_at IS NULL`,
+		utils.HashAPIKey(token),
+	).Scan(&userID, &email, &username, &clientID, pq.Array(&scopes))
+	if err != nil {
+		return false
+	}
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	c.Set("user_id", userID)
+	c.Set("email", email)
+	c.Set("username", username)
+	c.Set("role", "user")
+	c.Set("principal_type", "oauth_client")
+	c.Set("client_id", clientID)
+	c.Set("scopes", scopes)
+	return true
+}
+
+// RequireScope rejects scope-restricted credentials (API keys, OAuth and
+// impersonation tokens) that were not granted the scope. Unrestricted
+// first-party sessions always pass.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, restricted := c.Get("scopes")
+		if restricted {
+			granted := false
+			for _, s := range value.([]string) {
+				if s == scope {
+					granted = true
+					break
+				}
+			}
+			if !granted {
+				c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient scope", "required_scope": scope})
+				c.Abort()
+				return
+			}
+		}
 		c.Next()
 	}
 }
 
-// AdminMiddleware checks if user has admin role
+// RequireFirstParty limits a route to unrestricted first-party sessions, for
+// account-level actions no delegated credential should perform
+func RequireFirstParty() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, restricted := c.Get("scopes"); restricted {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This action requires a first-party session"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminMiddleware checks if user has admin role. Super admins are admins too.
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
+		role := c.GetString("role")
+		if role != models.RoleAdmin && role != models.RoleSuperAdmin {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 			c.Abort()
 			return
 		}
 		c.Next()
 	}
+}
+
+// SuperAdminMiddleware limits a route to super admins, for actions such as
+// legal holds that ordinary admins must not perform
+func SuperAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != models.RoleSuperAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Super admin access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware authenticates the request like AuthMiddleware when
+// it carries credentials and lets it through anonymously otherwise, for public
+// routes that show the caller more of their own content
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token, err := c.Cookie(utils.AccessTokenCookie); err != nil || token == "" {
+				c.Next()
+				return
+			}
+		}
+		auth(c)
+	}
 }
\ No newline at end of file
@@ -7,29 +7,35 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// IsAllowedOrigin reports whether origin is listed in CORS_ORIGINS
+func IsAllowedOrigin(origin string) bool {
+	// Get allowed origins from environment
+	allowedOrigins := os.Getenv("CORS_ORIGINS")
+	if allowedOrigins == "" {
+		allowedOrigins = "http://localhost:5173,http://localhost:3000"
+	}
+
+	for _, allowedOrigin := range strings.Split(allowedOrigins, ",") {
+		if origin == strings.TrimSpace(allowedOrigin) {
+			return true
+		}
+	}
+	return false
+}
+
 // CORSMiddleware handles CORS headers
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get allowed origins from environment
-		allowedOrigins := os.Getenv("CORS_ORIGINS")
-		if allowedOrigins == "" {
-			allowedOrigins = "http://localhost:5173,http://localhost:3000"
-		}
-
 		origin := c.Request.Header.Get("Origin")
 		
 		// Check if origin is allowed
-		origins := strings.Split(allowedOrigins, ",")
-		for _, allowedOrigin := range origins {
-			if origin == strings.TrimSpace(allowedOrigin) {
-				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-				break
-			}
+		if IsAllowedOrigin(origin) {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 		}
 
 		// Set other CORS headers
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Session-Mode")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
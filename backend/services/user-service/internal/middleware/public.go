@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/models"
+	"user-service/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicRateLimit returns the per-IP request budget per minute for anonymous traffic
+func publicRateLimit() int {
+	if n, err := strconv.Atoi(os.Getenv("PUBLIC_RATE_LIMIT_PER_MINUTE")); err == nil && n > 0 {
+		return n
+	}
+	return 60
+}
+
+// publicCacheTTL returns how long anonymous responses are cached
+func publicCacheTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("PUBLIC_CACHE_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return time.Minute
+}
+
+// AnonymousRateLimitMiddleware limits unauthenticated requests per client IP.
+// The budget is separate from API key and user rate limits.
+func AnonymousRateLimitMiddleware() gin.HandlerFunc {
+	limit := publicRateLimit()
+	return func(c *gin.Context) {
+		rate, err := ratelimit.Allow(c.Request.Context(), "anon:"+c.ClientIP(), limit, time.Minute)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limiter unavailable"})
+			c.Abort()
+			return
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rate.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(rate.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(rate.ResetAt.Unix(), 10))
+		if !rate.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(rate.ResetAt).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// cachedResponse is a successful response body stored in Redis
+type cachedResponse struct {
+	ContentType     string `json:"content_type"`
+	ContentLanguage string `json:"content_language,omitempty"`
+	Body            []byte `json:"body"`
+	ETag            string `json:"etag"`
+}
+
+// responseRecorder captures the body of a response while writing it through,
+// and marks successful responses as publicly cacheable
+type responseRecorder struct {
+	gin.ResponseWriter
+	body         bytes.Buffer
+	cacheControl string
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if code == http.StatusOK {
+		w.Header().Set("Cache-Control", w.cacheControl)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// PublicCacheMiddleware serves anonymous GET requests from a shared Redis
+// cache keyed by path and query, so hot content never reaches the database.
+// Successful responses are stored for PUBLIC_CACHE_TTL and marked cacheable
+// by browsers and CDNs for the same period, varying by Accept-Language.
+// Cache hits carry an ETag and answer If-None-Match with 304.
+func PublicCacheMiddleware() gin.HandlerFunc {
+	ttl := publicCacheTTL()
+	cacheControl := "public, max-age=" + strconv.Itoa(int(ttl.Seconds()))
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		// Responses are translated for the client's Accept-Language, so the
+		// key carries the locales it resolves to rather than the raw header
+		ctx := c.Request.Context()
+		key := "public_cache:" + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode() +
+			"|" + strings.Join(models.RequestLocales(c.GetHeader("Accept-Language")), ",")
+		c.Header("Vary", "Accept-Language")
+
+		var cached cachedResponse
+		if payload, err := database.GetRedis().Get(ctx, key).Bytes(); err == nil && json.Unmarshal(payload, &cached) == nil {
+			c.Header("Cache-Control", cacheControl)
+			c.Header("ETag", cached.ETag)
+			c.Header("X-Cache", "HIT")
+			if cached.ContentLanguage != "" {
+				c.Header("Content-Language", cached.ContentLanguage)
+			}
+			for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+				if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == cached.ETag {
+					c.AbortWithStatus(http.StatusNotModified)
+					return
+				}
+			}
+			c.Data(http.StatusOK, cached.ContentType, cached.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, cacheControl: cacheControl}
+		c.Writer = recorder
+		c.Header("X-Cache", "MISS")
+		c.Next()
+
+		if recorder.Status() != http.StatusOK {
+			return
+		}
+		sum := sha256.Sum256(recorder.body.Bytes())
+		payload, err := json.Marshal(cachedResponse{
+			ContentType:     recorder.Header().Get("Content-Type"),
+			ContentLanguage: recorder.Header().Get("Content-Language"),
+			Body:            recorder.body.Bytes(),
+			ETag:            `"` + hex.EncodeToString(sum[:16]) + `"`,
+		})
+		if err != nil {
+			return
+		}
+		if err := database.GetRedis().Set(ctx, key, payload, ttl).Err(); err != nil {
+			log.Printf("Failed to cache public response %s: %v", key, err)
+		}
+	}
+}
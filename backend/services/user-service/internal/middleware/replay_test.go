@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"user-service/internal/redistest"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testSigningSecret = utils.SigningSecretPrefix + "test"
+
+type signedRequest struct {
+	timestamp string
+	nonce     string
+	body      string
+	// signature overrides the computed one when set
+	signature string
+}
+
+func newReplayRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("principal_type", "api_key")
+		c.Set("user_id", "user-1")
+		c.Set("api_key_signing_secret", testSigningSecret)
+	})
+	r.POST("/transcriptions", ReplayProtectionMiddleware(5*time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+	return r
+}
+
+func (s signedRequest) send(r *gin.Engine) int {
+	signature := s.signature
+	if signature == "" {
+		canonical := utils.CanonicalRequest(http.MethodPost, "/transcriptions", s.timestamp, s.nonce, []byte(s.body))
+		mac := hmac.New(sha256.New, []byte(testSigningSecret))
+		mac.Write(canonical)
+		signature = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/transcriptions", strings.NewReader(s.body))
+	req.Header.Set("X-Timestamp", s.timestamp)
+	req.Header.Set("X-Nonce", s.nonce)
+	req.Header.Set("X-Signature", signature)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestReplayProtectionMiddleware(t *testing.T) {
+	now := time.Now().Unix()
+	ts := func(offset time.Duration) string {
+		return strconv.FormatInt(now+int64(offset/time.Second), 10)
+	}
+
+	tests := []struct {
+		name     string
+		requests []signedRequest
+		want     []int
+	}{
+		{
+			name:     "valid request",
+			requests: []signedRequest{{timestamp: ts(0), nonce: "nonce-valid-0001", body: "{}"}},
+			want:     []int{http.StatusAccepted},
+		},
+		{
+			name:     "stale timestamp",
+			requests: []signedRequest{{timestamp: ts(-6 * time.Minute), nonce: "nonce-stale-0001", body: "{}"}},
+			want:     []int{http.StatusUnauthorized},
+		},
+		{
+			name:     "future timestamp",
+			requests: []signedRequest{{timestamp: ts(6 * time.Minute), nonce: "nonce-future-001", body: "{}"}},
+			want:     []int{http.StatusUnauthorized},
+		},
+		{
+			name:     "timestamp inside the window",
+			requests: []signedRequest{{timestamp: ts(-4 * time.Minute), nonce: "nonce-recent-001", body: "{}"}},
+			want:     []int{http.StatusAccepted},
+		},
+		{
+			name:     "malformed timestamp",
+			requests: []signedRequest{{timestamp: "yesterday", nonce: "nonce-malformed1", body: "{}"}},
+			want:     []int{http.StatusBadRequest},
+		},
+		{
+			name:     "bad signature",
+			requests: []signedRequest{{timestamp: ts(0), nonce: "nonce-badsig-001", body: "{}", signature: strings.Repeat("00", 32)}},
+			want:     []int{http.StatusUnauthorized},
+		},
+		{
+			name:     "short nonce",
+			requests: []signedRequest{{timestamp: ts(0), nonce: "short", body: "{}"}},
+			want:     []int{http.StatusBadRequest},
+		},
+		{
+			name: "reused nonce",
+			requests: []signedRequest{
+				{timestamp: ts(0), nonce: "nonce-reused-001", body: "{}"},
+				{timestamp: ts(0), nonce: "nonce-reused-001", body: "{}"},
+			},
+			want: []int{http.StatusAccepted, http.StatusConflict},
+		},
+		{
+			name: "reused nonce with a fresh signature",
+			requests: []signedRequest{
+				{timestamp: ts(0), nonce: "nonce-resigned-1", body: "{}"},
+				{timestamp: ts(time.Second), nonce: "nonce-resigned-1", body: `{"retry":true}`},
+			},
+			want: []int{http.StatusAccepted, http.StatusConflict},
+		},
+		{
+			name: "rejected request does not burn its nonce",
+			requests: []signedRequest{
+				{timestamp: ts(0), nonce: "nonce-retry-0001", body: "{}", signature: strings.Repeat("00", 32)},
+				{timestamp: ts(0), nonce: "nonce-retry-0001", body: "{}"},
+			},
+			want: []int{http.StatusUnauthorized, http.StatusAccepted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redistest.Start(t)
+			r := newReplayRouter()
+
+			for i, req := range tt.requests {
+				if got := req.send(r); got != tt.want[i] {
+					t.Errorf("request %d: status = %d, want %d", i+1, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"user-service/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceAuthMiddleware authenticates internal callers by service identity token.
+// Only the listed services are allowed. When the caller presented a TLS client
+// certificate, its common name must match the service named in the token.
+func ServiceAuthMiddleware(allowedServices ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(allowedServices))
+	for _, s := range allowedServices {
+		allowed[s] = true
+	}
+
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Service credentials required"})
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.ValidateServiceToken(parts[1])
+		if err != nil {
+			// End-user tokens are HMAC-signed and land here too
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid service token"})
+			c.Abort()
+			return
+		}
+
+		if tls := c.Request.TLS; tls != nil && len(tls.PeerCertificates) > 0 {
+			if tls.PeerCertificates[0].Subject.CommonName != claims.Service {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Client certificate does not match service identity"})
+				c.Abort()
+				return
+			}
+		}
+
+		if !allowed[claims.Service] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Service not allowed"})
+			c.Abort()
+			return
+		}
+
+		c.Set("principal_type", "service")
+		c.Set("service", claims.Service)
+
+		c.Next()
+	}
+}
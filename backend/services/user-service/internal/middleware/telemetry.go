@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"user-service/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// telemetryRateLimit returns how many telemetry batches a user may send per minute
+func telemetryRateLimit() int {
+	if n, err := strconv.Atoi(os.Getenv("TELEMETRY_RATE_LIMIT_PER_MINUTE")); err == nil && n > 0 {
+		return n
+	}
+	return 30
+}
+
+// TelemetryRateLimitMiddleware limits telemetry batches per authenticated
+// user, separately from their API and page request budgets
+func TelemetryRateLimitMiddleware() gin.HandlerFunc {
+	limit := telemetryRateLimit()
+	return func(c *gin.Context) {
+		rate, err := ratelimit.Allow(c.Request.Context(), "telemetry:"+c.GetString("user_id"), limit, time.Minute)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limiter unavailable"})
+			c.Abort()
+			return
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rate.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(rate.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(rate.ResetAt.Unix(), 10))
+		if !rate.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(rate.ResetAt).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
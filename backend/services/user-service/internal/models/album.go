@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Album groups a user's scores into an ordered release. ReleaseDate is a
+// calendar date (YYYY-MM-DD).
+type Album struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Artist      *string    `json:"artist,omitempty"`
+	ReleaseDate *string    `json:"release_date,omitempty"`
+	Label       *string    `json:"label,omitempty"`
+	CoverURL    *string    `json:"cover_url,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	IsPublic    bool       `json:"is_public"`
+	TrackCount  int        `json:"track_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// AlbumRequest creates an album or replaces its metadata
+type AlbumRequest struct {
+	Title       string  `json:"title" binding:"required,max=255"`
+	Artist      *string `json:"artist" binding:"omitempty,max=255"`
+	ReleaseDate *string `json:"release_date" binding:"omitempty,datetime=2006-01-02"`
+	Label       *string `json:"label" binding:"omitempty,max=255"`
+	CoverURL    *string `json:"cover_url" binding:"omitempty,url,max=500"`
+	Description *string `json:"description" binding:"omitempty,max=5000"`
+}
+
+// AlbumTracks replaces an album's track list, in order. Scores in another
+// album move to this one.
+type AlbumTracks struct {
+	ScoreIDs []uuid.UUID `json:"score_ids" binding:"required,max=200"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Analytics metrics computed by the aggregation job
+const (
+	MetricActiveUsers = "active_users"
+	MetricEvents      = "events"
+	MetricEventUsers  = "event_users"
+	// Distinct users exposed per experiment variant, dimension "experiment:variant"
+	MetricExperimentExposures = "experiment_exposures"
+)
+
+// DailyMetric is one day's value of an aggregated analytics metric. Dimension
+// is the event name for per-event metrics.
+type DailyMetric struct {
+	Day       time.Time `json:"day"`
+	Metric    string    `json:"metric"`
+	Dimension string    `json:"dimension,omitempty"`
+	Value     int64     `json:"value"`
+}
+
+// CreatorStats counts activity on a creator's public scores: views,
+// favorites, downloads and new learners practicing them
+type CreatorStats struct {
+	Views     int64 `json:"views"`
+	Favorites int64 `json:"favorites"`
+	Downloads int64 `json:"downloads"`
+	Learners  int64 `json:"learners"`
+}
+
+// CreatorDailyStats is the activity on a creator's public scores on one day
+type CreatorDailyStats struct {
+	Day time.Time `json:"day"`
+	CreatorStats
+}
+
+// CreatorScoreStats is the activity on one public score over a period, with
+// its running totals as of the latest snapshot
+type CreatorScoreStats struct {
+	ScoreID uuid.UUID    `json:"score_id"`
+	Title   string       `json:"title"`
+	Period  CreatorStats `json:"period"`
+	Totals  CreatorStats `json:"totals"`
+}
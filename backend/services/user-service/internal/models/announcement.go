@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Announcement is a release note or feature highlight in the what's-new feed
+type Announcement struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	Kind        string    `json:"kind"`
+	LinkURL     *string   `json:"link_url,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	Seen        bool      `json:"seen"`
+}
+
+// AnnouncementInput creates or replaces an announcement (admin). A future
+// published_at schedules it; omitted means now. Past times are rejected.
+type AnnouncementInput struct {
+	Title       string     `json:"title" binding:"required,max=200"`
+	Body        string     `json:"body" binding:"required,max=20000"`
+	Kind        string     `json:"kind" binding:"required,oneof=release feature notice"`
+	LinkURL     string     `json:"link_url" binding:"omitempty,url,max=500"`
+	PublishedAt *time.Time `json:"published_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey represents a public developer API key. The secret itself is never stored.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	KeyPrefix  string     `json:"key_prefix" db:"key_prefix"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// APIKeyCreate represents an API key creation request
+type APIKeyCreate struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	// Scopes defaults to DefaultAPIKeyScopes when omitted
+	Scopes []string `json:"scopes"`
+}
+
+// DefaultAPIKeyScopes are granted to keys created without explicit scopes
+var DefaultAPIKeyScopes = []string{ScopeScoresRead, ScopeTranscriptionsRead, ScopeTranscriptionsWrite}
+
+// APIKeyCreated is returned once on creation and is the only time the key and
+// its request signing secret are shown
+type APIKeyCreated struct {
+	APIKey
+	Key           string `json:"key"`
+	SigningSecret string `json:"signing_secret"`
+}
+
+// APIKeyUsage is one row of an API key usage report
+type APIKeyUsage struct {
+	Day          string `json:"day"`
+	Endpoint     string `json:"endpoint"`
+	RequestCount int    `json:"request_count"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Artist is a performer scores are transcribed from, distinct from the users
+// who upload them. ScoreCount counts public scores only.
+type Artist struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Bio           *string   `json:"bio,omitempty"`
+	ImageURL      *string   `json:"image_url,omitempty"`
+	FollowerCount int       `json:"follower_count"`
+	ScoreCount    int       `json:"score_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ArtistUpdate edits an artist's page (admin)
+type ArtistUpdate struct {
+	Name     string  `json:"name" binding:"required,max=255"`
+	Bio      *string `json:"bio" binding:"omitempty,max=5000"`
+	ImageURL *string `json:"image_url" binding:"omitempty,url,max=500"`
+}
+
+// ArtistMerge folds duplicate artists into the artist in the URL (admin)
+type ArtistMerge struct {
+	DuplicateIDs []uuid.UUID `json:"duplicate_ids" binding:"required,min=1,max=20"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AssessmentQuestion is a multiple-choice placement quiz question. The
+// correct choice is only included in admin responses.
+type AssessmentQuestion struct {
+	ID              uuid.UUID `json:"id"`
+	Prompt          string    `json:"prompt"`
+	Choices         []string  `json:"choices"`
+	CorrectChoice   *int      `json:"correct_choice,omitempty"`
+	DifficultyLevel int       `json:"difficulty_level"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AssessmentQuestionCreate represents an admin-authored quiz question
+type AssessmentQuestionCreate struct {
+	Prompt          string   `json:"prompt" binding:"required,max=2000"`
+	Choices         []string `json:"choices" binding:"required,min=2,max=6,dive,required,max=200"`
+	CorrectChoice   int      `json:"correct_choice" binding:"min=0"`
+	DifficultyLevel int      `json:"difficulty_level" binding:"required,min=1,max=10"`
+}
+
+// QuizAnswer is the chosen option for one quiz question
+type QuizAnswer struct {
+	QuestionID uuid.UUID `json:"question_id" binding:"required"`
+	Choice     int       `json:"choice" binding:"min=0"`
+}
+
+// QuizSubmission represents a completed placement quiz. It must answer every
+// question of the quiz last drawn for the user.
+type QuizSubmission struct {
+	Answers []QuizAnswer `json:"answers" binding:"required,min=1,max=40,dive"`
+}
+
+// AssessmentResult is the outcome of one placement assessment
+type AssessmentResult struct {
+	ID         uuid.UUID `json:"id"`
+	Method     string    `json:"method"`
+	Correct    int       `json:"correct"`
+	Total      int       `json:"total"`
+	SkillLevel int       `json:"skill_level"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Challenge is a time-boxed practice challenge
+type Challenge struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Kind        string     `json:"kind"`
+	ScoreID     *uuid.UUID `json:"score_id,omitempty"`
+	TargetBPM   *int       `json:"target_bpm,omitempty"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	Enrolled    int        `json:"enrolled_count"`
+	Completed   int        `json:"completed_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	// Caller's own enrollment, when listed for a user
+	EnrolledAt  *time.Time `json:"enrolled_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// ChallengeCreate represents a challenge published by an admin or a service
+type ChallengeCreate struct {
+	Title       string     `json:"title" binding:"required,max=200"`
+	Description string     `json:"description" binding:"max=5000"`
+	Kind        string     `json:"kind" binding:"required,oneof=daily weekly"`
+	ScoreID     *uuid.UUID `json:"score_id"`
+	TargetBPM   *int       `json:"target_bpm" binding:"omitempty,min=20,max=400"`
+	StartsAt    time.Time  `json:"starts_at" binding:"required"`
+	EndsAt      time.Time  `json:"ends_at" binding:"required,gtfield=StartsAt"`
+}
+
+// ChallengeSubmission is an attempt at a challenge
+type ChallengeSubmission struct {
+	ID           uuid.UUID  `json:"id"`
+	ChallengeID  uuid.UUID  `json:"challenge_id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Username     string     `json:"username,omitempty"`
+	RecordingURL string     `json:"recording_url"`
+	AchievedBPM  *int       `json:"achieved_bpm,omitempty"`
+	Notes        *string    `json:"notes,omitempty"`
+	Status       string     `json:"status"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// ChallengeSubmit represents a challenge submission request
+type ChallengeSubmit struct {
+	RecordingURL string `json:"recording_url" binding:"required,url,max=500"`
+	AchievedBPM  *int   `json:"achieved_bpm" binding:"omitempty,min=20,max=400"`
+	Notes        string `json:"notes" binding:"max=2000"`
+}
+
+// SubmissionReview is an admin decision on a challenge submission
+type SubmissionReview struct {
+	Decision string `json:"decision" binding:"required,oneof=accept reject"`
+}
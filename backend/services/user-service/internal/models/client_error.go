@@ -0,0 +1,96 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Client error group statuses
+const (
+	ErrorGroupOpen     = "open"
+	ErrorGroupResolved = "resolved"
+	ErrorGroupIgnored  = "ignored"
+)
+
+// Breadcrumb is a client event leading up to an error
+type Breadcrumb struct {
+	Timestamp time.Time `json:"timestamp"`
+	Category  string    `json:"category" binding:"max=50"`
+	Message   string    `json:"message" binding:"max=500"`
+}
+
+// ClientErrorReport is a structured frontend error or crash report
+type ClientErrorReport struct {
+	Type        string       `json:"type" binding:"max=100"`
+	Message     string       `json:"message" binding:"required,max=1000"`
+	Stack       string       `json:"stack" binding:"max=20000"`
+	Release     string       `json:"release" binding:"max=50"`
+	URL         string       `json:"url" binding:"omitempty,url,max=2000"`
+	Fingerprint string       `json:"fingerprint" binding:"omitempty,max=64"`
+	Breadcrumbs []Breadcrumb `json:"breadcrumbs" binding:"max=50,dive"`
+}
+
+// ErrorGroup is a set of client error reports sharing a fingerprint
+type ErrorGroup struct {
+	ID          uuid.UUID `json:"id"`
+	Fingerprint string    `json:"fingerprint"`
+	Type        *string   `json:"type,omitempty"`
+	Message     string    `json:"message"`
+	Release     *string   `json:"release,omitempty"`
+	Status      string    `json:"status"`
+	Occurrences int64     `json:"occurrences"`
+	LastSample  JSONB     `json:"last_sample,omitempty"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// ErrorGroupUpdate sets an error group's triage status (admin)
+type ErrorGroupUpdate struct {
+	Status string `json:"status" binding:"required,oneof=open resolved ignored"`
+}
+
+// fingerprintFrames is how many top stack frames identify an error
+const fingerprintFrames = 5
+
+var (
+	// Line and column numbers shift between builds; digits in messages are usually IDs
+	stackPosition = regexp.MustCompile(`:\d+(:\d+)?\)?$`)
+	messageDigits = regexp.MustCompile(`\d+`)
+	// Bundle hashes such as main.3f2a9c1b.js change every release
+	bundleHash = regexp.MustCompile(`\.[0-9a-f]{8,}\.`)
+)
+
+// ErrorFingerprint groups reports of the same error. A client-supplied
+// fingerprint wins but is hashed in its own namespace, so it can never collide
+// with a computed one and fold reports into another error's group; otherwise
+// it hashes the error type, the message with numbers removed and the top stack
+// frames without positions or bundle hashes.
+func ErrorFingerprint(r ClientErrorReport) string {
+	if r.Fingerprint != "" {
+		sum := sha256.Sum256([]byte("client:" + r.Fingerprint))
+		return hex.EncodeToString(sum[:])
+	}
+
+	parts := []string{r.Type, messageDigits.ReplaceAllString(r.Message, "#")}
+	frames := 0
+	for _, line := range strings.Split(r.Stack, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || frames == fingerprintFrames {
+			continue
+		}
+		if frames == 0 && strings.Contains(line, r.Message) {
+			continue // V8 repeats "Type: message" as the first line
+		}
+		line = stackPosition.ReplaceAllString(line, "")
+		parts = append(parts, bundleHash.ReplaceAllString(line, "."))
+		frames++
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"user-service/internal/encryption"
+)
+
+// EncryptedString is a string column that is encrypted on write and decrypted
+// on read, so handlers can treat PII columns like plain strings
+type EncryptedString string
+
+// Value implements the driver.Valuer interface
+func (s EncryptedString) Value() (driver.Value, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return encryption.Encrypt(string(s))
+}
+
+// Scan implements the sql.Scanner interface
+func (s *EncryptedString) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into EncryptedString", value)
+	}
+
+	plaintext, err := encryption.Decrypt(raw)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plaintext)
+	return nil
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Experiment statuses
+const (
+	ExperimentDraft   = "draft"
+	ExperimentRunning = "running"
+	ExperimentStopped = "stopped"
+)
+
+// ExperimentVariant is one arm of an experiment with its relative weight
+type ExperimentVariant struct {
+	Name   string `json:"name" binding:"required,max=50"`
+	Weight int    `json:"weight" binding:"min=1,max=1000"`
+}
+
+// Experiment is an A/B test. Users are bucketed deterministically, so the
+// same user always gets the same variant while the experiment is unchanged.
+type Experiment struct {
+	ID             uuid.UUID           `json:"id"`
+	Key            string              `json:"key"`
+	Description    *string             `json:"description,omitempty"`
+	Status         string              `json:"status"`
+	Variants       []ExperimentVariant `json:"variants"`
+	TrafficPercent int                 `json:"traffic_percent"`
+	TargetTiers    []string            `json:"target_tiers"`
+	TargetLocales  []string            `json:"target_locales"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// ExperimentInput replaces an experiment's settings (admin)
+type ExperimentInput struct {
+	Description    string              `json:"description" binding:"max=2000"`
+	Status         string              `json:"status" binding:"required,oneof=draft running stopped"`
+	Variants       []ExperimentVariant `json:"variants" binding:"required,min=2,max=10,dive"`
+	TrafficPercent int                 `json:"traffic_percent" binding:"min=0,max=100"`
+	TargetTiers    []string            `json:"target_tiers" binding:"max=5,dive,oneof=free hobbyist professional master enterprise"`
+	TargetLocales  []string            `json:"target_locales" binding:"max=50,dive,min=2,max=35"`
+}
+
+// ExperimentCreate creates an experiment (admin). The key cannot be changed
+// later because it seeds bucketing.
+type ExperimentCreate struct {
+	Key string `json:"key" binding:"required,min=2,max=100"`
+	ExperimentInput
+}
+
+// ValidExperimentKey reports whether key uses only lowercase letters, digits, '-' and '_'
+func ValidExperimentKey(key string) bool {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return key != ""
+}
+
+// Targets reports whether the experiment targets a user on tier in locale.
+// A target locale matches itself and its regional variants ("de" matches "de-AT").
+func (e Experiment) Targets(tier, locale string) bool {
+	if len(e.TargetTiers) > 0 && !containsFold(e.TargetTiers, tier) {
+		return false
+	}
+	if len(e.TargetLocales) == 0 {
+		return true
+	}
+	locale = strings.ToLower(locale)
+	for _, target := range e.TargetLocales {
+		target = strings.ToLower(target)
+		if locale == target || strings.HasPrefix(locale, target+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// Assign returns the user's variant, or false when the user falls outside the
+// experiment's traffic. Traffic and variant buckets are hashed independently,
+// so raising traffic_percent only adds users and never moves existing ones.
+func (e Experiment) Assign(userID string) (string, bool) {
+	if experimentBucket(e.Key, "traffic", userID, 100) >= uint64(e.TrafficPercent) {
+		return "", false
+	}
+
+	total := 0
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+	if total == 0 {
+		return "", false
+	}
+
+	bucket := int(experimentBucket(e.Key, "variant", userID, uint64(total)))
+	for _, v := range e.Variants {
+		if bucket < v.Weight {
+			return v.Name, true
+		}
+		bucket -= v.Weight
+	}
+	return "", false
+}
+
+// experimentBucket hashes the user into one of n buckets for a purpose
+func experimentBucket(key, purpose, userID string, n uint64) uint64 {
+	sum := sha256.Sum256([]byte(key + ":" + purpose + ":" + userID))
+	return binary.BigEndian.Uint64(sum[:8]) % n
+}
+
+func containsFold(values []string, v string) bool {
+	for _, s := range values {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
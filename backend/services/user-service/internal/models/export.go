@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Score export statuses
+const (
+	ExportPending    = "pending"
+	ExportProcessing = "processing"
+	ExportCompleted  = "completed"
+	ExportFailed     = "failed"
+	ExportExpired    = "expired"
+)
+
+// Score export formats. MusicXML and MIDI are stored on scores; ABC is the
+// stored notation or else written from the transcribed notes. PDF and Guitar
+// Pro have no renderer yet.
+const (
+	ExportMusicXML = "musicxml"
+	ExportMIDI     = "midi"
+	ExportABC      = "abc"
+)
+
+// ScoreExportRequest selects scores and formats for a ZIP export
+type ScoreExportRequest struct {
+	ScoreIDs []uuid.UUID `json:"score_ids" binding:"required,min=1,max=100"`
+	Formats  []string    `json:"formats" binding:"required,min=1,max=3,dive,oneof=musicxml midi abc"`
+}
+
+// ExportSkip records a score and format that had nothing to export
+type ExportSkip struct {
+	ScoreID uuid.UUID `json:"score_id"`
+	Format  string    `json:"format"`
+}
+
+// ScoreExport is a bulk export and, once completed, its signed download
+// link. DownloadURL is set when PUBLIC_BASE_URL is configured.
+type ScoreExport struct {
+	ID           uuid.UUID    `json:"id"`
+	Status       string       `json:"status"`
+	ScoreIDs     []string     `json:"score_ids"`
+	Formats      []string     `json:"formats"`
+	SizeBytes    *int64       `json:"size_bytes,omitempty"`
+	Skipped      []ExportSkip `json:"skipped"`
+	ErrorMessage *string      `json:"error_message,omitempty"`
+	DownloadPath string       `json:"download_path,omitempty"`
+	DownloadURL  string       `json:"download_url,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	CompletedAt  *time.Time   `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time   `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Feedback is an in-app feedback report in the triage queue
+type Feedback struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        *uuid.UUID `json:"user_id,omitempty"`
+	Username      *string    `json:"username,omitempty"`
+	Category      string     `json:"category"`
+	Message       string     `json:"message"`
+	ClientVersion *string    `json:"client_version,omitempty"`
+	Platform      *string    `json:"platform,omitempty"`
+	PageURL       *string    `json:"page_url,omitempty"`
+	ScreenshotURL *string    `json:"screenshot_url,omitempty"`
+	Status        string     `json:"status"`
+	Labels        []string   `json:"labels"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// FeedbackSubmit represents feedback sent from the app. Screenshots are
+// uploaded first with an upload credential and referenced by URL.
+type FeedbackSubmit struct {
+	Category      string `json:"category" binding:"required,oneof=bug feature_request content praise other"`
+	Message       string `json:"message" binding:"required,max=5000"`
+	ClientVersion string `json:"client_version" binding:"max=50"`
+	Platform      string `json:"platform" binding:"max=50"`
+	PageURL       string `json:"page_url" binding:"omitempty,url,max=500"`
+	ScreenshotURL string `json:"screenshot_url" binding:"omitempty,url,max=500"`
+}
+
+// FeedbackTriage updates a feedback report's status and labels (admin)
+type FeedbackTriage struct {
+	Status *string  `json:"status" binding:"omitempty,oneof=new triaged planned done wont_fix"`
+	Labels []string `json:"labels" binding:"omitempty,max=10,dive,required,max=50"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImpersonationTTL is how long an impersonation token lasts. It cannot be
+// refreshed.
+const ImpersonationTTL = 15 * time.Minute
+
+// DefaultImpersonationScopes are granted when a request names none, so
+// support staff see the account without being able to change it
+var DefaultImpersonationScopes = []string{
+	ScopeProfileRead,
+	ScopeScoresRead,
+	ScopePracticeRead,
+	ScopeTranscriptionsRead,
+	ScopeBillingRead,
+}
+
+// ImpersonationRequest starts a super admin's session as another user
+type ImpersonationRequest struct {
+	Reason string   `json:"reason" binding:"required,max=2000"`
+	Scopes []string `json:"scopes" binding:"omitempty,max=20"`
+}
+
+// ImpersonationToken is a scope-restricted access token for another user
+type ImpersonationToken struct {
+	UserID      uuid.UUID `json:"user_id"`
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	Scopes      []string  `json:"scopes"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// JamRoomCreate represents a jam room creation request
+type JamRoomCreate struct {
+	Name     string `json:"name" binding:"required,min=1,max=100"`
+	MaxPeers int    `json:"max_peers" binding:"omitempty,min=2,max=8"`
+}
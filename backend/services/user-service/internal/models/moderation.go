@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShadowBan describes a shadow-banned user for moderators
+type ShadowBan struct {
+	UserID   uuid.UUID  `json:"user_id"`
+	Username string     `json:"username"`
+	Reason   *string    `json:"reason,omitempty"`
+	BannedBy *uuid.UUID `json:"banned_by,omitempty"`
+	BannedAt time.Time  `json:"banned_at"`
+}
+
+// ShadowBanCreate represents a request to shadow-ban a user
+type ShadowBanCreate struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+// ContentSearchScore is a score matched by a moderator content search,
+// regardless of visibility
+type ContentSearchScore struct {
+	ID               uuid.UUID `json:"id"`
+	Title            string    `json:"title"`
+	Artist           *string   `json:"artist,omitempty"`
+	Album            *string   `json:"album,omitempty"`
+	Description      *string   `json:"description,omitempty"`
+	OriginalAudioURL *string   `json:"original_audio_url,omitempty"`
+	IsPublic         bool      `json:"is_public"`
+	IsDraft          bool      `json:"is_draft"`
+	OwnerID          uuid.UUID `json:"owner_id"`
+	Owner            string    `json:"owner"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ContentSearchTranscription is a transcription job matched by its input
+// filename or URL in a moderator content search
+type ContentSearchTranscription struct {
+	ID            uuid.UUID  `json:"id"`
+	Status        string     `json:"status"`
+	InputFilename *string    `json:"input_filename,omitempty"`
+	InputURL      *string    `json:"input_url,omitempty"`
+	ScoreID       *uuid.UUID `json:"score_id,omitempty"`
+	OwnerID       uuid.UUID  `json:"owner_id"`
+	Owner         string     `json:"owner"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// LegalHold describes an account under legal hold
+type LegalHold struct {
+	UserID   uuid.UUID  `json:"user_id"`
+	Username string     `json:"username"`
+	IsActive bool       `json:"is_active"`
+	Reason   string     `json:"reason"`
+	PlacedBy *uuid.UUID `json:"placed_by,omitempty"`
+	PlacedAt time.Time  `json:"placed_at"`
+}
+
+// LegalHoldCreate represents a request to place an account under legal hold
+type LegalHoldCreate struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
+
+// LegalHoldRelease represents a request to release a legal hold
+type LegalHoldRelease struct {
+	Reason string `json:"reason" binding:"required,max=2000"`
+}
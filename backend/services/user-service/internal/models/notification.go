@@ -0,0 +1,25 @@
+package models
+
+// Notification delivery channels
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelPush    = "push"
+)
+
+// JobNotificationSettings control how the user hears that a transcription or
+// score export finished. WebhookSecret signs webhook deliveries; it is set
+// whenever WebhookURL is.
+type JobNotificationSettings struct {
+	Email         bool    `json:"email"`
+	WebhookURL    *string `json:"webhook_url"`
+	WebhookSecret string  `json:"webhook_secret,omitempty"`
+}
+
+// JobNotificationSettingsUpdate represents a job notification settings
+// update; omitted fields are unchanged and an empty webhook_url removes the
+// webhook
+type JobNotificationSettingsUpdate struct {
+	Email      *bool   `json:"email"`
+	WebhookURL *string `json:"webhook_url" binding:"omitempty,max=2000"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthClient represents a registered third-party application
+type OAuthClient struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	OwnerID      uuid.UUID `json:"owner_id" db:"owner_id"`
+	Name         string    `json:"name" db:"name"`
+	ClientID     string    `json:"client_id" db:"client_id"`
+	RedirectURIs []string  `json:"redirect_uris" db:"redirect_uris"`
+	Scopes       []string  `json:"scopes" db:"scopes"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// OAuthClientRegistration represents an application registration request
+type OAuthClientRegistration struct {
+	Name         string   `json:"name" binding:"required,min=1,max=100"`
+	RedirectURIs []string `json:"redirect_uris" binding:"required,min=1,max=10,dive,required,max=500"`
+	Scopes       []string `json:"scopes" binding:"required,min=1"`
+}
+
+// OAuthClientUpdate represents an application update request
+type OAuthClientUpdate struct {
+	Name         *string  `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	RedirectURIs []string `json:"redirect_uris,omitempty" binding:"omitempty,min=1,max=10,dive,required,max=500"`
+	Scopes       []string `json:"scopes,omitempty" binding:"omitempty,min=1"`
+}
+
+// OAuthClientCredentials is returned on registration and secret rotation; the
+// secret is shown only this once
+type OAuthClientCredentials struct {
+	OAuthClient
+	ClientSecret string `json:"client_secret"`
+}
+
+// OAuthAuthorizeRequest carries the parameters of an authorization request
+type OAuthAuthorizeRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required,eq=code"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state" binding:"max=500"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge" binding:"required,min=43,max=128"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method" binding:"required,eq=S256"`
+}
+
+// OAuthConsentDecision is the user's answer on the consent screen
+type OAuthConsentDecision struct {
+	OAuthAuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// OAuthTokenResponse is the RFC 6749 token endpoint response
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PracticeReminder is a recurring, timezone-aware practice reminder
+type PracticeReminder struct {
+	ID              uuid.UUID  `json:"id"`
+	Label           string     `json:"label"`
+	DaysOfWeek      []int64    `json:"days_of_week"`
+	TimeOfDay       string     `json:"time_of_day"`
+	Timezone        string     `json:"timezone"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Channels        []string   `json:"channels"`
+	Enabled         bool       `json:"enabled"`
+	NextFireAt      *time.Time `json:"next_fire_at,omitempty"`
+	SnoozedUntil    *time.Time `json:"snoozed_until,omitempty"`
+	SkipUntil       *time.Time `json:"skip_until,omitempty"`
+	LastSentAt      *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// PracticeReminderInput creates or replaces a reminder's schedule
+type PracticeReminderInput struct {
+	Label           string   `json:"label" binding:"required,max=100"`
+	DaysOfWeek      []int64  `json:"days_of_week" binding:"required,min=1,max=7,dive,min=0,max=6"`
+	TimeOfDay       string   `json:"time_of_day" binding:"required,len=5"`
+	Timezone        string   `json:"timezone" binding:"required,max=64"`
+	DurationMinutes int      `json:"duration_minutes" binding:"omitempty,min=5,max=480"`
+	Channels        []string `json:"channels" binding:"omitempty,dive,oneof=push email"`
+	Enabled         *bool    `json:"enabled"`
+}
+
+// ReminderSnooze postpones the next reminder
+type ReminderSnooze struct {
+	Minutes int `json:"minutes" binding:"required,min=5,max=1440"`
+}
+
+// ReminderSchedule is the parsed recurrence of a reminder
+type ReminderSchedule struct {
+	Days     map[time.Weekday]bool
+	Hour     int
+	Minute   int
+	Location *time.Location
+}
+
+// ParseReminderSchedule validates and parses a reminder's recurrence
+func ParseReminderSchedule(days []int64, timeOfDay, timezone string) (*ReminderSchedule, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", timezone)
+	}
+
+	t, err := time.Parse("15:04", timeOfDay)
+	if err != nil {
+		return nil, fmt.Errorf("time_of_day must be HH:MM")
+	}
+
+	s := &ReminderSchedule{Days: map[time.Weekday]bool{}, Hour: t.Hour(), Minute: t.Minute(), Location: loc}
+	for _, d := range days {
+		if d < 0 || d > 6 {
+			return nil, fmt.Errorf("days_of_week must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		s.Days[time.Weekday(d)] = true
+	}
+	if len(s.Days) == 0 {
+		return nil, fmt.Errorf("days_of_week must not be empty")
+	}
+	return s, nil
+}
+
+// Next returns the first occurrence strictly after the given time. Local wall
+// times are used, so reminders follow daylight saving changes.
+func (s *ReminderSchedule) Next(after time.Time) time.Time {
+	local := after.In(s.Location)
+	for i := 0; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		candidate := time.Date(day.Year(), day.Month(), day.Day(), s.Hour, s.Minute, 0, 0, s.Location)
+		if s.Days[candidate.Weekday()] && candidate.After(after) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// NextOutsideSkip returns the first occurrence after the given time that is not
+// before skipUntil, so a skipped week stays skipped once a reminder has fired
+// or been snoozed. A nil or past skipUntil has no effect.
+func (s *ReminderSchedule) NextOutsideSkip(after time.Time, skipUntil *time.Time) time.Time {
+	if skipUntil != nil && skipUntil.After(after) {
+		// Next is exclusive; an occurrence exactly at skipUntil is allowed
+		after = skipUntil.Add(-time.Second)
+	}
+	return s.Next(after)
+}
+
+// StartOfNextWeek returns Monday 00:00 of the week after t, in the schedule's timezone
+func (s *ReminderSchedule) StartOfNextWeek(t time.Time) time.Time {
+	local := t.In(s.Location)
+	daysUntilMonday := (8 - int(local.Weekday())) % 7
+	if daysUntilMonday == 0 {
+		daysUntilMonday = 7
+	}
+	monday := local.AddDate(0, 0, daysUntilMonday)
+	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, s.Location)
+}
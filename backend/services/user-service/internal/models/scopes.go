@@ -0,0 +1,37 @@
+package models
+
+// Token scopes, granted to API keys and third-party applications
+const (
+	ScopeProfileRead         = "profile:read"
+	ScopeProfileWrite        = "profile:write"
+	ScopeScoresRead          = "scores:read"
+	ScopeScoresWrite         = "scores:write"
+	ScopeMediaWrite          = "media:write"
+	ScopePracticeRead        = "practice:read"
+	ScopeTranscriptionsRead  = "transcriptions:read"
+	ScopeTranscriptionsWrite = "transcriptions:write"
+	ScopeBillingRead         = "billing:read"
+)
+
+// AllScopes lists every scope that can be granted
+var AllScopes = []string{
+	ScopeProfileRead,
+	ScopeProfileWrite,
+	ScopeScoresRead,
+	ScopeScoresWrite,
+	ScopeMediaWrite,
+	ScopePracticeRead,
+	ScopeTranscriptionsRead,
+	ScopeTranscriptionsWrite,
+	ScopeBillingRead,
+}
+
+// IsValidScope reports whether scope is a known scope
+func IsValidScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,356 @@
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PublicScore is the public view of a score exposed by the developer API.
+// Locale names the translation the title and description are in, if any.
+type PublicScore struct {
+	ID              uuid.UUID  `json:"id"`
+	Title           string     `json:"title"`
+	Artist          *string    `json:"artist,omitempty"`
+	ArtistID        *uuid.UUID `json:"artist_id,omitempty"`
+	Album           *string    `json:"album,omitempty"`
+	Genre           *string    `json:"genre,omitempty"`
+	Year            *int       `json:"year,omitempty"`
+	DifficultyLevel *int       `json:"difficulty_level,omitempty"`
+	KeySignature    *string    `json:"key_signature,omitempty"`
+	TimeSignature   *string    `json:"time_signature,omitempty"`
+	Tempo           *int       `json:"tempo,omitempty"`
+	Tuning          *string    `json:"tuning,omitempty"`
+	Tags            []string   `json:"tags"`
+	Owner           string     `json:"owner"`
+	Description     *string    `json:"description,omitempty"`
+	Locale          string     `json:"locale,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TranscriptionSubmit represents a transcription job submitted through the public API
+type TranscriptionSubmit struct {
+	InputType string `json:"input_type" binding:"required,oneof=youtube url"`
+	InputURL  string `json:"input_url" binding:"required,url,max=500"`
+	Settings  JSONB  `json:"settings,omitempty"`
+}
+
+// TranscriptionJob is the public view of a transcription job. Stage and
+// StageProgress are set while a worker runs it; QueuePosition on pending jobs
+// only.
+type TranscriptionJob struct {
+	ID            uuid.UUID  `json:"id"`
+	Status        string     `json:"status"`
+	Progress      int        `json:"progress"`
+	Stage         *string    `json:"stage,omitempty"`
+	StageProgress *int       `json:"stage_progress,omitempty"`
+	InputType     string     `json:"input_type"`
+	InputURL      *string    `json:"input_url,omitempty"`
+	ScoreID       *uuid.UUID `json:"score_id,omitempty"`
+	ErrorMessage  *string    `json:"error_message,omitempty"`
+	QueuePosition *int       `json:"queue_position,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// ABCImport creates a score from an ABC tune. Artist defaults to the tune's
+// composer (C:).
+type ABCImport struct {
+	ABC    string  `json:"abc" binding:"required,max=100000"`
+	Artist *string `json:"artist" binding:"omitempty,max=255"`
+}
+
+// Score audio download variants: the untouched upload, or the processed
+// audio the transcription was made from
+const (
+	ScoreDownloadOriginal  = "original"
+	ScoreDownloadProcessed = "processed"
+)
+
+// ScoreVisibility publishes or unpublishes a score
+type ScoreVisibility struct {
+	IsPublic *bool `json:"is_public" binding:"required"`
+}
+
+// Score library sort orders
+const (
+	LibrarySortRecent        = "recent"
+	LibrarySortMostPracticed = "most_practiced"
+	LibrarySortAlphabetical  = "alphabetical"
+)
+
+// LibraryFilters narrows and orders the current user's score library. It binds
+// from GET /scores query parameters; every tag must be present on a score.
+type LibraryFilters struct {
+	Tags          []string   `form:"tag" json:"tags,omitempty" binding:"max=10,dive,max=50"`
+	Artist        string     `form:"artist" json:"artist,omitempty" binding:"max=255"`
+	AlbumID       string     `form:"album_id" json:"album_id,omitempty" binding:"omitempty,uuid"`
+	Genre         string     `form:"genre" json:"genre,omitempty" binding:"max=100"`
+	Tuning        string     `form:"tuning" json:"tuning,omitempty" binding:"max=50"`
+	DifficultyMin *int       `form:"difficulty_min" json:"difficulty_min,omitempty" binding:"omitempty,min=1,max=10"`
+	DifficultyMax *int       `form:"difficulty_max" json:"difficulty_max,omitempty" binding:"omitempty,min=1,max=10"`
+	TempoMin      *int       `form:"tempo_min" json:"tempo_min,omitempty" binding:"omitempty,min=1,max=400"`
+	TempoMax      *int       `form:"tempo_max" json:"tempo_max,omitempty" binding:"omitempty,min=1,max=400"`
+	Visibility    string     `form:"visibility" json:"visibility,omitempty" binding:"omitempty,oneof=public private draft"`
+	CreatedAfter  *time.Time `form:"created_after" json:"created_after,omitempty" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" json:"created_before,omitempty" time_format:"2006-01-02T15:04:05Z07:00"`
+	AddedWithin   *int       `form:"added_within_days" json:"added_within_days,omitempty" binding:"omitempty,min=1,max=3650"`
+	Sort          string     `form:"sort" json:"sort,omitempty" binding:"omitempty,oneof=recent most_practiced alphabetical"`
+}
+
+// LibraryScore is a score as listed in its owner's library
+type LibraryScore struct {
+	ID              uuid.UUID  `json:"id"`
+	Title           string     `json:"title"`
+	Artist          *string    `json:"artist,omitempty"`
+	Album           *string    `json:"album,omitempty"`
+	AlbumID         *uuid.UUID `json:"album_id,omitempty"`
+	Genre           *string    `json:"genre,omitempty"`
+	DifficultyLevel *int       `json:"difficulty_level,omitempty"`
+	KeySignature    *string    `json:"key_signature,omitempty"`
+	Tempo           *int       `json:"tempo,omitempty"`
+	Tuning          *string    `json:"tuning,omitempty"`
+	Tags            []string   `json:"tags"`
+	IsPublic        bool       `json:"is_public"`
+	IsDraft         bool       `json:"is_draft"`
+	PracticeMinutes int        `json:"practice_minutes"`
+	LastPracticedAt *time.Time `json:"last_practiced_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+// TrashedScore is a deleted score that can still be restored until PurgeAt
+type TrashedScore struct {
+	LibraryScore
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// LibraryView is a named, saved set of library filters. Pinned views are
+// returned with the first page of the library.
+type LibraryView struct {
+	ID        uuid.UUID      `json:"id"`
+	Name      string         `json:"name"`
+	Filters   LibraryFilters `json:"filters"`
+	Pinned    bool           `json:"pinned"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// LibraryViewInput creates or replaces a saved library view
+type LibraryViewInput struct {
+	Name    string         `json:"name" binding:"required,max=100"`
+	Filters LibraryFilters `json:"filters"`
+	Pinned  bool           `json:"pinned"`
+}
+
+// SmartPlaylist is a named set of library rules evaluated on every read.
+// MaxScores caps how many of the matching scores, in the rules' sort, it lists.
+type SmartPlaylist struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Rules       LibraryFilters `json:"rules"`
+	MaxScores   int            `json:"max_scores"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// SmartPlaylistInput creates or replaces a smart playlist
+type SmartPlaylistInput struct {
+	Name        string         `json:"name" binding:"required,max=100"`
+	Description *string        `json:"description" binding:"omitempty,max=1000"`
+	Rules       LibraryFilters `json:"rules"`
+	MaxScores   int            `json:"max_scores" binding:"omitempty,min=1,max=500"`
+}
+
+// Reasons two library scores are reported as likely duplicates
+const (
+	DuplicateSameSource   = "same_source"
+	DuplicateSimilarTitle = "similar_title"
+)
+
+// DuplicateGroup is a set of the user's scores that are likely the same song.
+// SuggestedCanonicalID is the most practiced score, oldest first on ties.
+type DuplicateGroup struct {
+	Reasons              []string       `json:"reasons"`
+	SuggestedCanonicalID uuid.UUID      `json:"suggested_canonical_id"`
+	Scores               []LibraryScore `json:"scores"`
+}
+
+// ScoreMerge folds duplicate scores into the canonical score in the URL
+type ScoreMerge struct {
+	DuplicateIDs []uuid.UUID `json:"duplicate_ids" binding:"required,min=1,max=20"`
+}
+
+var (
+	youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	// Bracketed asides such as "(Live)" or "[Official Video]"
+	titleAside    = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]`)
+	titleNonAlnum = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// ScoreSourceKey identifies the audio a score was transcribed from, so two
+// scores from the same recording match however the URL was written. YouTube
+// links reduce to the video ID; other URLs to host and path. It returns ""
+// when the source is unknown.
+func ScoreSourceKey(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+
+	var id string
+	switch host {
+	case "youtube.com", "music.youtube.com":
+		if id = u.Query().Get("v"); id == "" {
+			for _, prefix := range []string{"/shorts/", "/embed/", "/live/"} {
+				if strings.HasPrefix(u.Path, prefix) {
+					id = strings.Trim(strings.TrimPrefix(u.Path, prefix), "/")
+				}
+			}
+		}
+	case "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	}
+	if youTubeID.MatchString(id) {
+		return "youtube:" + id
+	}
+	return host + strings.TrimRight(u.EscapedPath(), "/")
+}
+
+// ScoreTitleKey normalizes a title and artist for near-duplicate matching:
+// case, punctuation and bracketed asides are ignored
+func ScoreTitleKey(title, artist string) string {
+	normalize := func(s string) string {
+		s = titleAside.ReplaceAllString(strings.ToLower(s), " ")
+		return strings.TrimSpace(titleNonAlnum.ReplaceAllString(s, " "))
+	}
+	key := normalize(title)
+	if key == "" {
+		return ""
+	}
+	return key + "|" + normalize(artist)
+}
+
+// Fingering places one transcribed note, by its index in the score's notes,
+// on a string (1 = lowest) and fret counted from the capo
+type Fingering struct {
+	Note   int `json:"note" binding:"min=0"`
+	String int `json:"string" binding:"min=1,max=8"`
+	Fret   int `json:"fret" binding:"min=0,max=24"`
+}
+
+// FingeringUpdate replaces a score's fingerings; notes left out are unfingered
+type FingeringUpdate struct {
+	Fingerings []Fingering `json:"fingerings" binding:"required,max=20000,dive"`
+}
+
+// FingeringSuggest asks for fingerings of the notes starting within a time
+// range, in seconds, or of the whole score. Saved fingerings are kept and
+// planned around unless Overwrite is set.
+type FingeringSuggest struct {
+	StartTime *float64 `json:"start_time" binding:"omitempty,min=0"`
+	EndTime   *float64 `json:"end_time" binding:"omitempty,min=0"`
+	Overwrite bool     `json:"overwrite"`
+}
+
+// ScoreDifficulty is a score's difficulty and how it was arrived at.
+// DifficultyLevel is the override when set, otherwise the estimate.
+type ScoreDifficulty struct {
+	DifficultyLevel     *int            `json:"difficulty_level"`
+	DifficultyEstimated *int            `json:"difficulty_estimated"`
+	DifficultyOverride  *int            `json:"difficulty_override"`
+	Factors             json.RawMessage `json:"factors,omitempty"`
+	EstimatedAt         *time.Time      `json:"estimated_at,omitempty"`
+}
+
+// DifficultyOverrideUpdate sets the creator's difficulty, or clears it with null
+type DifficultyOverrideUpdate struct {
+	DifficultyOverride *int `json:"difficulty_override" binding:"omitempty,min=1,max=10"`
+}
+
+// Score license types
+const (
+	LicenseOriginal     = "original"
+	LicenseCover        = "cover"
+	LicensePublicDomain = "public_domain"
+	LicenseLicensed     = "licensed"
+)
+
+// ScoreLicense is the rights information a score needs before it can be made
+// public. Covers name the original composer; licensed arrangements name the
+// publisher and the license reference.
+type ScoreLicense struct {
+	Type      string     `json:"type" binding:"required,oneof=original cover public_domain licensed"`
+	Composer  *string    `json:"composer,omitempty" binding:"omitempty,max=255"`
+	Publisher *string    `json:"publisher,omitempty" binding:"omitempty,max=255"`
+	Reference *string    `json:"reference,omitempty" binding:"omitempty,max=255"`
+	Notes     *string    `json:"notes,omitempty" binding:"omitempty,max=2000"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// LicensingReportScore is a public score as listed in the admin licensing report
+type LicensingReportScore struct {
+	ID        uuid.UUID     `json:"id"`
+	Title     string        `json:"title"`
+	Artist    *string       `json:"artist,omitempty"`
+	Owner     string        `json:"owner"`
+	License   *ScoreLicense `json:"license"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// TranscriptionBatchSubmit represents several transcription jobs submitted at once.
+// Items are validated individually so one bad source does not reject the batch.
+type TranscriptionBatchSubmit struct {
+	Items []TranscriptionSubmit `json:"items" binding:"required,min=1,max=100"`
+}
+
+// BatchItemError reports a batch item that was rejected before a job was created
+type BatchItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// Aggregate transcription batch statuses
+const (
+	BatchPending         = "pending"
+	BatchProcessing      = "processing"
+	BatchCompleted       = "completed"
+	BatchPartiallyFailed = "partially_failed"
+	BatchFailed          = "failed"
+)
+
+// TranscriptionBatch is a group of transcription jobs with aggregate status
+type TranscriptionBatch struct {
+	ID        uuid.UUID          `json:"id"`
+	Status    string             `json:"status"`
+	Total     int                `json:"total"`
+	Counts    map[string]int     `json:"counts"`
+	Jobs      []TranscriptionJob `json:"jobs"`
+	Rejected  []BatchItemError   `json:"rejected,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// BatchStatus derives a batch's status from the number of jobs in each job status
+func BatchStatus(counts map[string]int) string {
+	switch {
+	case counts["pending"]+counts["processing"] > 0:
+		if counts["processing"]+counts["completed"]+counts["failed"]+counts["cancelled"] > 0 {
+			return BatchProcessing
+		}
+		return BatchPending
+	case counts["failed"]+counts["cancelled"] == 0:
+		return BatchCompleted
+	case counts["completed"] == 0:
+		return BatchFailed
+	default:
+		return BatchPartiallyFailed
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Song request statuses. Requests move requested -> in_progress -> published;
+// duplicates are closed as merged.
+const (
+	SongRequestRequested  = "requested"
+	SongRequestInProgress = "in_progress"
+	SongRequestPublished  = "published"
+	SongRequestMerged     = "merged"
+)
+
+// SongRequest is a community request for a song to be transcribed
+type SongRequest struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Artist      string     `json:"artist"`
+	Notes       *string    `json:"notes,omitempty"`
+	Status      string     `json:"status"`
+	RequestedBy *string    `json:"requested_by,omitempty"`
+	MergedInto  *uuid.UUID `json:"merged_into,omitempty"`
+	ScoreID     *uuid.UUID `json:"score_id,omitempty"`
+	VoteCount   int        `json:"vote_count"`
+	Voted       bool       `json:"voted"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// SongRequestCreate represents a new song request
+type SongRequestCreate struct {
+	Title  string `json:"title" binding:"required,max=255"`
+	Artist string `json:"artist" binding:"required,max=255"`
+	Notes  string `json:"notes" binding:"max=2000"`
+}
+
+// SongRequestStatusUpdate moves a request along its workflow (admin)
+type SongRequestStatusUpdate struct {
+	Status  string     `json:"status" binding:"required,oneof=requested in_progress published"`
+	ScoreID *uuid.UUID `json:"score_id"`
+}
+
+// SongRequestMerge closes a duplicate request into another (admin)
+type SongRequestMerge struct {
+	Into uuid.UUID `json:"into" binding:"required"`
+}
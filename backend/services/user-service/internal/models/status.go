@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusComponents are the user-facing components reported on the status page
+var StatusComponents = []string{"api", "transcription", "playback"}
+
+// Component statuses, from best to worst
+const (
+	ComponentOperational = "operational"
+	ComponentDegraded    = "degraded_performance"
+	ComponentPartial     = "partial_outage"
+	ComponentMajor       = "major_outage"
+)
+
+// componentStatusByImpact maps an incident's impact to the status of the components it affects
+var componentStatusByImpact = map[string]string{
+	"minor":    ComponentDegraded,
+	"major":    ComponentPartial,
+	"critical": ComponentMajor,
+}
+
+var componentSeverity = map[string]int{
+	ComponentOperational: 0,
+	ComponentDegraded:    1,
+	ComponentPartial:     2,
+	ComponentMajor:       3,
+}
+
+// WorseComponentStatus returns the more severe of two component statuses
+func WorseComponentStatus(a, b string) string {
+	if componentSeverity[b] > componentSeverity[a] {
+		return b
+	}
+	return a
+}
+
+// ComponentStatusForImpact returns the component status caused by an incident impact
+func ComponentStatusForImpact(impact string) string {
+	if status, ok := componentStatusByImpact[impact]; ok {
+		return status
+	}
+	return ComponentOperational
+}
+
+// ComponentStatus is the current status of one component
+type ComponentStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// Incident is a service disruption with its timeline of updates
+type Incident struct {
+	ID         uuid.UUID        `json:"id"`
+	Title      string           `json:"title"`
+	Impact     string           `json:"impact"`
+	Status     string           `json:"status"`
+	Components []string         `json:"components"`
+	StartedAt  time.Time        `json:"started_at"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty"`
+	Updates    []IncidentUpdate `json:"updates"`
+}
+
+// IncidentUpdate is one status message posted on an incident
+type IncidentUpdate struct {
+	Status    string    `json:"status"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IncidentCreate opens an incident (admin)
+type IncidentCreate struct {
+	Title      string   `json:"title" binding:"required,max=200"`
+	Impact     string   `json:"impact" binding:"required,oneof=minor major critical"`
+	Components []string `json:"components" binding:"required,min=1,dive,oneof=api transcription playback"`
+	Message    string   `json:"message" binding:"required,max=5000"`
+}
+
+// IncidentUpdateCreate posts an update on an incident (admin). Status
+// "resolved" closes it; impact and components may be revised.
+type IncidentUpdateCreate struct {
+	Status     string   `json:"status" binding:"required,oneof=investigating identified monitoring resolved"`
+	Message    string   `json:"message" binding:"required,max=5000"`
+	Impact     string   `json:"impact" binding:"omitempty,oneof=minor major critical"`
+	Components []string `json:"components" binding:"omitempty,min=1,dive,oneof=api transcription playback"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Support ticket statuses
+const (
+	TicketOpen          = "open"
+	TicketWaitingOnUser = "waiting_on_user"
+	TicketResolved      = "resolved"
+	TicketClosed        = "closed"
+)
+
+// SupportTicket is a user's support request with its SLA timers
+type SupportTicket struct {
+	ID                   uuid.UUID              `json:"id"`
+	UserID               uuid.UUID              `json:"user_id"`
+	Username             string                 `json:"username,omitempty"`
+	Subject              string                 `json:"subject"`
+	Category             string                 `json:"category"`
+	Status               string                 `json:"status"`
+	Priority             string                 `json:"priority"`
+	Tier                 string                 `json:"tier"`
+	AssigneeID           *uuid.UUID             `json:"assignee_id,omitempty"`
+	FirstResponseDueAt   time.Time              `json:"first_response_due_at"`
+	ResolutionDueAt      time.Time              `json:"resolution_due_at"`
+	FirstRespondedAt     *time.Time             `json:"first_responded_at,omitempty"`
+	ResolvedAt           *time.Time             `json:"resolved_at,omitempty"`
+	FirstResponseOverdue bool                   `json:"first_response_overdue"`
+	ResolutionOverdue    bool                   `json:"resolution_overdue"`
+	CreatedAt            time.Time              `json:"created_at"`
+	UpdatedAt            time.Time              `json:"updated_at"`
+	Messages             []SupportTicketMessage `json:"messages,omitempty"`
+}
+
+// SupportTicketMessage is one message in a ticket thread
+type SupportTicketMessage struct {
+	ID             uuid.UUID `json:"id"`
+	IsStaff        bool      `json:"is_staff"`
+	Body           string    `json:"body"`
+	AttachmentURLs []string  `json:"attachment_urls"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SupportTicketCreate represents a new support ticket. Attachments are URLs
+// of files uploaded with an upload credential.
+type SupportTicketCreate struct {
+	Subject        string   `json:"subject" binding:"required,max=200"`
+	Category       string   `json:"category" binding:"required,oneof=account billing transcription bug other"`
+	Body           string   `json:"body" binding:"required,max=10000"`
+	AttachmentURLs []string `json:"attachment_urls" binding:"max=5,dive,url,max=500"`
+}
+
+// SupportTicketReply represents a message added to a ticket
+type SupportTicketReply struct {
+	Body           string   `json:"body" binding:"required,max=10000"`
+	AttachmentURLs []string `json:"attachment_urls" binding:"max=5,dive,url,max=500"`
+}
+
+// SupportTicketUpdate changes a ticket's workflow fields (staff)
+type SupportTicketUpdate struct {
+	Status     *string    `json:"status" binding:"omitempty,oneof=open waiting_on_user resolved closed"`
+	Priority   *string    `json:"priority" binding:"omitempty,oneof=low normal high urgent"`
+	AssigneeID *uuid.UUID `json:"assignee_id"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Client telemetry event types
+const (
+	TelemetryPlaybackStall = "playback_stall"
+	TelemetryRenderTime    = "render_time"
+	TelemetryFeatureUsage  = "feature_usage"
+)
+
+// TelemetryEvent is a single client measurement or usage event
+type TelemetryEvent struct {
+	Type       string            `json:"type" binding:"required,oneof=playback_stall render_time feature_usage"`
+	Timestamp  time.Time         `json:"timestamp" binding:"required"`
+	DurationMS *int              `json:"duration_ms" binding:"omitempty,min=0,max=600000"`
+	ScoreID    string            `json:"score_id" binding:"omitempty,uuid"`
+	Feature    string            `json:"feature" binding:"omitempty,max=100"`
+	Properties map[string]string `json:"properties" binding:"max=20,dive,keys,max=50,endkeys,max=200"`
+}
+
+// Validate checks the fields each event type requires
+func (e TelemetryEvent) Validate() error {
+	switch e.Type {
+	case TelemetryPlaybackStall, TelemetryRenderTime:
+		if e.DurationMS == nil {
+			return errors.New(e.Type + " requires duration_ms")
+		}
+	case TelemetryFeatureUsage:
+		if e.Feature == "" {
+			return errors.New("feature_usage requires feature")
+		}
+	}
+	return nil
+}
+
+// TelemetryBatch is a batch of client telemetry events. Events are validated
+// individually so one bad event does not drop the batch.
+type TelemetryBatch struct {
+	Release  string           `json:"release" binding:"max=50"`
+	Platform string           `json:"platform" binding:"omitempty,oneof=web ios android desktop"`
+	Events   []TelemetryEvent `json:"events" binding:"required,min=1,max=100"`
+}
@@ -0,0 +1,225 @@
+package models
+
+import "fmt"
+
+// TierCapabilities describes what a subscription tier allows. Every per-user
+// limit in this service, including storage, is derived from the user's tier
+// through this table; other services read it through the internal API.
+type TierCapabilities struct {
+	Tier                      string   `json:"tier"`
+	StorageLimitMB            int      `json:"storage_limit_mb"`
+	MaxUploadSizeMB           int      `json:"max_upload_size_mb"`
+	MaxUploadDurationSecs     int      `json:"max_upload_duration_seconds"`
+	RenditionQualities        []string `json:"rendition_qualities"`
+	OriginalDownloads         bool     `json:"original_downloads"`
+	ConcurrentTranscriptions  int      `json:"concurrent_transcriptions"`
+	TranscriptionPriority     int      `json:"transcription_priority"`
+	MaxAPIKeys                int      `json:"max_api_keys"`
+	APIMonthlyQuota           int      `json:"api_monthly_quota"`
+	APIRateLimitPerMinute     int      `json:"api_rate_limit_per_minute"`
+	TrashRetentionDays        int      `json:"trash_retention_days"`
+	MaxBatchTranscriptions    int      `json:"max_batch_transcriptions"`
+	TranscriptionMinutes      int      `json:"monthly_transcription_minutes"`
+	SupportPriority           string   `json:"support_priority"`
+	SupportFirstResponseHours int      `json:"support_first_response_hours"`
+	SupportResolutionHours    int      `json:"support_resolution_hours"`
+}
+
+var tierCapabilities = map[string]TierCapabilities{
+	TierFree: {
+		Tier:                      TierFree,
+		StorageLimitMB:            100,
+		MaxUploadSizeMB:           20,
+		MaxUploadDurationSecs:     5 * 60,
+		RenditionQualities:        []string{"mp3_128"},
+		OriginalDownloads:         false,
+		ConcurrentTranscriptions:  1,
+		TranscriptionPriority:     0,
+		MaxAPIKeys:                1,
+		APIMonthlyQuota:           1000,
+		APIRateLimitPerMinute:     10,
+		TrashRetentionDays:        30,
+		MaxBatchTranscriptions:    0,
+		TranscriptionMinutes:      30,
+		SupportPriority:           "low",
+		SupportFirstResponseHours: 72,
+		SupportResolutionHours:    336,
+	},
+	TierHobbyist: {
+		Tier:                      TierHobbyist,
+		StorageLimitMB:            1000,
+		MaxUploadSizeMB:           50,
+		MaxUploadDurationSecs:     10 * 60,
+		RenditionQualities:        []string{"mp3_128", "mp3_320"},
+		OriginalDownloads:         true,
+		ConcurrentTranscriptions:  2,
+		TranscriptionPriority:     1,
+		MaxAPIKeys:                2,
+		APIMonthlyQuota:           10000,
+		APIRateLimitPerMinute:     30,
+		TrashRetentionDays:        30,
+		MaxBatchTranscriptions:    5,
+		TranscriptionMinutes:      120,
+		SupportPriority:           "normal",
+		SupportFirstResponseHours: 48,
+		SupportResolutionHours:    168,
+	},
+	TierProfessional: {
+		Tier:                      TierProfessional,
+		StorageLimitMB:            5000,
+		MaxUploadSizeMB:           200,
+		MaxUploadDurationSecs:     30 * 60,
+		RenditionQualities:        []string{"mp3_128", "mp3_320", "flac"},
+		OriginalDownloads:         true,
+		ConcurrentTranscriptions:  3,
+		TranscriptionPriority:     2,
+		MaxAPIKeys:                5,
+		APIMonthlyQuota:           100000,
+		APIRateLimitPerMinute:     120,
+		TrashRetentionDays:        60,
+		MaxBatchTranscriptions:    20,
+		TranscriptionMinutes:      600,
+		SupportPriority:           "high",
+		SupportFirstResponseHours: 24,
+		SupportResolutionHours:    72,
+	},
+	TierMaster: {
+		Tier:                      TierMaster,
+		StorageLimitMB:            20000,
+		MaxUploadSizeMB:           500,
+		MaxUploadDurationSecs:     60 * 60,
+		RenditionQualities:        []string{"mp3_128", "mp3_320", "flac", "wav"},
+		OriginalDownloads:         true,
+		ConcurrentTranscriptions:  5,
+		TranscriptionPriority:     3,
+		MaxAPIKeys:                10,
+		APIMonthlyQuota:           500000,
+		APIRateLimitPerMinute:     300,
+		TrashRetentionDays:        90,
+		MaxBatchTranscriptions:    50,
+		TranscriptionMinutes:      2000,
+		SupportPriority:           "high",
+		SupportFirstResponseHours: 12,
+		SupportResolutionHours:    48,
+	},
+	TierEnterprise: {
+		Tier:                      TierEnterprise,
+		StorageLimitMB:            999999, // Unlimited
+		MaxUploadSizeMB:           2000,
+		MaxUploadDurationSecs:     3 * 60 * 60,
+		RenditionQualities:        []string{"mp3_128", "mp3_320", "flac", "wav"},
+		OriginalDownloads:         true,
+		ConcurrentTranscriptions:  10,
+		TranscriptionPriority:     4,
+		MaxAPIKeys:                50,
+		APIMonthlyQuota:           5000000,
+		APIRateLimitPerMinute:     1200,
+		TrashRetentionDays:        180,
+		MaxBatchTranscriptions:    100,
+		TranscriptionMinutes:      20000,
+		SupportPriority:           "urgent",
+		SupportFirstResponseHours: 4,
+		SupportResolutionHours:    24,
+	},
+}
+
+// GetTierCapabilities returns the capabilities of a tier, defaulting to free
+func GetTierCapabilities(tier string) TierCapabilities {
+	if caps, ok := tierCapabilities[tier]; ok {
+		return caps
+	}
+	return tierCapabilities[TierFree]
+}
+
+// AllTierCapabilities returns the capabilities of every tier in ascending order
+func AllTierCapabilities() []TierCapabilities {
+	return []TierCapabilities{
+		tierCapabilities[TierFree],
+		tierCapabilities[TierHobbyist],
+		tierCapabilities[TierProfessional],
+		tierCapabilities[TierMaster],
+		tierCapabilities[TierEnterprise],
+	}
+}
+
+// Quota error codes
+const (
+	QuotaStorage        = "storage_quota_exceeded"
+	QuotaUploadSize     = "upload_size_exceeded"
+	QuotaUploadDuration = "upload_duration_exceeded"
+	QuotaQuality        = "quality_not_available"
+	QuotaOriginal       = "original_download_not_available"
+	QuotaAPIKeys        = "api_key_limit_reached"
+	QuotaAPIMonthly     = "api_monthly_quota_exceeded"
+	QuotaAPIRate        = "api_rate_limit_exceeded"
+	QuotaBatchSize      = "batch_size_exceeded"
+)
+
+// QuotaError is a structured error returned when a request exceeds tier limits
+type QuotaError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Tier      string `json:"tier"`
+	Limit     int    `json:"limit,omitempty"`
+	Requested int    `json:"requested,omitempty"`
+}
+
+func (e *QuotaError) Error() string {
+	return e.Message
+}
+
+// UploadRequest describes an upload to be checked against tier limits
+type UploadRequest struct {
+	SizeMB          int    `json:"size_mb" binding:"min=0"`
+	DurationSeconds int    `json:"duration_seconds" binding:"min=0"`
+	Quality         string `json:"quality,omitempty"`
+}
+
+// CheckUpload validates an upload against the tier's limits and the user's
+// remaining storage, returning a QuotaError describing the first violation
+func (caps TierCapabilities) CheckUpload(req UploadRequest, storageUsedMB int) *QuotaError {
+	if req.SizeMB > caps.MaxUploadSizeMB {
+		return &QuotaError{
+			Code:      QuotaUploadSize,
+			Message:   fmt.Sprintf("Files on the %s plan are limited to %d MB", caps.Tier, caps.MaxUploadSizeMB),
+			Tier:      caps.Tier,
+			Limit:     caps.MaxUploadSizeMB,
+			Requested: req.SizeMB,
+		}
+	}
+
+	if req.DurationSeconds > caps.MaxUploadDurationSecs {
+		return &QuotaError{
+			Code:      QuotaUploadDuration,
+			Message:   fmt.Sprintf("Audio on the %s plan is limited to %d minutes", caps.Tier, caps.MaxUploadDurationSecs/60),
+			Tier:      caps.Tier,
+			Limit:     caps.MaxUploadDurationSecs,
+			Requested: req.DurationSeconds,
+		}
+	}
+
+	if storageUsedMB+req.SizeMB > caps.StorageLimitMB {
+		return &QuotaError{
+			Code:      QuotaStorage,
+			Message:   "Not enough storage remaining",
+			Tier:      caps.Tier,
+			Limit:     caps.StorageLimitMB,
+			Requested: storageUsedMB + req.SizeMB,
+		}
+	}
+
+	if req.Quality != "" {
+		for _, q := range caps.RenditionQualities {
+			if q == req.Quality {
+				return nil
+			}
+		}
+		return &QuotaError{
+			Code:    QuotaQuality,
+			Message: fmt.Sprintf("Quality %s is not available on the %s plan", req.Quality, caps.Tier),
+			Tier:    caps.Tier,
+		}
+	}
+
+	return nil
+}
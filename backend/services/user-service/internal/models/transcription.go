@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transcription job statuses
+const (
+	TranscriptionPending    = "pending"
+	TranscriptionProcessing = "processing"
+	TranscriptionCompleted  = "completed"
+	TranscriptionFailed     = "failed"
+	TranscriptionCancelled  = "cancelled"
+)
+
+// ClaimedTranscriptionJob is what a worker needs to run a job it claimed.
+// Attempt counts from 1.
+type ClaimedTranscriptionJob struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"user_id"`
+	InputType     string    `json:"input_type"`
+	InputURL      *string   `json:"input_url,omitempty"`
+	InputFilename *string   `json:"input_filename,omitempty"`
+	InputMetadata JSONB     `json:"input_metadata"`
+	Settings      JSONB     `json:"settings"`
+	AIModel       *string   `json:"ai_model,omitempty"`
+	Priority      int       `json:"priority"`
+	Attempt       int       `json:"attempt"`
+	CreatedAt     time.Time `json:"created_at"`
+	StartedAt     time.Time `json:"started_at"`
+}
+
+// Worker reports echo the Attempt of the claim they belong to, so a worker
+// that lost a job cannot update a later attempt at it.
+
+// TranscriptionComplete is a worker's report that a job finished
+type TranscriptionComplete struct {
+	Attempt    int        `json:"attempt" binding:"required,min=1"`
+	ScoreID    *uuid.UUID `json:"score_id"`
+	ResultData JSONB      `json:"result_data"`
+}
+
+// TranscriptionProgress is a worker's report of how far a job has got
+type TranscriptionProgress struct {
+	Attempt int    `json:"attempt" binding:"required,min=1"`
+	Stage   string `json:"stage" binding:"required,oneof=downloading separating transcribing rendering"`
+	Percent *int   `json:"percent" binding:"required,min=0,max=100"`
+}
+
+// TranscriptionHeartbeat is a worker's report that it is still running a job
+type TranscriptionHeartbeat struct {
+	Attempt int `json:"attempt" binding:"required,min=1"`
+}
+
+// TranscriptionFailure is a worker's report that a job failed
+type TranscriptionFailure struct {
+	Attempt      int    `json:"attempt" binding:"required,min=1"`
+	FailureClass string `json:"failure_class" binding:"required,oneof=transient worker_crash bad_audio unsupported_format"`
+	ErrorMessage string `json:"error_message" binding:"required,max=2000"`
+	ErrorDetails JSONB  `json:"error_details"`
+}
+
+// DeadLetteredTranscriptionJob is the admin view of a job that failed for good
+type DeadLetteredTranscriptionJob struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Username       string     `json:"username"`
+	BatchID        *uuid.UUID `json:"batch_id,omitempty"`
+	InputType      string     `json:"input_type"`
+	InputURL       *string    `json:"input_url,omitempty"`
+	Attempts       int        `json:"attempts"`
+	FailureClass   *string    `json:"failure_class"`
+	ErrorMessage   *string    `json:"error_message"`
+	ErrorDetails   JSONB      `json:"error_details"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeadLetteredAt time.Time  `json:"dead_lettered_at"`
+}
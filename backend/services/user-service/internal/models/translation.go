@@ -0,0 +1,91 @@
+package models
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxRequestLocales bounds how many locales a request is matched against
+const maxRequestLocales = 10
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// ScoreTranslation is a score's title and description in one locale
+type ScoreTranslation struct {
+	ScoreID     uuid.UUID `json:"score_id"`
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ScoreTranslationInput creates or replaces a translation
+type ScoreTranslationInput struct {
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=5000"`
+}
+
+// NormalizeLocale lowercases a BCP 47 language tag ("pt_BR" becomes "pt-br")
+// and reports whether it is well formed
+func NormalizeLocale(tag string) (string, bool) {
+	locale := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	return locale, len(locale) <= 35 && localePattern.MatchString(locale)
+}
+
+// RequestLocales orders the locales of an Accept-Language header by
+// preference, each followed by its base language when that is not listed
+// itself: "pt-BR,en;q=0.5" gives [pt-br pt en]. Wildcards, malformed tags and
+// tags with q=0 are dropped.
+func RequestLocales(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale, ok := NormalizeLocale(fields[0])
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value := strings.TrimSpace(param); strings.HasPrefix(value, "q=") {
+				if parsed, err := strconv.ParseFloat(value[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{locale, q})
+		}
+	}
+	sort.SliceStable(tags, func(a, b int) bool { return tags[a].q > tags[b].q })
+
+	listed := map[string]bool{}
+	for _, t := range tags {
+		listed[t.locale] = true
+	}
+	var locales []string
+	seen := map[string]bool{}
+	add := func(locale string) {
+		if !seen[locale] && len(locales) < maxRequestLocales {
+			seen[locale] = true
+			locales = append(locales, locale)
+		}
+	}
+	for _, t := range tags {
+		add(t.locale)
+		// A base language listed on its own keeps its own place in the order
+		if base := strings.SplitN(t.locale, "-", 2)[0]; !listed[base] {
+			add(base)
+		}
+	}
+	return locales
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AllowedUploadContentTypes lists the media types accepted for direct uploads
+var AllowedUploadContentTypes = []string{
+	"audio/mpeg",
+	"audio/wav",
+	"audio/x-wav",
+	"audio/flac",
+	"audio/ogg",
+	"audio/mp4",
+	"audio/midi",
+	"application/pdf",
+	"application/vnd.recordare.musicxml+xml",
+	"image/png", // screenshots for feedback and support tickets
+	"image/jpeg",
+}
+
+// IsAllowedUploadContentType reports whether contentType may be uploaded
+func IsAllowedUploadContentType(contentType string) bool {
+	for _, t := range AllowedUploadContentTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadCredentialRequest declares the file a browser is about to upload
+type UploadCredentialRequest struct {
+	Filename        string `json:"filename" binding:"required,max=255"`
+	ContentType     string `json:"content_type" binding:"required"`
+	SizeBytes       int64  `json:"size_bytes" binding:"required,min=1"`
+	DurationSeconds int    `json:"duration_seconds" binding:"min=0"`
+}
+
+// UploadCredential is a single-use, time-limited token bound to one declared file
+type UploadCredential struct {
+	UploadToken string    `json:"upload_token"`
+	UploadURL   string    `json:"upload_url,omitempty"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// UploadGrant is what an upload token authorizes; storage redeems it exactly once
+type UploadGrant struct {
+	UploadID    uuid.UUID `json:"upload_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// UploadRedeemRequest is sent by the storage service when the upload arrives
+type UploadRedeemRequest struct {
+	UploadToken string `json:"upload_token" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	SizeBytes   int64  `json:"size_bytes" binding:"required,min=1"`
+}
@@ -0,0 +1,61 @@
+package models
+
+// Usage metrics tracked against tier quotas
+const (
+	UsageStorage              = "storage"
+	UsageTranscriptionMinutes = "transcription_minutes"
+)
+
+// UsageThresholds are the quota percentages users are alerted at
+var UsageThresholds = []int{80, 95, 100}
+
+// UsageHysteresisPercent is how far usage must fall below an alerted threshold
+// before that threshold can alert again
+const UsageHysteresisPercent = 5
+
+// UsageMeter reports current usage of a quota
+type UsageMeter struct {
+	Metric  string `json:"metric"`
+	Used    int    `json:"used"`
+	Limit   int    `json:"limit"`
+	Unit    string `json:"unit"`
+	Percent int    `json:"percent"`
+}
+
+// NewUsageMeter builds a meter, computing the percentage of the limit used
+func NewUsageMeter(metric, unit string, used, limit int) UsageMeter {
+	return UsageMeter{Metric: metric, Used: used, Limit: limit, Unit: unit, Percent: usagePercent(used, limit)}
+}
+
+func usagePercent(used, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+	return used * 100 / limit
+}
+
+// UsageLevel returns the highest threshold reached at percent, or 0
+func UsageLevel(percent int) int {
+	level := 0
+	for _, t := range UsageThresholds {
+		if percent >= t {
+			level = t
+		}
+	}
+	return level
+}
+
+// NextUsageLevel returns the alert level to store for a metric given the
+// previously stored level and current usage. Levels rise as soon as a
+// threshold is reached but only fall once usage drops UsageHysteresisPercent
+// below them. alert is true when a higher threshold was reached.
+func NextUsageLevel(previous, percent int) (level int, alert bool) {
+	current := UsageLevel(percent)
+	if current > previous {
+		return current, true
+	}
+	if rearmed := UsageLevel(percent + UsageHysteresisPercent); rearmed < previous {
+		return rearmed, false
+	}
+	return previous, false
+}
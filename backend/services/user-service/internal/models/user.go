@@ -2,6 +2,8 @@ package models
 
 import (
 	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,10 +25,14 @@ type User struct {
 	EmailVerifiedAt      *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
 	LastLoginAt          *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
 	IsActive             bool       `json:"is_active" db:"is_active"`
+	Role                 string     `json:"-" db:"role"`
 	SubscriptionTier     string     `json:"subscription_tier" db:"subscription_tier"`
 	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at,omitempty" db:"subscription_expires_at"`
 	StorageUsedMB        int        `json:"storage_used_mb" db:"storage_used_mb"`
 	StorageLimitMB       int        `json:"storage_limit_mb" db:"storage_limit_mb"`
+	Verified             bool       `json:"verified" db:"verified"`
+	VerifiedBadge        *string    `json:"verified_badge,omitempty" db:"verified_badge"`
+	SkillLevel           *int       `json:"skill_level,omitempty" db:"skill_level"`
 	Preferences          JSONB      `json:"preferences" db:"preferences"`
 	Metadata             JSONB      `json:"metadata" db:"metadata"`
 }
@@ -49,7 +55,10 @@ type JSONB map[string]interface{}
 
 // Value implements the driver.Valuer interface
 func (j JSONB) Value() (driver.Value, error) {
-	return j, nil
+	if j == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(j)
 }
 
 // Scan implements the sql.Scanner interface
@@ -58,7 +67,22 @@ func (j *JSONB) Scan(value interface{}) error {
 		*j = make(JSONB)
 		return nil
 	}
-	*j = value.(JSONB)
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONB", value)
+	}
+
+	result := make(JSONB)
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*j = result
 	return nil
 }
 
@@ -86,6 +110,19 @@ type UserUpdate struct {
 	AvatarURL *string `json:"avatar_url,omitempty" binding:"omitempty,url"`
 }
 
+// EmailChange represents an email change request
+type EmailChange struct {
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// IdentityChange is one entry of a user's username or email history
+type IdentityChange struct {
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
 // PasswordChange represents a password change request
 type PasswordChange struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
@@ -94,8 +131,8 @@ type PasswordChange struct {
 
 // TokenResponse represents the authentication token response
 type TokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int    `json:"expires_in"`
 	User         *User  `json:"user,omitempty"`
@@ -131,23 +168,12 @@ const (
 	TierEnterprise   = "enterprise"
 )
 
-// GetStorageLimit returns the storage limit based on subscription tier
-func GetStorageLimit(tier string) int {
-	switch tier {
-	case TierFree:
-		return 100 // 100 MB
-	case TierHobbyist:
-		return 1000 // 1 GB
-	case TierProfessional:
-		return 5000 // 5 GB
-	case TierMaster:
-		return 20000 // 20 GB
-	case TierEnterprise:
-		return 999999 // Unlimited
-	default:
-		return 100
-	}
-}
+// User roles
+const (
+	RoleUser       = "user"
+	RoleAdmin      = "admin"
+	RoleSuperAdmin = "super_admin"
+)
 
 // UserProfile represents the public user profile
 type UserProfile struct {
@@ -158,6 +184,8 @@ type UserProfile struct {
 	AvatarURL        *string   `json:"avatar_url,omitempty"`
 	Bio              *string   `json:"bio,omitempty"`
 	SubscriptionTier string    `json:"subscription_tier"`
+	Verified         bool      `json:"verified"`
+	VerifiedBadge    *string   `json:"verified_badge,omitempty"`
 	JoinedAt         time.Time `json:"joined_at"`
 }
 
@@ -171,6 +199,20 @@ func (u *User) ToProfile() *UserProfile {
 		AvatarURL:        u.AvatarURL,
 		Bio:              u.Bio,
 		SubscriptionTier: u.SubscriptionTier,
+		Verified:         u.Verified,
+		VerifiedBadge:    u.VerifiedBadge,
 		JoinedAt:         u.CreatedAt,
 	}
-}
\ No newline at end of file
+}
+
+// PrivacySettings are the user's privacy choices
+type PrivacySettings struct {
+	LeaderboardOptOut bool `json:"leaderboard_opt_out"`
+	AnalyticsOptOut   bool `json:"analytics_opt_out"`
+}
+
+// PrivacySettingsUpdate represents a privacy settings update; omitted fields are unchanged
+type PrivacySettingsUpdate struct {
+	LeaderboardOptOut *bool `json:"leaderboard_opt_out"`
+	AnalyticsOptOut   *bool `json:"analytics_opt_out"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReservedUsername is a username that can only be taken by the account it is granted to
+type ReservedUsername struct {
+	Username  string     `json:"username"`
+	Reason    *string    `json:"reason,omitempty"`
+	GrantedTo *uuid.UUID `json:"granted_to,omitempty"`
+	GrantedAt *time.Time `json:"granted_at,omitempty"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ReservedUsernameCreate represents a request to reserve a username
+type ReservedUsernameCreate struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Reason   string `json:"reason" binding:"max=255"`
+}
+
+// ReservedUsernameGrant assigns a reserved username to an account
+type ReservedUsernameGrant struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
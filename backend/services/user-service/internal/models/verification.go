@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Verification badge types
+const (
+	BadgeArtist     = "artist"
+	BadgeInstructor = "instructor"
+)
+
+// Verification request statuses
+const (
+	VerificationPending  = "pending"
+	VerificationApproved = "approved"
+	VerificationRejected = "rejected"
+)
+
+// VerificationRequest is a user's request for a verified badge
+type VerificationRequest struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	Username     string     `json:"username,omitempty"`
+	BadgeType    string     `json:"badge_type"`
+	EvidenceURLs []string   `json:"evidence_urls"`
+	Note         *string    `json:"note,omitempty"`
+	Status       string     `json:"status"`
+	ReviewNote   *string    `json:"review_note,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// VerificationSubmit represents a verification request with supporting evidence
+type VerificationSubmit struct {
+	BadgeType    string   `json:"badge_type" binding:"required,oneof=artist instructor"`
+	EvidenceURLs []string `json:"evidence_urls" binding:"required,min=1,max=10,dive,url,max=500"`
+	Note         string   `json:"note" binding:"max=2000"`
+}
+
+// VerificationReview is an admin decision on a verification request
+type VerificationReview struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Note     string `json:"note" binding:"max=2000"`
+}
@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/encryption"
+	"user-service/internal/models"
+)
+
+// QueueKey is the Redis list notification-service consumes
+const QueueKey = "notifications:outbound"
+
+// Notification types
+const (
+	TypeTranscriptionCompleted      = "transcription_completed"
+	TypeScoreExportCompleted        = "score_export_completed"
+	TypePracticeReminder            = "practice_reminder"
+	TypeChallengeCompleted          = "challenge_completed"
+	TypeSongRequestPublished        = "song_request_published"
+	TypeTranscriptionBatchCompleted = "transcription_batch_completed"
+	TypeSupportReply                = "support_reply"
+	TypeUsageThreshold              = "usage_threshold"
+)
+
+// Notification is a message for notification-service to deliver to a user
+type Notification struct {
+	Type     string                 `json:"type"`
+	UserID   string                 `json:"user_id"`
+	Channels []string               `json:"channels"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Webhook  *Webhook               `json:"webhook,omitempty"`
+	QueuedAt time.Time              `json:"queued_at"`
+}
+
+// Webhook is where notification-service POSTs a webhook-channel
+// notification, and the secret it signs the body with
+type Webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// Enqueue hands a notification to notification-service
+func Enqueue(ctx context.Context, n Notification) error {
+	n.QueuedAt = time.Now().UTC()
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return database.GetRedis().LPush(ctx, QueueKey, payload).Err()
+}
+
+// Link returns an absolute link to a path in the web app
+func Link(path string) string {
+	base := "http://localhost:5173"
+	if configured := os.Getenv("WEB_APP_URL"); configured != "" {
+		base = strings.TrimRight(configured, "/")
+	}
+	return base + path
+}
+
+// EnqueueJobFinished sends a job completion notification over the channels
+// the user chose in their job notification settings, if any
+func EnqueueJobFinished(ctx context.Context, db *sql.DB, n Notification) error {
+	var email bool
+	var webhookURL, webhookSecret sql.NullString
+	err := db.QueryRowContext(ctx,
+		"SELECT job_notify_email, job_webhook_url, job_webhook_secret FROM users WHERE id = $1", n.UserID,
+	).Scan(&email, &webhookURL, &webhookSecret)
+	if err != nil {
+		return err
+	}
+
+	n.Channels = nil
+	if email {
+		n.Channels = append(n.Channels, models.ChannelEmail)
+	}
+	if webhookURL.Valid && webhookSecret.Valid {
+		secret, err := encryption.Decrypt(webhookSecret.String)
+		if err != nil {
+			return err
+		}
+		n.Channels = append(n.Channels, models.ChannelWebhook)
+		n.Webhook = &Webhook{URL: webhookURL.String, Secret: secret}
+	}
+	if len(n.Channels) == 0 {
+		return nil
+	}
+	return Enqueue(ctx, n)
+}
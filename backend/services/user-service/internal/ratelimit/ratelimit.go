@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Result describes the state of a counter after a request was counted
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// Allow counts a request against a fixed window and reports whether it is
+// within the limit. Windows are aligned to the window size so all instances
+// share the same counter.
+func Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	now := time.Now()
+	windowStart := now.Truncate(window)
+	resetAt := windowStart.Add(window)
+	counterKey := "ratelimit:" + key + ":" + windowStart.Format("20060102150405")
+
+	return count(ctx, counterKey, limit, resetAt)
+}
+
+// AllowMonthly counts a request against a calendar-month quota (UTC)
+func AllowMonthly(ctx context.Context, key string, limit int) (Result, error) {
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	resetAt := monthStart.AddDate(0, 1, 0)
+	counterKey := "quota:" + key + ":" + monthStart.Format("200601")
+
+	return count(ctx, counterKey, limit, resetAt)
+}
+
+// MonthlyUsage returns how many requests were counted this month without counting one
+func MonthlyUsage(ctx context.Context, key string) (int, error) {
+	now := time.Now().UTC()
+	counterKey := "quota:" + key + ":" + now.Format("200601")
+
+	n, err := database.GetRedis().Get(ctx, counterKey).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return n, nil
+}
+
+func count(ctx context.Context, counterKey string, limit int, resetAt time.Time) (Result, error) {
+	rdb := database.GetRedis()
+
+	pipe := rdb.TxPipeline()
+	incr := pipe.Incr(ctx, counterKey)
+	pipe.ExpireAt(ctx, counterKey, resetAt.Add(time.Minute))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return Result{}, err
+	}
+
+	n := int(incr.Val())
+	remaining := limit - n
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return Result{
+		Allowed:   n <= limit,
+		Limit:     limit,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}, nil
+}
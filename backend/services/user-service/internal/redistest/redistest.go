@@ -0,0 +1,294 @@
+// Package redistest runs an in-memory stand-in for Redis so packages that go
+// through database.GetRedis can be tested without a server. It speaks RESP2
+// and implements only the commands and Lua scripts this service issues.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"user-service/internal/database"
+)
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Server is an in-memory Redis listening on a loopback port
+type Server struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	data map[string]entry
+}
+
+// Start runs a Server for the duration of the test and points the shared
+// client returned by database.GetRedis at it
+func Start(t testing.TB) *Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("redistest: listen: %v", err)
+	}
+	s := &Server{ln: ln, data: make(map[string]entry)}
+	go s.serve()
+
+	t.Setenv("REDIS_URL", "redis://"+ln.Addr().String()+"/0")
+	if err := database.InitRedis(); err != nil {
+		ln.Close()
+		t.Fatalf("redistest: %v", err)
+	}
+	t.Cleanup(func() {
+		database.CloseRedis()
+		ln.Close()
+	})
+	return s
+}
+
+// Get returns the value stored at key, if it exists and has not expired
+func (s *Server) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.lookup(key)
+	return e.value, ok
+}
+
+// Set stores a value without expiry, as another client would
+func (s *Server) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = entry{value: value}
+}
+
+// Del removes a key, as if it had expired
+func (s *Server) Del(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+}
+
+func (s *Server) lookup(key string) (entry, bool) {
+	e, ok := s.data[key]
+	if ok && !e.expiresAt.IsZero() && !time.Now().Before(e.expiresAt) {
+		delete(s.data, key)
+		return entry{}, false
+	}
+	return e, ok
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, s.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("expected array, got %q", line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "$") {
+			return nil, fmt.Errorf("expected bulk string, got %q", line)
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (s *Server) exec(args []string) string {
+	if len(args) == 0 {
+		return "-ERR empty command\r\n"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "HELLO":
+		// Clients fall back to RESP2 when HELLO is unknown
+		return "-ERR unknown command 'HELLO'\r\n"
+	case "CLIENT":
+		return "+OK\r\n"
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		if len(args) != 2 {
+			return wrongArgs(args[0])
+		}
+		if e, ok := s.lookup(args[1]); ok {
+			return bulk(e.value)
+		}
+		return "$-1\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.lookup(key); ok {
+				delete(s.data, key)
+				deleted++
+			}
+		}
+		return integer(deleted)
+	case "SETNX":
+		if len(args) != 3 {
+			return wrongArgs(args[0])
+		}
+		if _, ok := s.lookup(args[1]); ok {
+			return integer(0)
+		}
+		s.data[args[1]] = entry{value: args[2]}
+		return integer(1)
+	case "SET":
+		return s.set(args)
+	case "EVALSHA":
+		return "-NOSCRIPT No matching script. Please use EVAL.\r\n"
+	case "EVAL":
+		return s.eval(args)
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+// set handles SET key value [EX seconds | PX milliseconds] [NX]
+func (s *Server) set(args []string) string {
+	if len(args) < 3 {
+		return wrongArgs(args[0])
+	}
+
+	var ttl time.Duration
+	nx := false
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return "-ERR syntax error\r\n"
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				return "-ERR invalid expire time in 'set' command\r\n"
+			}
+			if strings.ToUpper(args[i]) == "EX" {
+				ttl = time.Duration(n) * time.Second
+			} else {
+				ttl = time.Duration(n) * time.Millisecond
+			}
+			i++
+		default:
+			return "-ERR syntax error\r\n"
+		}
+	}
+
+	if _, ok := s.lookup(args[1]); ok && nx {
+		return "$-1\r\n"
+	}
+	e := entry{value: args[2]}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	s.data[args[1]] = e
+	return "+OK\r\n"
+}
+
+// eval runs the compare-and-delete and compare-and-expire scripts used by the
+// lock package: both act on KEYS[1] only while it still holds ARGV[1]
+func (s *Server) eval(args []string) string {
+	if len(args) < 3 {
+		return wrongArgs(args[0])
+	}
+	script := args[1]
+	numKeys, err := strconv.Atoi(args[2])
+	if err != nil || numKeys != 1 || len(args) < 5 {
+		return "-ERR unsupported script arguments\r\n"
+	}
+	key, token := args[3], args[4]
+
+	e, ok := s.lookup(key)
+	if !ok || e.value != token {
+		return integer(0)
+	}
+
+	switch {
+	case strings.Contains(script, `redis.call("DEL"`):
+		delete(s.data, key)
+		return integer(1)
+	case strings.Contains(script, `redis.call("PEXPIRE"`):
+		if len(args) < 6 {
+			return "-ERR unsupported script arguments\r\n"
+		}
+		ms, err := strconv.ParseInt(args[5], 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		e.expiresAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		s.data[key] = e
+		return integer(1)
+	}
+	return "-ERR unsupported script\r\n"
+}
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+func integer(n int) string {
+	return ":" + strconv.Itoa(n) + "\r\n"
+}
+
+func wrongArgs(cmd string) string {
+	return fmt.Sprintf("-ERR wrong number of arguments for '%s' command\r\n", strings.ToLower(cmd))
+}
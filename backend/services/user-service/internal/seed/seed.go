@@ -0,0 +1,152 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"time"
+	"user-service/internal/models"
+	"user-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// DefaultPassword is the password of every seeded user
+const DefaultPassword = "password123"
+
+// Options controls how much data is generated
+type Options struct {
+	Users         int
+	ScoresPerUser int
+	Seed          int64
+}
+
+// Result reports what was inserted
+type Result struct {
+	Users    int `json:"users"`
+	Scores   int `json:"scores"`
+	Progress int `json:"progress"`
+}
+
+var songs = []struct {
+	title, artist, album, genre string
+	year, tempo, difficulty     int
+	key, tuning                 string
+	tags                        []string
+}{
+	{"Stairway to Heaven", "Led Zeppelin", "Led Zeppelin IV", "rock", 1971, 72, 6, "Am", "standard", []string{"classic", "fingerpicking"}},
+	{"Hotel California", "Eagles", "Hotel California", "rock", 1976, 74, 7, "Bm", "standard", []string{"classic", "solo"}},
+	{"Sultans of Swing", "Dire Straits", "Dire Straits", "rock", 1978, 148, 8, "Dm", "standard", []string{"fingerstyle", "solo"}},
+	{"Smoke on the Water", "Deep Purple", "Machine Head", "rock", 1972, 112, 2, "Gm", "standard", []string{"riff", "beginner"}},
+	{"Little Wing", "Jimi Hendrix", "Axis: Bold as Love", "blues", 1967, 70, 8, "Em", "eb", []string{"blues", "chord-melody"}},
+	{"Comfortably Numb", "Pink Floyd", "The Wall", "rock", 1979, 64, 7, "Bm", "standard", []string{"solo", "bends"}},
+	{"Layla", "Derek and the Dominos", "Layla and Other Assorted Love Songs", "rock", 1970, 116, 6, "Dm", "standard", []string{"riff", "classic"}},
+	{"Crossroads", "Cream", "Wheels of Fire", "blues", 1968, 136, 7, "A", "standard", []string{"blues", "solo"}},
+	{"Black Magic Woman", "Santana", "Abraxas", "latin rock", 1970, 120, 5, "Dm", "standard", []string{"latin", "solo"}},
+	{"Eruption", "Van Halen", "Van Halen", "rock", 1978, 120, 10, "A", "eb", []string{"tapping", "solo"}},
+	{"Dust in the Wind", "Kansas", "Point of Know Return", "folk rock", 1977, 96, 4, "C", "standard", []string{"fingerpicking", "acoustic"}},
+	{"Iron Man", "Black Sabbath", "Paranoid", "metal", 1970, 76, 3, "B", "standard", []string{"riff", "beginner"}},
+}
+
+var firstNames = []string{"Alex", "Sam", "Jordan", "Taylor", "Morgan", "Casey", "Riley", "Jamie", "Avery", "Quinn"}
+var lastNames = []string{"Kim", "Lee", "Park", "Garcia", "Smith", "Nguyen", "Rossi", "Müller", "Silva", "Tanaka"}
+var tiers = []string{models.TierFree, models.TierFree, models.TierFree, models.TierHobbyist, models.TierProfessional, models.TierMaster}
+
+// Run inserts realistic users, scores and practice history in one transaction.
+// Seeded users are named seed-user-N so repeated runs skip existing ones.
+func Run(ctx context.Context, db *sql.DB, opts Options) (*Result, error) {
+	rng := rand.New(rand.NewSource(opts.Seed))
+	result := &Result{}
+
+	passwordHash, err := utils.HashPassword(DefaultPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for i := 1; i <= opts.Users; i++ {
+		username := fmt.Sprintf("seed-user-%d", i)
+		tier := tiers[rng.Intn(len(tiers))]
+		createdAt := time.Now().Add(-time.Duration(rng.Intn(365*24)) * time.Hour)
+
+		userID := uuid.New()
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO users (id, email, username, password_hash, first_name, last_name,
+							  subscription_tier, email_verified, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, true, $8, $8)
+			ON CONFLICT DO NOTHING`,
+			userID, username+"@example.com", username, passwordHash,
+			firstNames[rng.Intn(len(firstNames))], lastNames[rng.Intn(len(lastNames))],
+			tier, createdAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert user %s: %w", username, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		result.Users++
+
+		for j := 0; j < opts.ScoresPerUser; j++ {
+			song := songs[rng.Intn(len(songs))]
+			scoreID := uuid.New()
+
+			// Public scores need license information; the seeded songs are
+			// covers, credited to the original artist
+			isPublic := rng.Intn(3) == 0
+			var licenseType, licenseComposer, licenseUpdatedAt interface{}
+			if isPublic {
+				licenseType, licenseComposer, licenseUpdatedAt = models.LicenseCover, song.artist, time.Now()
+			}
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO scores (id, user_id, title, artist, album, genre, year, tempo,
+								   difficulty_level, key_signature, time_signature, tuning,
+								   tags, instruments, is_public, created_at,
+								   license_type, license_composer, license_updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, '4/4', $11, $12, '{guitar}', $13, $14,
+						$15, $16, $17)`,
+				scoreID, userID, song.title, song.artist, song.album, song.genre, song.year,
+				song.tempo, song.difficulty, song.key, song.tuning, pq.Array(song.tags),
+				isPublic, createdAt.Add(time.Duration(rng.Intn(90*24))*time.Hour),
+				licenseType, licenseComposer, licenseUpdatedAt,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to insert score: %w", err)
+			}
+			result.Scores++
+
+			// Practice history for most scores
+			if rng.Intn(4) == 0 {
+				continue
+			}
+			completion := rng.Float64() * 100
+			lastPracticed := time.Now().Add(-time.Duration(rng.Intn(30*24)) * time.Hour)
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO learning_progress (user_id, score_id, last_practiced_at,
+											  total_practice_time_minutes, completion_percentage,
+											  accuracy_percentage, current_streak_days, best_streak_days,
+											  target_tempo, current_tempo)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+				userID, scoreID, lastPracticed, 10+rng.Intn(600), completion,
+				50+rng.Float64()*50, rng.Intn(7), 7+rng.Intn(30),
+				song.tempo, song.tempo*(50+rng.Intn(50))/100,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to insert practice history: %w", err)
+			}
+			result.Progress++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,176 @@
+package servicecall
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"user-service/internal/utils"
+)
+
+// ServerTLSConfig builds the listener TLS config from TLS_CERT_FILE/TLS_KEY_FILE.
+// It returns nil when TLS is not configured. If TLS_CLIENT_CA_FILE is set,
+// client certificates signed by that CA are verified; they are only mandatory
+// when TLS_REQUIRE_CLIENT_CERT=true, so end-user traffic from the gateway can
+// still connect without one.
+func ServerTLSConfig() (*tls.Config, error) {
+	certFile := os.Getenv("TLS_CERT_FILE")
+	keyFile := os.Getenv("TLS_KEY_FILE")
+	if certFile == "" || keyFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if caFile := os.Getenv("TLS_CLIENT_CA_FILE"); caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if os.Getenv("TLS_REQUIRE_CLIENT_CERT") == "true" {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return config, nil
+}
+
+// clientTLSConfig presents this service's certificate to peers and trusts
+// TLS_CA_FILE when set
+func clientTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	certFile := os.Getenv("TLS_CERT_FILE")
+	keyFile := os.Getenv("TLS_KEY_FILE")
+	if certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile := os.Getenv("TLS_CA_FILE"); caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificates found in CA file")
+	}
+	return pool, nil
+}
+
+var client *http.Client
+
+// Init prepares the HTTP client used for calls to other internal services
+func Init() error {
+	tlsConfig, err := clientTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	client = &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig:     tlsConfig,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	return nil
+}
+
+// ErrNotInitialized is returned for calls made before Init
+var ErrNotInitialized = errors.New("service client not initialized")
+
+// Probe sends an unauthenticated GET to another service's health endpoint.
+// It uses the same TLS client as Do but needs no service identity key.
+func Probe(ctx context.Context, url string) (*http.Response, error) {
+	if client == nil {
+		return nil, ErrNotInitialized
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// Do sends a request to another internal service, authenticated with a
+// service identity token addressed to that service and signed for the
+// receiver's replay protection
+func Do(ctx context.Context, service, method, url string, body io.Reader) (*http.Response, error) {
+	if client == nil {
+		return nil, ErrNotInitialized
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateServiceToken(service)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// Timestamp and nonce for the receiver's replay protection
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := utils.SignServiceRequest(utils.CanonicalRequest(method, req.URL.RequestURI(), timestamp, hex.EncodeToString(nonce), payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Timestamp", timestamp)
+	req.Header.Set("X-Nonce", hex.EncodeToString(nonce))
+	req.Header.Set("X-Signature", signature)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return client.Do(req)
+}
@@ -0,0 +1,231 @@
+package signaling
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+	"user-service/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/net/websocket"
+)
+
+// Message types. Clients send offer, answer and ice addressed to a peer, and
+// ping as a keepalive; the server sends welcome, join, leave, pong and error.
+const (
+	TypeWelcome = "welcome"
+	TypeJoin    = "join"
+	TypeLeave   = "leave"
+	TypeOffer   = "offer"
+	TypeAnswer  = "answer"
+	TypeICE     = "ice"
+	TypePing    = "ping"
+	TypePong    = "pong"
+	TypeError   = "error"
+)
+
+// idleTimeout closes connections that stop sending (clients ping every ~30s)
+const idleTimeout = 90 * time.Second
+
+const writeTimeout = 10 * time.Second
+
+// Message is a signaling message. From is always set by the server.
+type Message struct {
+	Type    string          `json:"type"`
+	From    string          `json:"from,omitempty"`
+	To      string          `json:"to,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type peer struct {
+	PeerInfo
+	roomID string
+	send   chan Message
+}
+
+// localRoom tracks the peers connected to this instance and the Redis
+// subscription relaying the room's messages between instances
+type localRoom struct {
+	peers map[string]*peer
+	sub   *redis.PubSub
+}
+
+var (
+	mu    sync.Mutex
+	rooms = map[string]*localRoom{}
+)
+
+// Serve runs a peer connection until the client disconnects
+func Serve(ws *websocket.Conn, t *Ticket) {
+	ctx := context.Background()
+
+	room, err := GetRoom(ctx, t.RoomID)
+	if err != nil {
+		sendError(ws, err.Error())
+		return
+	}
+
+	p := &peer{
+		PeerInfo: PeerInfo{PeerID: uuid.NewString(), UserID: t.UserID, Username: t.Username},
+		roomID:   room.ID,
+		send:     make(chan Message, 64),
+	}
+
+	if err := addPeer(ctx, room, p.PeerInfo); err != nil {
+		sendError(ws, err.Error())
+		return
+	}
+	defer func() {
+		if err := removePeer(ctx, room.ID, p.PeerID); err != nil {
+			log.Printf("Failed to remove jam peer: %v", err)
+		}
+		publish(ctx, room.ID, Message{Type: TypeLeave, From: p.PeerID, Payload: mustJSON(p.PeerInfo)})
+	}()
+
+	if err := attach(ctx, p); err != nil {
+		log.Printf("Failed to subscribe to jam room %s: %v", room.ID, err)
+		sendError(ws, "signaling unavailable")
+		return
+	}
+	defer detach(p)
+
+	go writeLoop(ws, p)
+
+	peers, err := Peers(ctx, room.ID)
+	if err != nil {
+		peers = []PeerInfo{}
+	}
+	p.send <- Message{Type: TypeWelcome, Payload: mustJSON(map[string]interface{}{
+		"peer_id": p.PeerID,
+		"room":    room,
+		"peers":   peers,
+	})}
+	publish(ctx, room.ID, Message{Type: TypeJoin, From: p.PeerID, Payload: mustJSON(p.PeerInfo)})
+
+	for {
+		ws.SetReadDeadline(time.Now().Add(idleTimeout))
+
+		var m Message
+		if err := websocket.JSON.Receive(ws, &m); err != nil {
+			return
+		}
+
+		switch m.Type {
+		case TypePing:
+			p.send <- Message{Type: TypePong}
+		case TypeOffer, TypeAnswer, TypeICE:
+			if m.To == "" || m.To == p.PeerID {
+				p.send <- errorMessage("to must be another peer in the room")
+				continue
+			}
+			if ok, err := hasPeer(ctx, room.ID, m.To); err != nil || !ok {
+				p.send <- errorMessage("unknown peer")
+				continue
+			}
+			m.From = p.PeerID
+			publish(ctx, room.ID, m)
+		default:
+			p.send <- errorMessage("unknown message type")
+		}
+	}
+}
+
+func writeLoop(ws *websocket.Conn, p *peer) {
+	for m := range p.send {
+		ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := websocket.JSON.Send(ws, m); err != nil {
+			// Unblocks the read loop, which detaches the peer
+			ws.Close()
+			for range p.send {
+			}
+			return
+		}
+	}
+}
+
+// attach registers a peer locally, subscribing this instance to the room on
+// its first local peer
+func attach(ctx context.Context, p *peer) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	room, ok := rooms[p.roomID]
+	if !ok {
+		sub := database.GetRedis().Subscribe(ctx, channelKey(p.roomID))
+		if _, err := sub.Receive(ctx); err != nil {
+			sub.Close()
+			return err
+		}
+		room = &localRoom{peers: map[string]*peer{}, sub: sub}
+		rooms[p.roomID] = room
+		go relay(p.roomID, sub)
+	}
+	room.peers[p.PeerID] = p
+	return nil
+}
+
+// detach removes a local peer, dropping the room subscription with the last one
+func detach(p *peer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	room, ok := rooms[p.roomID]
+	if !ok {
+		return
+	}
+	delete(room.peers, p.PeerID)
+	close(p.send)
+
+	if len(room.peers) == 0 {
+		room.sub.Close()
+		delete(rooms, p.roomID)
+	}
+}
+
+// relay delivers messages published to a room to its local peers
+func relay(roomID string, sub *redis.PubSub) {
+	for msg := range sub.Channel() {
+		var m Message
+		if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+			continue
+		}
+
+		mu.Lock()
+		if room, ok := rooms[roomID]; ok && room.sub == sub {
+			for id, p := range room.peers {
+				if id == m.From || (m.To != "" && m.To != id) {
+					continue
+				}
+				select {
+				case p.send <- m:
+				default:
+					// Slow consumer; signaling is retried by the client
+				}
+			}
+		}
+		mu.Unlock()
+	}
+}
+
+func publish(ctx context.Context, roomID string, m Message) {
+	if err := database.GetRedis().Publish(ctx, channelKey(roomID), mustJSON(m)).Err(); err != nil {
+		log.Printf("Failed to publish jam message: %v", err)
+	}
+}
+
+func errorMessage(msg string) Message {
+	return Message{Type: TypeError, Payload: mustJSON(map[string]string{"error": msg})}
+}
+
+func sendError(ws *websocket.Conn, msg string) {
+	ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_ = websocket.JSON.Send(ws, errorMessage(msg))
+}
+
+func mustJSON(v interface{}) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
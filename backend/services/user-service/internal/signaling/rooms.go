@@ -0,0 +1,188 @@
+package signaling
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+	"user-service/internal/database"
+	"user-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// roomTTL is how long an idle room is kept; joining extends it
+const roomTTL = 12 * time.Hour
+
+// ticketTTL is how long a join ticket can be redeemed
+const ticketTTL = time.Minute
+
+// ErrRoomNotFound is returned for unknown or expired rooms
+var ErrRoomNotFound = errors.New("room not found")
+
+// ErrRoomFull is returned when a room already has its maximum number of peers
+var ErrRoomFull = errors.New("room is full")
+
+// ErrInvalidTicket is returned for unknown, expired or already used tickets
+var ErrInvalidTicket = errors.New("invalid or expired ticket")
+
+// Room is a jam room. Rooms only hold signaling state; audio flows peer to peer.
+type Room struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	OwnerID   string    `json:"owner_id"`
+	MaxPeers  int       `json:"max_peers"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PeerInfo identifies a connected peer to the rest of the room
+type PeerInfo struct {
+	PeerID   string `json:"peer_id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// Ticket authorizes one WebSocket connection to a room
+type Ticket struct {
+	RoomID   string `json:"room_id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+func roomKey(id string) string    { return "jam:room:" + id }
+func peersKey(id string) string   { return "jam:room:" + id + ":peers" }
+func channelKey(id string) string { return "jam:room:" + id + ":signal" }
+func ticketKey(hash string) string {
+	return "jam:ticket:" + hash
+}
+
+// addPeerScript registers a peer unless the room is already full
+var addPeerScript = redis.NewScript(`
+if redis.call("HLEN", KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+redis.call("EXPIRE", KEYS[1], ARGV[4])
+return 1`)
+
+// CreateRoom creates a room owned by ownerID
+func CreateRoom(ctx context.Context, ownerID, name string, maxPeers int) (*Room, error) {
+	room := &Room{
+		ID:        uuid.NewString(),
+		Name:      name,
+		OwnerID:   ownerID,
+		MaxPeers:  maxPeers,
+		ExpiresAt: time.Now().Add(roomTTL),
+	}
+
+	rdb := database.GetRedis()
+	pipe := rdb.TxPipeline()
+	pipe.HSet(ctx, roomKey(room.ID), "name", room.Name, "owner_id", room.OwnerID, "max_peers", room.MaxPeers)
+	pipe.Expire(ctx, roomKey(room.ID), roomTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// GetRoom loads a room
+func GetRoom(ctx context.Context, id string) (*Room, error) {
+	rdb := database.GetRedis()
+	fields, err := rdb.HGetAll(ctx, roomKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrRoomNotFound
+	}
+
+	ttl, err := rdb.TTL(ctx, roomKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	maxPeers, _ := strconv.Atoi(fields["max_peers"])
+	return &Room{
+		ID:        id,
+		Name:      fields["name"],
+		OwnerID:   fields["owner_id"],
+		MaxPeers:  maxPeers,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// Peers lists the peers currently connected to a room
+func Peers(ctx context.Context, roomID string) ([]PeerInfo, error) {
+	values, err := database.GetRedis().HVals(ctx, peersKey(roomID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	peers := make([]PeerInfo, 0, len(values))
+	for _, v := range values {
+		var p PeerInfo
+		if err := json.Unmarshal([]byte(v), &p); err == nil {
+			peers = append(peers, p)
+		}
+	}
+	return peers, nil
+}
+
+// IssueTicket returns a single-use ticket for joining a room over WebSocket.
+// Browsers cannot set headers on WebSocket requests, so the ticket stands in
+// for the access token without putting the token in a URL.
+func IssueTicket(ctx context.Context, t Ticket) (string, time.Time, error) {
+	token, hash, err := utils.GenerateOpaqueToken(utils.JamTicketPrefix)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	payload, _ := json.Marshal(t)
+	if err := database.GetRedis().Set(ctx, ticketKey(hash), payload, ticketTTL).Err(); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Now().Add(ticketTTL), nil
+}
+
+// RedeemTicket consumes a ticket
+func RedeemTicket(ctx context.Context, token string) (*Ticket, error) {
+	payload, err := database.GetRedis().GetDel(ctx, ticketKey(utils.HashAPIKey(token))).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidTicket
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t Ticket
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return nil, ErrInvalidTicket
+	}
+	return &t, nil
+}
+
+// addPeer registers a peer in the room's shared peer list and extends the room
+func addPeer(ctx context.Context, room *Room, p PeerInfo) error {
+	payload, _ := json.Marshal(p)
+	ttl := int(roomTTL.Seconds())
+
+	added, err := addPeerScript.Run(ctx, database.GetRedis(), []string{peersKey(room.ID)},
+		p.PeerID, payload, room.MaxPeers, ttl).Int()
+	if err != nil {
+		return err
+	}
+	if added == 0 {
+		return ErrRoomFull
+	}
+	return database.GetRedis().Expire(ctx, roomKey(room.ID), roomTTL).Err()
+}
+
+func removePeer(ctx context.Context, roomID, peerID string) error {
+	return database.GetRedis().HDel(ctx, peersKey(roomID), peerID).Err()
+}
+
+func hasPeer(ctx context.Context, roomID, peerID string) (bool, error) {
+	return database.GetRedis().HExists(ctx, peersKey(roomID), peerID).Result()
+}
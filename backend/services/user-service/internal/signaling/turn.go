@@ -0,0 +1,61 @@
+package signaling
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrTURNNotConfigured is returned when no TURN shared secret is set
+var ErrTURNNotConfigured = errors.New("TURN is not configured")
+
+// TURNCredential is a time-limited TURN credential (coturn REST API scheme)
+type TURNCredential struct {
+	Username   string   `json:"username"`
+	Credential string   `json:"credential"`
+	TTL        int      `json:"ttl"`
+	URIs       []string `json:"uris"`
+}
+
+// turnCredentialTTL returns how long issued TURN credentials stay valid
+func turnCredentialTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("TURN_CREDENTIAL_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return time.Hour
+}
+
+// NewTURNCredential issues a credential for userID, signed with TURN_SECRET,
+// which the TURN server verifies without calling back into this service
+func NewTURNCredential(userID string) (*TURNCredential, error) {
+	secret := os.Getenv("TURN_SECRET")
+	uris := os.Getenv("TURN_URIS")
+	if secret == "" || uris == "" {
+		return nil, ErrTURNNotConfigured
+	}
+
+	ttl := turnCredentialTTL()
+	username := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10) + ":" + userID
+
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(username))
+
+	var list []string
+	for _, uri := range strings.Split(uris, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			list = append(list, uri)
+		}
+	}
+
+	return &TURNCredential{
+		Username:   username,
+		Credential: base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+		TTL:        int(ttl.Seconds()),
+		URIs:       list,
+	}, nil
+}
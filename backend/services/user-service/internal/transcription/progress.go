@@ -0,0 +1,76 @@
+package transcription
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"user-service/internal/database"
+	"user-service/internal/models"
+)
+
+// Stages workers report progress in, in the order they run
+const (
+	StageDownloading  = "downloading"
+	StageSeparating   = "separating"
+	StageTranscribing = "transcribing"
+	StageRendering    = "rendering"
+)
+
+// stageSpans are the part of a job's overall progress each stage covers
+var stageSpans = map[string][2]int{
+	StageDownloading:  {0, 10},
+	StageSeparating:   {10, 40},
+	StageTranscribing: {40, 90},
+	StageRendering:    {90, 100},
+}
+
+// OverallProgress converts progress through a stage into progress through
+// the whole job
+func OverallProgress(stage string, percent int) int {
+	span := stageSpans[stage]
+	return span[0] + (span[1]-span[0])*percent/100
+}
+
+// Finished reports whether a job status is final
+func Finished(status string) bool {
+	return status == models.TranscriptionCompleted || status == models.TranscriptionFailed ||
+		status == models.TranscriptionCancelled
+}
+
+// JobColumns are the columns of the public job view, in ScanJob's order
+const JobColumns = "id, status, progress, stage, stage_progress, input_type, input_url, score_id, error_message, created_at, completed_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// ScanJob reads JobColumns into the public job view
+func ScanJob(row rowScanner) (models.TranscriptionJob, error) {
+	var job models.TranscriptionJob
+	err := row.Scan(&job.ID, &job.Status, &job.Progress, &job.Stage, &job.StageProgress, &job.InputType,
+		&job.InputURL, &job.ScoreID, &job.ErrorMessage, &job.CreatedAt, &job.CompletedAt)
+	return job, err
+}
+
+// Channel is the Redis channel a job's updates are published on
+func Channel(jobID string) string {
+	return "transcription-jobs:" + jobID
+}
+
+// Publish pushes a job's current state to the clients following it. Failures
+// are logged rather than returned; clients can still poll the job.
+func Publish(ctx context.Context, db *sql.DB, jobID string) {
+	job, err := ScanJob(db.QueryRowContext(ctx, "SELECT "+JobColumns+" FROM transcription_jobs WHERE id = $1", jobID))
+	if err != nil {
+		log.Printf("Failed to load transcription job %s for publishing: %v", jobID, err)
+		return
+	}
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return
+	}
+	if err := database.GetRedis().Publish(ctx, Channel(jobID), payload).Err(); err != nil {
+		log.Printf("Failed to publish transcription job %s: %v", jobID, err)
+	}
+}
@@ -0,0 +1,129 @@
+package transcription
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+	"user-service/internal/models"
+	"user-service/internal/notify"
+)
+
+// Failure classes reported by workers. Transient failures and worker crashes
+// are retried; the others are permanent.
+const (
+	FailureTransient         = "transient"
+	FailureWorkerCrash       = "worker_crash"
+	FailureBadAudio          = "bad_audio"
+	FailureUnsupportedFormat = "unsupported_format"
+)
+
+// MaxAttempts is how many times a job runs before it is dead-lettered
+const MaxAttempts = 3
+
+// RetryBackoff is the delay before the first retry; each further retry waits
+// four times longer
+const RetryBackoff = 30 * time.Second
+
+// StallTimeout is how long a processing job can go without a worker heartbeat
+// before it is treated as a worker crash
+const StallTimeout = 10 * time.Minute
+
+// Retryable reports whether a failure class is worth another attempt
+func Retryable(class string) bool {
+	return class == FailureTransient || class == FailureWorkerCrash
+}
+
+// Outcome is what became of a failed job
+type Outcome struct {
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	DeadLettered  bool       `json:"dead_lettered"`
+}
+
+// Fail records the failure of a processing job's given attempt. Retryable
+// failures with attempts left send the job back to the queue after a backoff;
+// any other failure fails the job and dead-letters it. sql.ErrNoRows means
+// the job was not processing that attempt.
+func Fail(ctx context.Context, db *sql.DB, jobID string, attempt int, class, message string, details models.JSONB) (Outcome, error) {
+	return fail(ctx, db, jobID, attempt, class, message, details, false)
+}
+
+// FailStalled fails a processing job as a worker crash if it has gone
+// StallTimeout without a heartbeat. sql.ErrNoRows means it has not.
+func FailStalled(ctx context.Context, db *sql.DB, jobID string) (Outcome, error) {
+	return fail(ctx, db, jobID, 0, FailureWorkerCrash, "Worker stopped responding", nil, true)
+}
+
+// fail fails a processing job on its current attempt, or only on the given
+// attempt when that is not 0
+func fail(ctx context.Context, db *sql.DB, jobID string, attempt int, class, message string, details models.JSONB, stalledOnly bool) (Outcome, error) {
+	var detailsValue interface{}
+	if details != nil {
+		detailsValue = details
+	}
+
+	var o Outcome
+	err := db.QueryRowContext(ctx, `
+		WITH job AS (
+			SELECT id, $4 AND attempts < $5 AS retry FROM transcription_jobs
+			WHERE id = $1 AND status = $6 AND ($13 = 0 OR attempts = $13)
+			  AND (NOT $11 OR COALESCE(heartbeat_at, started_at, created_at) < NOW() - make_interval(secs => $12))
+			FOR UPDATE
+		)
+		UPDATE transcription_jobs j SET
+			failure_class = $2, error_message = $3, error_details = $7, stage = NULL, stage_progress = NULL,
+			status = CASE WHEN job.retry THEN $8 ELSE $9 END,
+			next_attempt_at = CASE WHEN job.retry
+				THEN NOW() + make_interval(secs => $10 * power(4, GREATEST(j.attempts - 1, 0))) END,
+			completed_at = CASE WHEN job.retry THEN NULL ELSE NOW() END,
+			dead_lettered_at = CASE WHEN job.retry THEN NULL ELSE NOW() END
+		FROM job WHERE j.id = job.id
+		RETURNING j.status, j.attempts, j.next_attempt_at`,
+		jobID, class, message, Retryable(class), MaxAttempts, models.TranscriptionProcessing, detailsValue,
+		models.TranscriptionPending, models.TranscriptionFailed, RetryBackoff.Seconds(),
+		stalledOnly, StallTimeout.Seconds(), attempt,
+	).Scan(&o.Status, &o.Attempts, &o.NextAttemptAt)
+	o.DeadLettered = o.Status == models.TranscriptionFailed
+	if err == nil && o.DeadLettered {
+		NotifyFinished(ctx, db, jobID)
+	}
+	return o, err
+}
+
+// NotifyFinished tells the owner of a job that completed or failed for good,
+// through their job notification settings. Jobs in a batch are covered by the
+// batch's notification. Failures are logged, not returned.
+func NotifyFinished(ctx context.Context, db *sql.DB, jobID string) {
+	var userID, status string
+	var scoreID sql.NullString
+	var inBatch bool
+	err := db.QueryRowContext(ctx,
+		"SELECT user_id, status, score_id, batch_id IS NOT NULL FROM transcription_jobs WHERE id = $1", jobID,
+	).Scan(&userID, &status, &scoreID, &inBatch)
+	if err != nil {
+		log.Printf("Failed to load transcription job %s for notifying: %v", jobID, err)
+		return
+	}
+	if inBatch {
+		return
+	}
+
+	link := notify.Link("/transcriptions/" + jobID)
+	if scoreID.Valid {
+		link = notify.Link("/scores/" + scoreID.String)
+	}
+	err = notify.EnqueueJobFinished(ctx, db, notify.Notification{
+		Type:   notify.TypeTranscriptionCompleted,
+		UserID: userID,
+		Data: map[string]interface{}{
+			"job_id": jobID,
+			"status": status,
+			"url":    link,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to enqueue transcription completion for %s: %v", jobID, err)
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix marks public API keys so they are recognizable in logs and secret scanners
+const APIKeyPrefix = "gm_live_"
+
+// GenerateAPIKey returns a new API key, the short prefix shown in listings, and the hash to store
+func GenerateAPIKey() (key, displayPrefix, hash string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:len(APIKeyPrefix)+6], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the lookup hash of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(key)))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateClientCredentials returns a new OAuth client ID, client secret, and the secret's hash
+func GenerateClientCredentials() (clientID, secret, secretHash string, err error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", err
+	}
+	s := make([]byte, 32)
+	if _, err := rand.Read(s); err != nil {
+		return "", "", "", err
+	}
+
+	secret = "gmcs_" + hex.EncodeToString(s)
+	return "gmc_" + hex.EncodeToString(id), secret, HashAPIKey(secret), nil
+}
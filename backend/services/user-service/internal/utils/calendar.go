@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+)
+
+func calendarFeedSecret() string {
+	secret := os.Getenv("CALENDAR_FEED_SECRET")
+	if secret == "" {
+		secret = "default-calendar-secret-change-in-production"
+	}
+	return secret
+}
+
+// SignCalendarFeed returns the signature embedded in a user's ICS feed URL
+func SignCalendarFeed(userID, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(calendarFeedSecret()))
+	mac.Write([]byte(userID + ":" + nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyCalendarFeed checks an ICS feed URL signature in constant time
+func VerifyCalendarFeed(userID, nonce, signature string) bool {
+	return hmac.Equal([]byte(SignCalendarFeed(userID, nonce)), []byte(signature))
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+)
+
+func exportLinkSecret() string {
+	secret := os.Getenv("EXPORT_LINK_SECRET")
+	if secret == "" {
+		secret = "default-export-secret-change-in-production"
+	}
+	return secret
+}
+
+// SignExportDownload returns the signature of a score export download link
+// valid until expires (Unix seconds)
+func SignExportDownload(exportID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(exportLinkSecret()))
+	mac.Write([]byte(exportID + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyExportDownload checks a download link signature in constant time
+func VerifyExportDownload(exportID string, expires int64, signature string) bool {
+	return hmac.Equal([]byte(SignExportDownload(exportID, expires)), []byte(signature))
+}
@@ -15,6 +15,12 @@ type Claims struct {
 	Email    string    `json:"email"`
 	Username string    `json:"username"`
 	Role     string    `json:"role"`
+	// Scopes restricts the token to the listed scopes; first-party session
+	// tokens carry none and are unrestricted
+	Scopes []string `json:"scopes,omitempty"`
+	// ImpersonatorID is the staff member acting as the user, on
+	// impersonation tokens only
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -71,6 +77,37 @@ func GenerateTokens(userID uuid.UUID, email, username, role string) (string, str
 	return accessTokenString, refreshTokenString, nil
 }
 
+// GenerateScopedAccessToken generates a short-lived access token restricted to
+// the given scopes, e.g. for impersonation, where impersonatorID names the
+// staff member acting as the user. No refresh token is issued.
+func GenerateScopedAccessToken(userID uuid.UUID, email, username, role string, scopes []string, ttl time.Duration, impersonatorID string) (string, error) {
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		jwtSecret = "default-jwt-secret-change-in-production"
+	}
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	claims := &Claims{
+		UserID:         userID,
+		Email:          email,
+		Username:       username,
+		Role:           role,
+		Scopes:         scopes,
+		ImpersonatorID: impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "genesis-music",
+			Subject:   userID.String(),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwtSecret))
+}
+
 // ValidateAccessToken validates an access token
 func ValidateAccessToken(tokenString string) (*Claims, error) {
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -119,4 +156,10 @@ func ValidateRefreshToken(tokenString string) (*Claims, error) {
 	}
 
 	return nil, errors.New("invalid token")
-}
\ No newline at end of file
+}
+
+// Cookie names used in cookie session mode
+const (
+	AccessTokenCookie  = "genesis_access_token"
+	RefreshTokenCookie = "genesis_refresh_token"
+)
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Prefixes of opaque OAuth credentials
+const (
+	OAuthCodePrefix         = "gmac_"
+	OAuthAccessTokenPrefix  = "gmat_"
+	OAuthRefreshTokenPrefix = "gmrt_"
+)
+
+// UploadTokenPrefix marks single-use upload credentials
+const UploadTokenPrefix = "gmut_"
+
+// JamTicketPrefix marks single-use jam room join tickets
+const JamTicketPrefix = "gmjt_"
+
+// GenerateOpaqueToken returns a random token with the given prefix and its lookup hash
+func GenerateOpaqueToken(prefix string) (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = prefix + hex.EncodeToString(b)
+	return token, HashAPIKey(token), nil
+}
+
+// VerifyPKCE checks an RFC 7636 S256 code verifier against its challenge
+func VerifyPKCE(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestVerifyPKCE(t *testing.T) {
+	verifier := strings.Repeat("a", 43)
+	challenge := pkceChallenge(verifier)
+
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		want      bool
+	}{
+		{"matching verifier", verifier, challenge, true},
+		{"longest verifier", strings.Repeat("b", 128), pkceChallenge(strings.Repeat("b", 128)), true},
+		{"wrong verifier", strings.Repeat("b", 43), challenge, false},
+		{"verifier too short", verifier[:42], pkceChallenge(verifier[:42]), false},
+		{"verifier too long", strings.Repeat("c", 129), pkceChallenge(strings.Repeat("c", 129)), false},
+		{"empty verifier", "", challenge, false},
+		{"empty challenge", verifier, "", false},
+		{"plain challenge", verifier, verifier, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPKCE(tt.verifier, tt.challenge); got != tt.want {
+				t.Errorf("VerifyPKCE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+	}{
+		{"authorization code", OAuthCodePrefix},
+		{"access token", OAuthAccessTokenPrefix},
+		{"refresh token", OAuthRefreshTokenPrefix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, hash, err := GenerateOpaqueToken(tt.prefix)
+			if err != nil {
+				t.Fatalf("GenerateOpaqueToken() error = %v", err)
+			}
+			if !strings.HasPrefix(token, tt.prefix) {
+				t.Errorf("token %q does not start with %q", token, tt.prefix)
+			}
+			if hash != HashAPIKey(token) {
+				t.Errorf("hash does not match HashAPIKey(token)")
+			}
+			other, otherHash, err := GenerateOpaqueToken(tt.prefix)
+			if err != nil {
+				t.Fatalf("GenerateOpaqueToken() error = %v", err)
+			}
+			if other == token || otherHash == hash {
+				t.Errorf("two tokens collided")
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCanonicalRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		requestURI string
+		timestamp  string
+		nonce      string
+		body       []byte
+		want       string
+	}{
+		{
+			name:       "empty body",
+			method:     "GET",
+			requestURI: "/api/v1/scores",
+			timestamp:  "1700000000",
+			nonce:      "0123456789abcdef",
+			want: "GET\n/api/v1/scores\n1700000000\n0123456789abcdef\n" +
+				"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name:       "query string and body",
+			method:     "POST",
+			requestURI: "/api/v1/transcriptions?priority=high",
+			timestamp:  "1700000000",
+			nonce:      "fedcba9876543210",
+			body:       []byte(`{"audio_url":"s3://bucket/a.wav"}`),
+			want: "POST\n/api/v1/transcriptions?priority=high\n1700000000\nfedcba9876543210\n" +
+				"dd6de6cdb53d755af10de2d37f397f297bd37abda8468459a332d5c114f59506",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(CanonicalRequest(tt.method, tt.requestURI, tt.timestamp, tt.nonce, tt.body))
+			if got != tt.want {
+				t.Errorf("CanonicalRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func signHMAC(secret string, canonical []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(canonical)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyRequestHMAC(t *testing.T) {
+	secret := SigningSecretPrefix + "secret"
+	canonical := CanonicalRequest("POST", "/api/v1/transcriptions", "1700000000", "0123456789abcdef", []byte("{}"))
+	valid := signHMAC(secret, canonical)
+
+	tests := []struct {
+		name      string
+		secret    string
+		canonical []byte
+		signature string
+		want      bool
+	}{
+		{"valid signature", secret, canonical, valid, true},
+		{"wrong secret", SigningSecretPrefix + "other", canonical, valid, false},
+		{"different body", secret, CanonicalRequest("POST", "/api/v1/transcriptions", "1700000000", "0123456789abcdef", []byte("{ }")), valid, false},
+		{"different nonce", secret, CanonicalRequest("POST", "/api/v1/transcriptions", "1700000000", "fedcba9876543210", []byte("{}")), valid, false},
+		{"truncated signature", secret, canonical, valid[:len(valid)-2], false},
+		{"non-hex signature", secret, canonical, "zz" + valid[2:], false},
+		{"empty signature", secret, canonical, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyRequestHMAC(tt.secret, tt.canonical, tt.signature); got != tt.want {
+				t.Errorf("VerifyRequestHMAC() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
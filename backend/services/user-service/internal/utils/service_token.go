@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ServiceName is this service's identity in service-to-service calls
+const ServiceName = "user-service"
+
+// ServiceClaims represents the claims of a service identity token
+type ServiceClaims struct {
+	Service string `json:"service"`
+	jwt.RegisteredClaims
+}
+
+// serviceKeys holds this service's signing key and the public keys of the
+// services allowed to call it. Each service signs with its own Ed25519 key, so
+// holding one service's key does not allow acting as another.
+type serviceKeys struct {
+	private ed25519.PrivateKey
+	public  map[string]ed25519.PublicKey
+}
+
+var (
+	keys     *serviceKeys
+	keysErr  error
+	keysOnce sync.Once
+)
+
+func getServiceKeys() (*serviceKeys, error) {
+	keysOnce.Do(func() {
+		keys, keysErr = loadServiceKeys()
+	})
+	return keys, keysErr
+}
+
+// loadServiceKeys reads SERVICE_TOKEN_PRIVATE_KEY_FILE (PKCS#8 PEM) and
+// SERVICE_TOKEN_PUBLIC_KEYS, a comma separated list of name=path pairs
+// pointing at each peer's PKIX PEM public key
+func loadServiceKeys() (*serviceKeys, error) {
+	k := &serviceKeys{public: map[string]ed25519.PublicKey{}}
+
+	if path := os.Getenv("SERVICE_TOKEN_PRIVATE_KEY_FILE"); path != "" {
+		block, err := readPEM(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse service private key: %w", err)
+		}
+		private, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("service private key must be Ed25519")
+		}
+		k.private = private
+	}
+
+	for _, entry := range strings.Split(os.Getenv("SERVICE_TOKEN_PUBLIC_KEYS"), ",") {
+		name, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		block, err := readPEM(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key for %s: %w", name, err)
+		}
+		public, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key for %s must be Ed25519", name)
+		}
+		k.public[name] = public
+	}
+
+	return k, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+	return block, nil
+}
+
+// GenerateServiceToken generates a short-lived token identifying this service to the audience service
+func GenerateServiceToken(audience string) (string, error) {
+	k, err := getServiceKeys()
+	if err != nil {
+		return "", err
+	}
+	if k.private == nil {
+		return "", errors.New("SERVICE_TOKEN_PRIVATE_KEY_FILE not configured")
+	}
+
+	claims := &ServiceClaims{
+		Service: ServiceName,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    ServiceName,
+			Subject:   ServiceName,
+			Audience:  jwt.ClaimStrings{audience},
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	return token.SignedString(k.private)
+}
+
+// ValidateServiceToken validates a service identity token addressed to this
+// service. The signature is checked against the public key of the service the
+// token claims to be, so a caller can only assert its own identity.
+func ValidateServiceToken(tokenString string) (*ServiceClaims, error) {
+	k, err := getServiceKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &ServiceClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		claims, ok := token.Claims.(*ServiceClaims)
+		if !ok {
+			return nil, errors.New("invalid service token")
+		}
+		public, ok := k.public[claims.Service]
+		if !ok {
+			return nil, errors.New("unknown service")
+		}
+		return public, nil
+	}, jwt.WithAudience(ServiceName))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*ServiceClaims); ok && token.Valid && claims.Service != "" {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid service token")
+}
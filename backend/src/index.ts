@@ -55,7 +55,11 @@ app.use(cors({
 }));
 app.use(cookieParser());
 app.use(morgan('combined', { stream: { write: message => logger.info(message.trim()) } }));
-app.use(express.json({ limit: '50mb' }));
+app.use(express.json({
+  limit: '50mb',
+  // Keep the raw body for webhook signature verification
+  verify: (req, _res, buf) => { (req as any).rawBody = buf; }
+}));
 app.use(express.urlencoded({ extended: true, limit: '50mb' }));
 
 // Static files
@@ -0,0 +1,66 @@
+/**
+ * Webhook Signature Middleware
+ * Rejects webhook calls that are unsigned, stale or replayed
+ */
+
+import crypto from 'crypto';
+import { Request, Response, NextFunction } from 'express';
+import { RedisService } from '../services/redis';
+import { logger } from '../utils/logger';
+
+const MAX_SKEW_SECONDS = 5 * 60;
+
+/**
+ * Verify X-Timestamp, X-Nonce and X-Signature on incoming webhooks.
+ * The signature is a hex HMAC-SHA256, keyed by WEBHOOK_SECRET, of
+ * "METHOD\nPATH\nTIMESTAMP\nNONCE\nSHA256(body)". Nonces are remembered in
+ * Redis for twice the allowed skew so a captured call cannot be replayed.
+ */
+export async function verifyWebhookSignature(req: Request, res: Response, next: NextFunction) {
+  const secret = process.env.WEBHOOK_SECRET;
+  if (!secret) {
+    logger.error('WEBHOOK_SECRET not configured; rejecting webhook');
+    return res.status(503).json({ success: false, error: 'Webhooks not configured' });
+  }
+
+  const timestamp = req.header('X-Timestamp');
+  const nonce = req.header('X-Nonce');
+  const signature = req.header('X-Signature');
+  if (!timestamp || !nonce || !signature) {
+    return res.status(400).json({ success: false, error: 'X-Timestamp, X-Nonce and X-Signature headers required' });
+  }
+
+  if (nonce.length < 16 || nonce.length > 128) {
+    return res.status(400).json({ success: false, error: 'Invalid nonce' });
+  }
+
+  const ts = Number(timestamp);
+  if (!Number.isInteger(ts) || Math.abs(Math.floor(Date.now() / 1000) - ts) > MAX_SKEW_SECONDS) {
+    return res.status(401).json({ success: false, error: 'Request timestamp outside allowed window' });
+  }
+
+  const rawBody: Buffer = (req as any).rawBody || Buffer.alloc(0);
+  const bodyHash = crypto.createHash('sha256').update(rawBody).digest('hex');
+  const canonical = [req.method, req.originalUrl, timestamp, nonce, bodyHash].join('\n');
+  const expected = crypto.createHmac('sha256', secret).update(canonical).digest();
+
+  let provided: Buffer;
+  try {
+    provided = Buffer.from(signature, 'hex');
+  } catch {
+    provided = Buffer.alloc(0);
+  }
+  if (provided.length !== expected.length || !crypto.timingSafeEqual(provided, expected)) {
+    return res.status(401).json({ success: false, error: 'Invalid webhook signature' });
+  }
+
+  const fresh = await RedisService.getInstance().setIfAbsent(`webhook:nonce:${nonce}`, '1', 2 * MAX_SKEW_SECONDS);
+  if (fresh === null) {
+    return res.status(503).json({ success: false, error: 'Replay check unavailable' });
+  }
+  if (!fresh) {
+    return res.status(409).json({ success: false, error: 'Request already processed' });
+  }
+
+  next();
+}
@@ -2,6 +2,7 @@ import { Router, Request, Response, NextFunction } from 'express';
 import { body, param, validationResult } from 'express-validator';
 import { youtubeService } from '../services/youtube.service';
 import { authenticate } from '../middleware/auth';
+import { verifyWebhookSignature } from '../middleware/webhookSignature';
 import { ValidationError } from '../utils/AppError';
 import { logger } from '../utils/logger';
 
@@ -212,11 +213,12 @@ router.delete(
 /**
  * @route   POST /api/youtube/webhook/progress
  * @desc    Webhook for AI service to update job progress
- * @access  Internal (should be secured with API key)
+ * @access  Internal (signed with WEBHOOK_SECRET)
  */
 router.post(
   '/webhook/progress',
   [
+    verifyWebhookSignature,
     body('jobId').notEmpty(),
     body('progress').isInt({ min: 0, max: 100 }),
     body('status').optional().isIn(['QUEUED', 'PROCESSING', 'COMPLETED', 'FAILED', 'CANCELLED']),
@@ -225,7 +227,6 @@ router.post(
   ],
   async (req: Request, res: Response, next: NextFunction) => {
     try {
-      // TODO: Verify webhook signature or API key for security
       const { jobId, progress, status, message } = req.body;
 
       await youtubeService.updateJobProgress(jobId, progress, status, message);
@@ -243,18 +244,18 @@ router.post(
 /**
  * @route   POST /api/youtube/webhook/complete
  * @desc    Webhook for AI service when transcription is complete
- * @access  Internal (should be secured with API key)
+ * @access  Internal (signed with WEBHOOK_SECRET)
  */
 router.post(
   '/webhook/complete',
   [
+    verifyWebhookSignature,
     body('jobId').notEmpty(),
     body('result').notEmpty(),
     handleValidationErrors
   ],
   async (req: Request, res: Response, next: NextFunction) => {
     try {
-      // TODO: Verify webhook signature or API key for security
       const { jobId, result } = req.body;
 
       await youtubeService.handleTranscriptionComplete(jobId, result);
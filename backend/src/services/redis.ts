@@ -101,6 +101,22 @@ export class RedisService {
     }
   }
   
+  /**
+   * Set a key only if it does not exist. Returns null when Redis is unavailable
+   * so callers can fail closed.
+   */
+  async setIfAbsent(key: string, value: string, expirySeconds: number): Promise<boolean | null> {
+    if (!this.client) return null;
+    
+    try {
+      const result = await this.client.set(key, value, { NX: true, EX: expirySeconds });
+      return result === 'OK';
+    } catch (error) {
+      logger.error('Redis setIfAbsent error:', error);
+      return null;
+    }
+  }
+  
   async hGet(key: string, field: string): Promise<string | null> {
     if (!this.client) return null;
     
@@ -0,0 +1,11 @@
+-- Field-level encryption for PII
+-- Encrypted values are stored as text envelopes ("enc:v1:<key id>:...") so
+-- columns holding them must be TEXT.
+
+ALTER TABLE refresh_tokens ALTER COLUMN ip_address TYPE TEXT USING ip_address::text;
+
+-- Original email of deactivated accounts; users.email is replaced with a placeholder
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_encrypted TEXT;
+
+COMMENT ON COLUMN refresh_tokens.ip_address IS 'Client IP address, encrypted at the application layer';
+COMMENT ON COLUMN users.email_encrypted IS 'Encrypted original email of a deactivated account';
@@ -0,0 +1,20 @@
+-- ==========================================
+-- Audit Log (security and account events)
+-- ==========================================
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(50) NOT NULL,
+    ip_address TEXT,
+    user_agent TEXT,
+    country VARCHAR(2),
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_user_created ON audit_log(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_event_type ON audit_log(event_type);
+
+COMMENT ON TABLE audit_log IS 'Security and account events, shown to users via GET /users/activity';
+COMMENT ON COLUMN audit_log.ip_address IS 'Client IP address, encrypted at the application layer';
@@ -0,0 +1,8 @@
+-- ==========================================
+-- API Key Scopes
+-- ==========================================
+-- Existing keys keep the access the public API gave them before scopes existed
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{scores:read,transcriptions:read,transcriptions:write}';
+
+COMMENT ON COLUMN api_keys.scopes IS 'Scopes the key is restricted to; checked per route';
@@ -0,0 +1,25 @@
+-- ==========================================
+-- Username and Email Change History
+-- ==========================================
+CREATE TABLE IF NOT EXISTS username_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(100) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_history_user ON username_history(user_id, changed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_username_history_username ON username_history(username, changed_at DESC);
+
+CREATE TABLE IF NOT EXISTS email_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email_encrypted TEXT NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_history_user ON email_history(user_id, changed_at DESC);
+
+COMMENT ON TABLE username_history IS 'Previous usernames; recent ones stay reserved and redirect to the current profile';
+COMMENT ON TABLE email_history IS 'Previous email addresses';
+COMMENT ON COLUMN email_history.email_encrypted IS 'Previous email, encrypted at the application layer';
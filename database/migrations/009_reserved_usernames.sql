@@ -0,0 +1,32 @@
+-- ==========================================
+-- Reserved Usernames
+-- ==========================================
+CREATE TABLE IF NOT EXISTS reserved_usernames (
+    username VARCHAR(100) PRIMARY KEY, -- stored lowercase; matching is case-insensitive
+    reason VARCHAR(255),
+    granted_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    granted_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO reserved_usernames (username, reason) VALUES
+    ('admin', 'system'),
+    ('administrator', 'system'),
+    ('root', 'system'),
+    ('system', 'system'),
+    ('support', 'system'),
+    ('help', 'system'),
+    ('staff', 'system'),
+    ('moderator', 'system'),
+    ('official', 'system'),
+    ('security', 'system'),
+    ('billing', 'system'),
+    ('api', 'system'),
+    ('www', 'system'),
+    ('genesis', 'brand'),
+    ('genesismusic', 'brand'),
+    ('genesis_music', 'brand')
+ON CONFLICT (username) DO NOTHING;
+
+COMMENT ON TABLE reserved_usernames IS 'Usernames that cannot be registered unless an admin grants them to an account';
@@ -0,0 +1,26 @@
+-- ==========================================
+-- Verified Artist / Instructor Badges
+-- ==========================================
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS verified_badge VARCHAR(20) CHECK (verified_badge IN ('artist', 'instructor'));
+
+CREATE TABLE IF NOT EXISTS verification_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    badge_type VARCHAR(20) NOT NULL CHECK (badge_type IN ('artist', 'instructor')),
+    evidence_urls TEXT[] NOT NULL DEFAULT '{}',
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- At most one open request per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_requests_pending
+    ON verification_requests(user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_verification_requests_status ON verification_requests(status, created_at);
+
+COMMENT ON TABLE verification_requests IS 'Artist/instructor verification requests and their review outcome';
@@ -0,0 +1,28 @@
+-- ==========================================
+-- Practice Reminders
+-- ==========================================
+CREATE TABLE IF NOT EXISTS practice_reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(100) NOT NULL,
+    days_of_week SMALLINT[] NOT NULL, -- 0 = Sunday ... 6 = Saturday, in the reminder's timezone
+    time_of_day VARCHAR(5) NOT NULL,  -- HH:MM local time
+    timezone VARCHAR(64) NOT NULL,
+    duration_minutes INTEGER NOT NULL DEFAULT 30 CHECK (duration_minutes BETWEEN 5 AND 480),
+    channels TEXT[] NOT NULL DEFAULT '{push}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_fire_at TIMESTAMP WITH TIME ZONE,
+    snoozed_until TIMESTAMP WITH TIME ZONE,
+    skip_until TIMESTAMP WITH TIME ZONE,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_practice_reminders_user ON practice_reminders(user_id);
+CREATE INDEX IF NOT EXISTS idx_practice_reminders_due ON practice_reminders(next_fire_at) WHERE enabled = TRUE;
+
+CREATE TRIGGER update_practice_reminders_updated_at BEFORE UPDATE ON practice_reminders
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE practice_reminders IS 'Recurring practice reminders, delivered through notification-service';
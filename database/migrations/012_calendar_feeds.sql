@@ -0,0 +1,10 @@
+-- ==========================================
+-- Calendar (ICS) Feeds
+-- ==========================================
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    nonce VARCHAR(64) NOT NULL, -- signed into the feed URL; replacing it revokes old URLs
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE calendar_feeds IS 'Per-user signing nonces for subscribable ICS calendar URLs';
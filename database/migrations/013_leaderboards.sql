@@ -0,0 +1,26 @@
+-- ==========================================
+-- Practice Leaderboards
+-- ==========================================
+ALTER TABLE users ADD COLUMN IF NOT EXISTS leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- One row per leaderboard period that has been opened
+CREATE TABLE IF NOT EXISTS leaderboard_periods (
+    period VARCHAR(10) NOT NULL CHECK (period IN ('week', 'month')),
+    period_start DATE NOT NULL,
+    opened_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (period, period_start)
+);
+
+-- Cumulative practice minutes per user when a period opened; minutes practiced
+-- in the period are the current total minus this baseline
+CREATE TABLE IF NOT EXISTS leaderboard_baselines (
+    period VARCHAR(10) NOT NULL,
+    period_start DATE NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    practice_minutes INTEGER NOT NULL,
+    PRIMARY KEY (period, period_start, user_id),
+    FOREIGN KEY (period, period_start) REFERENCES leaderboard_periods(period, period_start) ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN users.leaderboard_opt_out IS 'Hide the user from all leaderboards';
+COMMENT ON TABLE leaderboard_baselines IS 'Practice minute totals at the start of each leaderboard period';
@@ -0,0 +1,48 @@
+-- ==========================================
+-- Daily / Weekly Challenges
+-- ==========================================
+CREATE TABLE IF NOT EXISTS challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('daily', 'weekly')),
+    score_id UUID REFERENCES scores(id) ON DELETE SET NULL,
+    target_bpm INTEGER CHECK (target_bpm BETWEEN 20 AND 400),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_by_service VARCHAR(100), -- set when published by automation
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenges_window ON challenges(starts_at, ends_at);
+
+CREATE TABLE IF NOT EXISTS challenge_enrollments (
+    challenge_id UUID NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    enrolled_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (challenge_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenge_enrollments_user ON challenge_enrollments(user_id);
+
+CREATE TABLE IF NOT EXISTS challenge_submissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    challenge_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    recording_url VARCHAR(500) NOT NULL,
+    achieved_bpm INTEGER,
+    notes TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (challenge_id, user_id) REFERENCES challenge_enrollments(challenge_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenge_submissions_review ON challenge_submissions(challenge_id, status, created_at);
+
+COMMENT ON TABLE challenges IS 'Time-boxed practice challenges published by admins or automation';
+COMMENT ON TABLE challenge_submissions IS 'Challenge attempts; an accepted submission completes the enrollment';
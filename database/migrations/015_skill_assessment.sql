@@ -0,0 +1,34 @@
+-- ==========================================
+-- Skill Assessment and Level Placement
+-- ==========================================
+-- Skill level uses the same 1-10 scale as scores.difficulty_level
+ALTER TABLE users ADD COLUMN IF NOT EXISTS skill_level INTEGER CHECK (skill_level BETWEEN 1 AND 10);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS skill_assessed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS assessment_questions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    prompt TEXT NOT NULL,
+    choices TEXT[] NOT NULL CHECK (array_length(choices, 1) BETWEEN 2 AND 6),
+    correct_choice INTEGER NOT NULL CHECK (correct_choice >= 0),
+    difficulty_level INTEGER NOT NULL CHECK (difficulty_level BETWEEN 1 AND 10),
+    is_active BOOLEAN DEFAULT true,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (correct_choice < array_length(choices, 1))
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_questions_level ON assessment_questions(difficulty_level) WHERE is_active = true;
+
+CREATE TABLE IF NOT EXISTS assessment_results (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL DEFAULT 'quiz' CHECK (method IN ('quiz')),
+    correct INTEGER NOT NULL,
+    total INTEGER NOT NULL,
+    skill_level INTEGER NOT NULL CHECK (skill_level BETWEEN 1 AND 10),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assessment_results_user ON assessment_results(user_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_scores_public_difficulty ON scores(difficulty_level) WHERE is_public = true AND is_draft = false;
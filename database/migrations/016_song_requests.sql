@@ -0,0 +1,34 @@
+-- ==========================================
+-- Community Song Requests
+-- ==========================================
+CREATE TABLE IF NOT EXISTS song_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    artist VARCHAR(255) NOT NULL,
+    notes TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'in_progress', 'published', 'merged')),
+    merged_into UUID REFERENCES song_requests(id) ON DELETE SET NULL,
+    score_id UUID REFERENCES scores(id) ON DELETE SET NULL,
+    vote_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One open request per song; duplicates are merged into it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_requests_song
+    ON song_requests(LOWER(title), LOWER(artist)) WHERE status <> 'merged';
+CREATE INDEX IF NOT EXISTS idx_song_requests_board ON song_requests(status, vote_count DESC, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS song_request_votes (
+    request_id UUID NOT NULL REFERENCES song_requests(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (request_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_request_votes_user ON song_request_votes(user_id);
+
+CREATE TRIGGER update_song_requests_updated_at BEFORE UPDATE ON song_requests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
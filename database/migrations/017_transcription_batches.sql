@@ -0,0 +1,17 @@
+-- ==========================================
+-- Batch Transcription Submission
+-- ==========================================
+CREATE TABLE IF NOT EXISTS transcription_batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key_id UUID REFERENCES api_keys(id) ON DELETE SET NULL,
+    total INTEGER NOT NULL CHECK (total > 0),
+    notified_at TIMESTAMP WITH TIME ZONE, -- set once the completion notification is queued
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcription_batches_user ON transcription_batches(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transcription_batches_unnotified ON transcription_batches(created_at) WHERE notified_at IS NULL;
+
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS batch_id UUID REFERENCES transcription_batches(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_transcription_jobs_batch ON transcription_jobs(batch_id) WHERE batch_id IS NOT NULL;
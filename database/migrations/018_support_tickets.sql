@@ -0,0 +1,38 @@
+-- ==========================================
+-- Support Tickets
+-- ==========================================
+CREATE TABLE IF NOT EXISTS support_tickets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject VARCHAR(200) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('account', 'billing', 'transcription', 'bug', 'other')),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'waiting_on_user', 'resolved', 'closed')),
+    priority VARCHAR(10) NOT NULL CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    -- Subscription tier at creation; SLA timers are derived from it
+    tier VARCHAR(50) NOT NULL,
+    assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    first_response_due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolution_due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    first_responded_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_tickets_user ON support_tickets(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_support_tickets_queue ON support_tickets(status, resolution_due_at);
+
+CREATE TABLE IF NOT EXISTS support_ticket_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES support_tickets(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    is_staff BOOLEAN NOT NULL DEFAULT false,
+    body TEXT NOT NULL,
+    attachment_urls TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_ticket_messages_ticket ON support_ticket_messages(ticket_id, created_at);
+
+CREATE TRIGGER update_support_tickets_updated_at BEFORE UPDATE ON support_tickets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
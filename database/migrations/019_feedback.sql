@@ -0,0 +1,24 @@
+-- ==========================================
+-- In-app Feedback Triage
+-- ==========================================
+CREATE TABLE IF NOT EXISTS feedback (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('bug', 'feature_request', 'content', 'praise', 'other')),
+    message TEXT NOT NULL,
+    client_version VARCHAR(50),
+    platform VARCHAR(50),
+    page_url VARCHAR(500),
+    screenshot_url VARCHAR(500),
+    status VARCHAR(20) NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'triaged', 'planned', 'done', 'wont_fix')),
+    labels TEXT[] NOT NULL DEFAULT '{}',
+    triaged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_feedback_queue ON feedback(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_feedback_labels ON feedback USING GIN(labels);
+
+CREATE TRIGGER update_feedback_updated_at BEFORE UPDATE ON feedback
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,25 @@
+-- ==========================================
+-- Product Announcements (What's New)
+-- ==========================================
+CREATE TABLE IF NOT EXISTS announcements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL, -- Markdown
+    kind VARCHAR(20) NOT NULL DEFAULT 'release' CHECK (kind IN ('release', 'feature', 'notice')),
+    link_url VARCHAR(500),
+    published_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP, -- future dates schedule the entry
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_published ON announcements(published_at DESC);
+
+-- Per-user watermark: entries published after last_seen_at are unseen
+CREATE TABLE IF NOT EXISTS announcement_receipts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TRIGGER update_announcements_updated_at BEFORE UPDATE ON announcements
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
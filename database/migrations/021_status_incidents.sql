@@ -0,0 +1,32 @@
+-- ==========================================
+-- Public Service Status and Incidents
+-- ==========================================
+CREATE TABLE IF NOT EXISTS status_incidents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    impact VARCHAR(10) NOT NULL CHECK (impact IN ('minor', 'major', 'critical')),
+    status VARCHAR(20) NOT NULL DEFAULT 'investigating'
+        CHECK (status IN ('investigating', 'identified', 'monitoring', 'resolved')),
+    components TEXT[] NOT NULL CHECK (array_length(components, 1) > 0),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_incidents_active ON status_incidents(started_at DESC) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_status_incidents_history ON status_incidents(started_at DESC);
+
+CREATE TABLE IF NOT EXISTS status_incident_updates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    incident_id UUID NOT NULL REFERENCES status_incidents(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    message TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_incident_updates_incident ON status_incident_updates(incident_id, created_at);
+
+CREATE TRIGGER update_status_incidents_updated_at BEFORE UPDATE ON status_incidents
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,18 @@
+-- ==========================================
+-- Storage and Usage Threshold Alerts
+-- ==========================================
+-- The highest threshold a user has been alerted for per metric. The level only
+-- drops once usage falls well below it, so hovering near a threshold does not
+-- re-alert.
+CREATE TABLE IF NOT EXISTS usage_alerts (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric VARCHAR(50) NOT NULL CHECK (metric IN ('storage', 'transcription_minutes')),
+    level INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, metric)
+);
+
+CREATE TRIGGER update_usage_alerts_updated_at BEFORE UPDATE ON usage_alerts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX IF NOT EXISTS idx_transcription_jobs_user_completed ON transcription_jobs(user_id, completed_at) WHERE status = 'completed';
@@ -0,0 +1,10 @@
+-- ==========================================
+-- Shadow Banning
+-- ==========================================
+-- A shadow-banned user's public content is hidden from everyone but themselves
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS shadow_banned_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS shadow_ban_reason TEXT,
+    ADD COLUMN IF NOT EXISTS shadow_banned_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_shadow_banned ON users(shadow_banned_at DESC) WHERE shadow_banned_at IS NOT NULL;
@@ -0,0 +1,15 @@
+-- ==========================================
+-- Roles and Legal Hold
+-- ==========================================
+-- Access tokens carry the user's role; admin routes require admin or super_admin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin', 'super_admin'));
+
+-- Accounts under legal hold cannot be deleted or anonymized until released.
+-- Holds are placed and released by super admins only.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS legal_hold_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS legal_hold_reason TEXT,
+    ADD COLUMN IF NOT EXISTS legal_hold_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_legal_hold ON users(legal_hold_at DESC) WHERE legal_hold_at IS NOT NULL;
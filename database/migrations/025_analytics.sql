@@ -0,0 +1,28 @@
+-- ==========================================
+-- Pseudonymized Analytics Events
+-- ==========================================
+-- Raw product events. subject is an HMAC of the user ID under a salt that
+-- rotates daily and is then discarded, so events cannot be traced back to a
+-- user and the same user cannot be linked across days.
+CREATE TABLE IF NOT EXISTS analytics_events (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    subject VARCHAR(64), -- NULL for anonymous events
+    properties JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_events_occurred ON analytics_events(occurred_at);
+
+-- Daily aggregates read by the analytics dashboards
+CREATE TABLE IF NOT EXISTS analytics_daily_metrics (
+    day DATE NOT NULL,
+    metric VARCHAR(100) NOT NULL,
+    dimension VARCHAR(100) NOT NULL DEFAULT '',
+    value BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (day, metric, dimension)
+);
+
+CREATE TRIGGER update_analytics_daily_metrics_updated_at BEFORE UPDATE ON analytics_daily_metrics
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
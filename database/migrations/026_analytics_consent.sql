@@ -0,0 +1,9 @@
+-- ==========================================
+-- Analytics Consent
+-- ==========================================
+ALTER TABLE users ADD COLUMN IF NOT EXISTS analytics_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN users.analytics_opt_out IS 'Drop the user''s behavioral events at ingestion and exclude them from experiments';
+
+-- Lets an opt-out remove the user's events that can still be matched
+CREATE INDEX IF NOT EXISTS idx_analytics_events_subject ON analytics_events(subject) WHERE subject IS NOT NULL;
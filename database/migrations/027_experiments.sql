@@ -0,0 +1,26 @@
+-- ==========================================
+-- Experiments (A/B Testing)
+-- ==========================================
+-- Users are bucketed deterministically from the experiment key and user ID,
+-- so assignments need no per-user storage. Exposures are recorded as
+-- pseudonymized analytics events.
+CREATE TABLE IF NOT EXISTS experiments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    key VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'running', 'stopped')),
+    variants TEXT[] NOT NULL,
+    weights INTEGER[] NOT NULL, -- relative weight of each variant, same order as variants
+    traffic_percent INTEGER NOT NULL DEFAULT 100 CHECK (traffic_percent BETWEEN 0 AND 100),
+    target_tiers TEXT[] NOT NULL DEFAULT '{}',   -- empty targets every tier
+    target_locales TEXT[] NOT NULL DEFAULT '{}', -- empty targets every locale
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (cardinality(variants) = cardinality(weights))
+);
+
+CREATE INDEX IF NOT EXISTS idx_experiments_running ON experiments(key) WHERE status = 'running';
+
+CREATE TRIGGER update_experiments_updated_at BEFORE UPDATE ON experiments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,23 @@
+-- ==========================================
+-- Client Error Reports
+-- ==========================================
+-- Frontend errors grouped by fingerprint. Only the latest sample of each group
+-- is kept; reports are anonymous.
+CREATE TABLE IF NOT EXISTS client_error_groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    fingerprint VARCHAR(64) NOT NULL UNIQUE,
+    error_type VARCHAR(100),
+    message TEXT NOT NULL,
+    release VARCHAR(50), -- release of the latest report
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'ignored')),
+    occurrences BIGINT NOT NULL DEFAULT 1,
+    last_sample JSONB NOT NULL DEFAULT '{}', -- stack, url, user_agent and breadcrumbs of the latest report
+    first_seen_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_error_groups_status ON client_error_groups(status, last_seen_at DESC);
+
+CREATE TRIGGER update_client_error_groups_updated_at BEFORE UPDATE ON client_error_groups
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+-- ==========================================
+-- API Key Request Signing
+-- ==========================================
+-- Replay-protected public API routes require an HMAC over the method, path,
+-- timestamp, nonce and body, keyed by a per-key signing secret that is never
+-- sent on the wire. The secret is stored envelope-encrypted because the server
+-- needs it to verify signatures.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS signing_secret TEXT;
+
+COMMENT ON COLUMN api_keys.signing_secret IS 'Encrypted HMAC secret for request signatures; NULL for keys issued before signing';
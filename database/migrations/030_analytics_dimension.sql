@@ -0,0 +1,6 @@
+-- ==========================================
+-- Wider Analytics Dimensions
+-- ==========================================
+-- Experiment exposure dimensions are "<key>:<variant>": up to 100 + 1 + 50
+-- characters, which overflowed VARCHAR(100) and failed the whole aggregation.
+ALTER TABLE analytics_daily_metrics ALTER COLUMN dimension TYPE VARCHAR(160);
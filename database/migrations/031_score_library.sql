@@ -0,0 +1,8 @@
+-- ==========================================
+-- Score Library Pagination
+-- ==========================================
+-- GET /api/v1/scores pages a user's library by keyset on (sort key, id), so
+-- each sort order needs an index that starts with user_id. Most practiced is
+-- ordered by learning_progress, which is already unique on (user_id, score_id).
+CREATE INDEX IF NOT EXISTS idx_scores_library_recent ON scores(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_scores_library_title ON scores(user_id, LOWER(title), id);
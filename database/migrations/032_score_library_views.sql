@@ -0,0 +1,18 @@
+-- ==========================================
+-- Saved Score Library Views
+-- ==========================================
+-- A view is a named set of GET /api/v1/scores filters (models.LibraryFilters
+-- as JSON). Pinned views are returned with the first page of the library.
+CREATE TABLE IF NOT EXISTS score_library_views (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    pinned BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TRIGGER update_score_library_views_updated_at BEFORE UPDATE ON score_library_views
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,8 @@
+-- ==========================================
+-- Score Fingerings
+-- ==========================================
+-- Per-note string and fret choices for a score's transcribed notes, as a JSON
+-- array of {"note": <index into transcription_data.notes>, "string": <1 =
+-- lowest string>, "fret": <from the capo>}. Notes without an entry are
+-- unfingered.
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS fingerings JSONB NOT NULL DEFAULT '[]';
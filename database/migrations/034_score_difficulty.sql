@@ -0,0 +1,26 @@
+-- ==========================================
+-- Score Difficulty Estimation
+-- ==========================================
+-- difficulty_level stays the value searches and recommendations use. It is
+-- the creator's override when set, otherwise the estimate, and a level set
+-- directly only lasts until the score is first estimated. A score needs a new
+-- estimate when it has changed since the last one.
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS difficulty_estimated INTEGER CHECK (difficulty_estimated BETWEEN 1 AND 10);
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS difficulty_override INTEGER CHECK (difficulty_override BETWEEN 1 AND 10);
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS difficulty_factors JSONB;
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS difficulty_estimated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE OR REPLACE FUNCTION set_score_difficulty_level()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.difficulty_level = COALESCE(NEW.difficulty_override, NEW.difficulty_estimated, NEW.difficulty_level);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS set_scores_difficulty_level ON scores;
+CREATE TRIGGER set_scores_difficulty_level BEFORE INSERT OR UPDATE ON scores
+    FOR EACH ROW EXECUTE FUNCTION set_score_difficulty_level();
+
+CREATE INDEX IF NOT EXISTS idx_scores_difficulty_stale ON scores(updated_at)
+    WHERE difficulty_estimated_at IS NULL OR difficulty_estimated_at < updated_at;
@@ -0,0 +1,33 @@
+-- ==========================================
+-- Score Licensing
+-- ==========================================
+-- Rights information a score needs before it can be made public. Covers
+-- credit the original composer; licensed arrangements name the publisher and
+-- the license. Scores made public before this migration keep their visibility
+-- and show up as unlicensed in the admin licensing report.
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS license_type VARCHAR(20)
+    CHECK (license_type IN ('original', 'cover', 'public_domain', 'licensed'));
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS license_composer VARCHAR(255);
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS license_publisher VARCHAR(255);
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS license_reference VARCHAR(255);
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS license_notes TEXT;
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS license_updated_at TIMESTAMP WITH TIME ZONE;
+
+-- Applies to every writer of scores, not only this service
+CREATE OR REPLACE FUNCTION require_score_license()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.is_public AND NEW.license_type IS NULL
+       AND (TG_OP = 'INSERT' OR OLD.is_public IS NOT TRUE OR OLD.license_type IS NOT NULL) THEN
+        RAISE EXCEPTION 'score % needs license information to be public', NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS require_scores_license ON scores;
+CREATE TRIGGER require_scores_license BEFORE INSERT OR UPDATE OF is_public, license_type ON scores
+    FOR EACH ROW EXECUTE FUNCTION require_score_license();
+
+CREATE INDEX IF NOT EXISTS idx_scores_public_license ON scores(license_type, created_at DESC) WHERE is_public = true;
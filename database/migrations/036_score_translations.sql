@@ -0,0 +1,18 @@
+-- ==========================================
+-- Score Translations
+-- ==========================================
+-- A score's title and description in other locales. Locales are lowercase
+-- BCP 47 tags ("ja", "pt-br"); public score endpoints pick one from the
+-- client's Accept-Language and fall back to the original.
+CREATE TABLE IF NOT EXISTS score_translations (
+    score_id UUID NOT NULL REFERENCES scores(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (score_id, locale)
+);
+
+CREATE TRIGGER update_score_translations_updated_at BEFORE UPDATE ON score_translations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
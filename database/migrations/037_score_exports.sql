@@ -0,0 +1,25 @@
+-- ==========================================
+-- Score Exports
+-- ==========================================
+-- Bulk exports of a user's scores as a ZIP of the MusicXML and MIDI stored on
+-- each score, built by the build-score-exports job. The archive is kept until
+-- expires_at and downloaded through a signed link; the job then clears it.
+CREATE TABLE IF NOT EXISTS score_exports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score_ids UUID[] NOT NULL,
+    formats TEXT[] NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'expired')),
+    archive BYTEA,
+    size_bytes BIGINT,
+    skipped JSONB NOT NULL DEFAULT '[]',
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_score_exports_user ON score_exports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_score_exports_pending ON score_exports(created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_score_exports_expiry ON score_exports(expires_at) WHERE status = 'completed';
@@ -0,0 +1,13 @@
+-- ==========================================
+-- Transcription Queue
+-- ==========================================
+-- Workers claim pending jobs by priority, which follows the owner's tier,
+-- then in submission order, while the owner runs fewer jobs than the tier's
+-- concurrency cap allows.
+UPDATE transcription_jobs SET priority = 0 WHERE priority IS NULL;
+ALTER TABLE transcription_jobs ALTER COLUMN priority SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transcription_jobs_queue ON transcription_jobs(priority DESC, created_at, id)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_transcription_jobs_running ON transcription_jobs(user_id)
+    WHERE status = 'processing';
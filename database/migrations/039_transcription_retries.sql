@@ -0,0 +1,18 @@
+-- ==========================================
+-- Transcription Retries and Dead Letters
+-- ==========================================
+-- Failures are classified as transient (network or resource errors and
+-- worker crashes) or permanent (bad audio, unsupported format). Transient
+-- failures go back to pending until next_attempt_at, with backoff, until the
+-- job runs out of attempts. Jobs that fail for good are dead-lettered for
+-- admins to inspect and requeue. A processing job whose worker stops sending
+-- heartbeats is treated as a worker crash.
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS failure_class VARCHAR(30)
+    CHECK (failure_class IN ('transient', 'worker_crash', 'bad_audio', 'unsupported_format'));
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_transcription_jobs_dead_letter ON transcription_jobs(dead_lettered_at DESC)
+    WHERE dead_lettered_at IS NOT NULL;
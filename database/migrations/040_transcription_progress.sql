@@ -0,0 +1,10 @@
+-- ==========================================
+-- Transcription Progress
+-- ==========================================
+-- Workers report the stage a job is in and how far through it they are.
+-- progress stays the overall percentage, with each stage covering a fixed
+-- part of it. Both are cleared when a job is claimed again.
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS stage VARCHAR(20)
+    CHECK (stage IN ('downloading', 'separating', 'transcribing', 'rendering'));
+ALTER TABLE transcription_jobs ADD COLUMN IF NOT EXISTS stage_progress INTEGER
+    CHECK (stage_progress >= 0 AND stage_progress <= 100);
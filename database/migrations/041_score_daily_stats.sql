@@ -0,0 +1,19 @@
+-- ==========================================
+-- Creator Analytics
+-- ==========================================
+-- Daily snapshots of each public score's running totals, taken by the
+-- snapshot-score-stats job. A day's activity is the difference from the
+-- previous snapshot; the counters themselves are kept by the score service.
+CREATE TABLE IF NOT EXISTS score_daily_stats (
+    score_id UUID NOT NULL REFERENCES scores(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    favorites BIGINT NOT NULL DEFAULT 0,
+    downloads BIGINT NOT NULL DEFAULT 0,
+    learners BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (score_id, day)
+);
+
+CREATE TRIGGER update_score_daily_stats_updated_at BEFORE UPDATE ON score_daily_stats
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
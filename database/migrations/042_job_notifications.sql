@@ -0,0 +1,9 @@
+-- ==========================================
+-- Job Completion Notifications
+-- ==========================================
+-- How users hear that a transcription or score export finished: an email
+-- with a link to the result, and optionally a POST to their own webhook,
+-- signed with a per-user secret stored as an encryption envelope.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS job_notify_email BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS job_webhook_url TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS job_webhook_secret TEXT;
@@ -0,0 +1,8 @@
+-- ==========================================
+-- Storage Limits From Tiers
+-- ==========================================
+-- Storage limits come from the tier capabilities table in user-service, so
+-- they follow plan changes. The column is no longer written or read there;
+-- check_subscription_limits still compares against it and is not called by
+-- user-service.
+COMMENT ON COLUMN users.storage_limit_mb IS 'Unused: the limit is the subscription tier''s storage_limit_mb (GET /internal/v1/tiers)';
@@ -0,0 +1,9 @@
+-- ==========================================
+-- Score Trash
+-- ==========================================
+-- Deleted scores stay in the trash, hidden everywhere but the trash listing,
+-- until the purge-score-trash job removes them once their tier's retention
+-- period has passed. Scores of accounts under legal hold are never purged.
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_scores_trash ON scores(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
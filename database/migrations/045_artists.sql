@@ -0,0 +1,102 @@
+-- ==========================================
+-- Artists
+-- ==========================================
+-- An artist is the performer a score transcribes, distinct from the user who
+-- uploaded it. Scores keep their free-text artist; a trigger resolves it to an
+-- artist through the alias table, creating the artist on first use, so every
+-- writer of scores links them. Merging duplicate artists moves their aliases,
+-- so later scores under a merged name land on the surviving artist.
+CREATE OR REPLACE FUNCTION artist_name_key(artist_name TEXT)
+RETURNS TEXT AS $$
+    SELECT NULLIF(LOWER(regexp_replace(btrim(artist_name), '\s+', ' ', 'g')), '')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE TABLE IF NOT EXISTS artists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    bio TEXT,
+    image_url VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS artist_aliases (
+    name_key VARCHAR(255) PRIMARY KEY,
+    artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_artist_aliases_artist ON artist_aliases(artist_id);
+
+CREATE TABLE IF NOT EXISTS artist_follows (
+    artist_id UUID NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (artist_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_artist_follows_user ON artist_follows(user_id, created_at DESC);
+
+CREATE TRIGGER update_artists_updated_at BEFORE UPDATE ON artists
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE scores ADD COLUMN IF NOT EXISTS artist_id UUID REFERENCES artists(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_scores_artist_id ON scores(artist_id, created_at DESC) WHERE artist_id IS NOT NULL;
+
+-- Returns the artist for a name, creating it with that spelling if needed.
+-- The advisory lock keeps concurrent writers from creating the same artist.
+CREATE OR REPLACE FUNCTION resolve_artist(artist_name TEXT)
+RETURNS UUID AS $$
+DECLARE
+    key TEXT := artist_name_key(artist_name);
+    found UUID;
+BEGIN
+    IF key IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT artist_id INTO found FROM artist_aliases WHERE name_key = key;
+    IF found IS NOT NULL THEN
+        RETURN found;
+    END IF;
+
+    PERFORM pg_advisory_xact_lock(hashtext('artist:' || key));
+    SELECT artist_id INTO found FROM artist_aliases WHERE name_key = key;
+    IF found IS NULL THEN
+        INSERT INTO artists (name) VALUES (btrim(artist_name)) RETURNING id INTO found;
+        INSERT INTO artist_aliases (name_key, artist_id) VALUES (key, found);
+    END IF;
+    RETURN found;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION link_score_artist()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.artist IS DISTINCT FROM OLD.artist THEN
+        NEW.artist_id := resolve_artist(NEW.artist);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS link_scores_artist ON scores;
+CREATE TRIGGER link_scores_artist BEFORE INSERT OR UPDATE OF artist ON scores
+    FOR EACH ROW EXECUTE FUNCTION link_score_artist();
+
+-- Link existing scores without touching their updated_at, which would
+-- queue every score for difficulty re-estimation
+INSERT INTO artists (name)
+SELECT DISTINCT ON (artist_name_key(artist)) btrim(artist)
+FROM scores s WHERE artist_name_key(artist) IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM artist_aliases aa WHERE aa.name_key = artist_name_key(s.artist))
+ORDER BY artist_name_key(artist), created_at;
+
+INSERT INTO artist_aliases (name_key, artist_id)
+SELECT artist_name_key(name), id FROM artists
+ON CONFLICT (name_key) DO NOTHING;
+
+ALTER TABLE scores DISABLE TRIGGER update_scores_updated_at;
+UPDATE scores s SET artist_id = aa.artist_id
+FROM artist_aliases aa WHERE aa.name_key = artist_name_key(s.artist);
+ALTER TABLE scores ENABLE TRIGGER update_scores_updated_at;
@@ -0,0 +1,31 @@
+-- ==========================================
+-- Albums
+-- ==========================================
+-- A user's grouping of their uploaded tracks into an album or release, with
+-- an ordered track list, cover art and release metadata. A score belongs to
+-- at most one album. Deleting an album leaves its scores in the library.
+CREATE TABLE IF NOT EXISTS albums (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    artist VARCHAR(255),
+    release_date DATE,
+    label VARCHAR(255),
+    cover_url VARCHAR(500),
+    description TEXT,
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_albums_user ON albums(user_id, created_at DESC);
+
+CREATE TRIGGER update_albums_updated_at BEFORE UPDATE ON albums
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS album_tracks (
+    album_id UUID NOT NULL REFERENCES albums(id) ON DELETE CASCADE,
+    score_id UUID NOT NULL UNIQUE REFERENCES scores(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    PRIMARY KEY (album_id, position)
+);
@@ -0,0 +1,21 @@
+-- ==========================================
+-- Score Download Counts
+-- ==========================================
+-- Downloads of shared scores now bump download_count. Counter updates must
+-- not touch updated_at, which marks a score as edited, so scores get their
+-- own updated_at function that leaves it alone when only the view, like or
+-- download counters changed.
+CREATE OR REPLACE FUNCTION update_scores_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF to_jsonb(NEW) - ARRAY['view_count', 'like_count', 'download_count', 'updated_at']
+       IS DISTINCT FROM to_jsonb(OLD) - ARRAY['view_count', 'like_count', 'download_count', 'updated_at'] THEN
+        NEW.updated_at = CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS update_scores_updated_at ON scores;
+CREATE TRIGGER update_scores_updated_at BEFORE UPDATE ON scores
+    FOR EACH ROW EXECUTE FUNCTION update_scores_updated_at_column();
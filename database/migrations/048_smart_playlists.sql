@@ -0,0 +1,20 @@
+-- ==========================================
+-- Smart Playlists
+-- ==========================================
+-- A smart playlist is a named set of library rules (models.LibraryFilters as
+-- JSON, including relative dates such as added_within_days) evaluated on
+-- every read, so it updates itself as the library changes.
+CREATE TABLE IF NOT EXISTS smart_playlists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    rules JSONB NOT NULL DEFAULT '{}',
+    max_scores INTEGER NOT NULL DEFAULT 100 CHECK (max_scores BETWEEN 1 AND 500),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TRIGGER update_smart_playlists_updated_at BEFORE UPDATE ON smart_playlists
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();